@@ -0,0 +1,80 @@
+// Command nuvin-srv runs the local backend that the nuvin frontend talks to.
+package main
+
+import (
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+	srvhttp "github.com/marschhuynh/nuvin-srv/internal/http"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8787", "listen address")
+	proxyConfig := flag.String("proxy-config", "", "JSON file persisting proxy routes across restarts")
+	secretsFile := flag.String("secrets-file", "", "file persisting secrets encrypted at rest (needs a master secret)")
+	secretsKeyCmd := flag.String("secrets-key-cmd", "", "command printing the master secret (e.g. an OS keychain helper); NUVIN_MASTER_SECRET is used otherwise")
+	noAuth := flag.Bool("no-auth", false, "disable authentication (development only; anyone who can reach the port can run commands)")
+	fetchAllowPrivate := flag.Bool("fetch-allow-private", false, "let /fetch reach loopback, private, and link-local addresses (blocked by default; adjustable at runtime via /fetch/policy)")
+	authTokenFile := flag.String("auth-token-file", "", "file to write the local auth token to for nuvin-ui (default: print to the log)")
+	maxBodyBytes := flag.Int64("max-body-bytes", srvhttp.DefaultLimits().MaxBodyBytes, "maximum request body size in bytes (0 = unlimited)")
+	readHeaderTimeout := flag.Duration("read-header-timeout", srvhttp.DefaultLimits().ReadHeaderTimeout, "how long a client may take to send request headers")
+	idleTimeout := flag.Duration("idle-timeout", srvhttp.DefaultLimits().IdleTimeout, "how long idle keep-alive connections are kept open")
+	flag.Parse()
+
+	srv := srvhttp.NewServer()
+	srv.AccessLog = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	srv.Limits.MaxBodyBytes = *maxBodyBytes
+	srv.Limits.ReadHeaderTimeout = *readHeaderTimeout
+	srv.Limits.IdleTimeout = *idleTimeout
+	if *proxyConfig != "" {
+		if err := srv.Proxy.SetConfigPath(*proxyConfig); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *secretsFile != "" {
+		master := os.Getenv("NUVIN_MASTER_SECRET")
+		if *secretsKeyCmd != "" {
+			out, err := exec.Command("sh", "-c", *secretsKeyCmd).Output()
+			if err != nil {
+				log.Fatalf("secrets key command: %v", err)
+			}
+			master = strings.TrimSpace(string(out))
+		}
+		if err := srv.Secrets.Unlock(master); err != nil {
+			log.Fatal(err)
+		}
+		if err := srv.Secrets.SetPath(*secretsFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if !*fetchAllowPrivate {
+		if err := srv.FetchProxy.SetAccessPolicy(fetchproxy.AccessPolicy{BlockPrivate: true}); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *noAuth {
+		log.Printf("warning: authentication disabled")
+	} else {
+		srv.Auth.Enable(true)
+		if *authTokenFile != "" {
+			if err := srv.Auth.WriteTokenFile(*authTokenFile); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			token, err := srv.Auth.LocalToken()
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("local auth token: %s", token)
+		}
+	}
+	log.Printf("nuvin-srv listening on %s", *addr)
+	if err := srv.ListenAndServe(*addr); err != nil {
+		log.Fatal(err)
+	}
+}
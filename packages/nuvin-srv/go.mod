@@ -0,0 +1,15 @@
+module github.com/marschhuynh/nuvin-srv
+
+go 1.21
+
+require (
+	github.com/andybalholm/brotli v1.1.0
+	github.com/gorilla/websocket v1.5.1
+)
+
+require (
+	github.com/creack/pty v1.1.21
+	golang.org/x/net v0.17.0
+)
+
+require golang.org/x/text v0.13.0 // indirect
@@ -0,0 +1,133 @@
+// Package auth holds the credentials nuvin-srv itself accepts: API keys
+// for programmatic clients and the roles enforced on sensitive endpoints.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keyPrefix marks nuvin-srv API keys so they're recognizable in
+// Authorization headers and secret scanners.
+const keyPrefix = "nvn_"
+
+// APIKey is the metadata for an issued key; the key itself is only ever
+// returned once, at issuance, and stored hashed.
+type APIKey struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Scopes     []string  `json:"scopes,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// KeyStore issues and verifies API keys. Keys are stored as SHA-256
+// hashes, so a leaked store doesn't leak usable credentials.
+type KeyStore struct {
+	mu   sync.Mutex
+	keys map[string]*APIKey // keyed by hash
+
+	now func() time.Time
+}
+
+// NewKeyStore returns an empty key store.
+func NewKeyStore() *KeyStore {
+	return &KeyStore{keys: make(map[string]*APIKey), now: time.Now}
+}
+
+// hashKey derives the storage hash for a key string.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue creates a key with the given name and scopes, returning the
+// plaintext key exactly once.
+func (s *KeyStore) Issue(name string, scopes []string) (string, APIKey, error) {
+	if name == "" {
+		return "", APIKey{}, fmt.Errorf("key name is required")
+	}
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", APIKey{}, err
+	}
+	plaintext := keyPrefix + hex.EncodeToString(buf)
+	idBuf := make([]byte, 8)
+	if _, err := rand.Read(idBuf); err != nil {
+		return "", APIKey{}, err
+	}
+	meta := &APIKey{
+		ID:        hex.EncodeToString(idBuf),
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: s.now(),
+	}
+	s.mu.Lock()
+	s.keys[hashKey(plaintext)] = meta
+	s.mu.Unlock()
+	return plaintext, *meta, nil
+}
+
+// Verify checks a presented key, recording the use. The comparison runs on
+// hashes, in constant time.
+func (s *KeyStore) Verify(key string) (APIKey, bool) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return APIKey{}, false
+	}
+	hash := hashKey(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for stored, meta := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			meta.LastUsedAt = s.now()
+			return *meta, true
+		}
+	}
+	return APIKey{}, false
+}
+
+// List returns the issued keys' metadata, sorted by creation time.
+func (s *KeyStore) List() []APIKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]APIKey, 0, len(s.keys))
+	for _, meta := range s.keys {
+		out = append(out, *meta)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Revoke deletes a key by ID, reporting whether one was removed.
+func (s *KeyStore) Revoke(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, meta := range s.keys {
+		if meta.ID == id {
+			delete(s.keys, hash)
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the key carries the scope; a key with no scopes
+// is unrestricted.
+func (k APIKey) HasScope(scope string) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIssueVerifyRevoke(t *testing.T) {
+	s := NewKeyStore()
+	plaintext, meta, err := s.Issue("ci", []string{"proxy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(plaintext, "nvn_") || meta.ID == "" || meta.Name != "ci" {
+		t.Fatalf("plaintext = %q, meta = %+v", plaintext, meta)
+	}
+
+	key, ok := s.Verify(plaintext)
+	if !ok || key.ID != meta.ID {
+		t.Fatalf("Verify = %+v %v", key, ok)
+	}
+	if list := s.List(); len(list) != 1 || list[0].LastUsedAt.IsZero() {
+		t.Errorf("list = %+v", list)
+	}
+
+	if _, ok := s.Verify("nvn_wrong"); ok {
+		t.Error("bogus key verified")
+	}
+	if _, ok := s.Verify("sk-not-ours"); ok {
+		t.Error("foreign token verified")
+	}
+
+	if !s.Revoke(meta.ID) {
+		t.Fatal("revoke failed")
+	}
+	if _, ok := s.Verify(plaintext); ok {
+		t.Error("revoked key still verifies")
+	}
+	if s.Revoke(meta.ID) {
+		t.Error("second revoke reported success")
+	}
+}
+
+func TestIssueRequiresName(t *testing.T) {
+	if _, _, err := NewKeyStore().Issue("", nil); err == nil {
+		t.Fatal("nameless key issued")
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	scoped := APIKey{Scopes: []string{"proxy", "usage"}}
+	if !scoped.HasScope("proxy") || scoped.HasScope("execute") {
+		t.Error("scoped key checks wrong")
+	}
+	if !(APIKey{}).HasScope("anything") {
+		t.Error("unrestricted key denied")
+	}
+}
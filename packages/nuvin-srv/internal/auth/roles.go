@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Roles, from most to least privileged. Operators can use the proxy and
+// tooling but not run commands or change server config; viewers only read
+// status endpoints.
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+	RoleViewer   = "viewer"
+)
+
+// rank orders roles for the at-least comparison.
+var rank = map[string]int{RoleViewer: 0, RoleOperator: 1, RoleAdmin: 2}
+
+// RoleAllows reports whether a subject holding role may use an endpoint
+// requiring required.
+func RoleAllows(role, required string) bool {
+	return rank[role] >= rank[required]
+}
+
+// Assignment pairs a subject with its role, for listings.
+type Assignment struct {
+	Subject string `json:"subject"`
+	Role    string `json:"role"`
+}
+
+// Roles maps subjects to roles. Subjects without an assignment get the
+// default role, which starts as admin so a fresh local install keeps full
+// access until someone tightens it.
+type Roles struct {
+	mu          sync.Mutex
+	assignments map[string]string
+	defaultRole string
+}
+
+// NewRoles returns a role table with an admin default.
+func NewRoles() *Roles {
+	return &Roles{assignments: make(map[string]string), defaultRole: RoleAdmin}
+}
+
+func validRole(role string) error {
+	if _, ok := rank[role]; !ok {
+		return fmt.Errorf("unknown role %q", role)
+	}
+	return nil
+}
+
+// Assign sets a subject's role.
+func (r *Roles) Assign(subject, role string) error {
+	if subject == "" {
+		return fmt.Errorf("subject is required")
+	}
+	if err := validRole(role); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.assignments[subject] = role
+	return nil
+}
+
+// Unassign removes a subject's explicit role, returning it to the default.
+func (r *Roles) Unassign(subject string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.assignments, subject)
+}
+
+// Role returns the role a subject holds.
+func (r *Roles) Role(subject string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if role, ok := r.assignments[subject]; ok {
+		return role
+	}
+	return r.defaultRole
+}
+
+// SetDefault changes the role unassigned subjects hold.
+func (r *Roles) SetDefault(role string) error {
+	if err := validRole(role); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultRole = role
+	return nil
+}
+
+// Default returns the default role.
+func (r *Roles) Default() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.defaultRole
+}
+
+// List returns all explicit assignments sorted by subject.
+func (r *Roles) List() []Assignment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Assignment, 0, len(r.assignments))
+	for subject, role := range r.assignments {
+		out = append(out, Assignment{Subject: subject, Role: role})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Subject < out[j].Subject })
+	return out
+}
@@ -0,0 +1,55 @@
+package auth
+
+import "testing"
+
+func TestRoleAllows(t *testing.T) {
+	cases := []struct {
+		role, required string
+		want           bool
+	}{
+		{RoleAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleViewer, true},
+		{RoleOperator, RoleAdmin, false},
+		{RoleOperator, RoleOperator, true},
+		{RoleViewer, RoleOperator, false},
+		{RoleViewer, RoleViewer, true},
+	}
+	for _, c := range cases {
+		if got := RoleAllows(c.role, c.required); got != c.want {
+			t.Errorf("RoleAllows(%q, %q) = %v, want %v", c.role, c.required, got, c.want)
+		}
+	}
+}
+
+func TestRolesAssignments(t *testing.T) {
+	r := NewRoles()
+	if r.Role("anyone") != RoleAdmin {
+		t.Fatalf("default role = %q, want admin on a fresh install", r.Role("anyone"))
+	}
+	if err := r.SetDefault(RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Assign("key:ci", RoleOperator); err != nil {
+		t.Fatal(err)
+	}
+	if r.Role("key:ci") != RoleOperator || r.Role("anyone") != RoleViewer {
+		t.Errorf("roles = %q / %q", r.Role("key:ci"), r.Role("anyone"))
+	}
+	if list := r.List(); len(list) != 1 || list[0].Subject != "key:ci" {
+		t.Errorf("list = %+v", list)
+	}
+	r.Unassign("key:ci")
+	if r.Role("key:ci") != RoleViewer {
+		t.Errorf("role after unassign = %q", r.Role("key:ci"))
+	}
+
+	if err := r.Assign("x", "superuser"); err == nil {
+		t.Error("unknown role accepted")
+	}
+	if err := r.Assign("", RoleAdmin); err == nil {
+		t.Error("empty subject accepted")
+	}
+	if err := r.SetDefault("root"); err == nil {
+		t.Error("unknown default role accepted")
+	}
+}
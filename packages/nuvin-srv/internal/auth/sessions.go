@@ -0,0 +1,264 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// accessTTL is how long a session access token stays valid.
+const accessTTL = 12 * time.Hour
+
+// Session is an authenticated UI session. The tokens are only returned
+// from Login and Refresh; listings use SessionInfo so they never leak.
+type Session struct {
+	ID           string    `json:"id"`
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Subject      string    `json:"subject"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// SessionInfo is the token-free view of a session used by listings.
+type SessionInfo struct {
+	ID          string    `json:"id"`
+	Subject     string    `json:"subject"`
+	CreatedAt   time.Time `json:"createdAt"`
+	RefreshedAt time.Time `json:"refreshedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type sessionState struct {
+	Session
+	refreshedAt time.Time
+}
+
+// Authenticator implements the local loopback handshake: the server
+// generates a bootstrap token on startup and writes it to a file only the
+// local user can read; nuvin-ui reads that file and exchanges it at
+// /auth/login for a session. When disabled (the dev escape hatch) requests
+// without credentials pass through as before.
+//
+// Refresh tokens rotate: each Refresh retires the presented token and
+// issues a new pair. Presenting a retired token means it leaked (or the
+// UI lost a race with an attacker), so the whole session it belonged to
+// is revoked.
+type Authenticator struct {
+	mu         sync.Mutex
+	enabled    bool
+	localToken string
+	sessions   map[string]*sessionState // keyed by session ID
+	byAccess   map[string]string        // access token -> session ID
+	byRefresh  map[string]string        // current refresh token -> session ID
+	retired    map[string]string        // retired refresh token -> session ID
+
+	now func() time.Time
+}
+
+// NewAuthenticator returns a disabled authenticator; main enables it
+// unless the operator opts out.
+func NewAuthenticator() *Authenticator {
+	return &Authenticator{
+		sessions:  make(map[string]*sessionState),
+		byAccess:  make(map[string]string),
+		byRefresh: make(map[string]string),
+		retired:   make(map[string]string),
+		now:       time.Now,
+	}
+}
+
+// Enable turns enforcement on or off.
+func (a *Authenticator) Enable(on bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enabled = on
+}
+
+// Enabled reports whether authentication is enforced.
+func (a *Authenticator) Enabled() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.enabled
+}
+
+// LocalToken returns the bootstrap token, generating it on first use.
+func (a *Authenticator) LocalToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.localTokenLocked()
+}
+
+func (a *Authenticator) localTokenLocked() (string, error) {
+	if a.localToken == "" {
+		tok, err := randomHex(32)
+		if err != nil {
+			return "", err
+		}
+		a.localToken = tok
+	}
+	return a.localToken, nil
+}
+
+// WriteTokenFile writes the bootstrap token to path, readable only by the
+// local user, for nuvin-ui to pick up.
+func (a *Authenticator) WriteTokenFile(path string) error {
+	tok, err := a.LocalToken()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(tok+"\n"), 0o600); err != nil {
+		return fmt.Errorf("writing auth token file: %w", err)
+	}
+	return nil
+}
+
+// Login exchanges the bootstrap token for a session.
+func (a *Authenticator) Login(localToken string) (Session, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	expected, err := a.localTokenLocked()
+	if err != nil {
+		return Session{}, err
+	}
+	if localToken == "" || localToken != expected {
+		return Session{}, fmt.Errorf("invalid local token")
+	}
+	id, err := randomHex(8)
+	if err != nil {
+		return Session{}, err
+	}
+	sess := &sessionState{Session: Session{
+		ID:        id,
+		Subject:   "local",
+		CreatedAt: a.now(),
+	}}
+	if err := a.rotateLocked(sess); err != nil {
+		return Session{}, err
+	}
+	a.sessions[id] = sess
+	return sess.Session, nil
+}
+
+// Refresh rotates a session's token pair. The presented refresh token is
+// retired; presenting an already-retired token revokes the session it
+// belonged to, since a token being used twice means two parties hold it.
+func (a *Authenticator) Refresh(refreshToken string) (Session, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if id, ok := a.retired[refreshToken]; ok {
+		a.revokeLocked(id)
+		return Session{}, fmt.Errorf("refresh token reuse detected; session revoked")
+	}
+	id, ok := a.byRefresh[refreshToken]
+	if !ok {
+		return Session{}, fmt.Errorf("invalid refresh token")
+	}
+	sess := a.sessions[id]
+	if err := a.rotateLocked(sess); err != nil {
+		return Session{}, err
+	}
+	sess.refreshedAt = a.now()
+	return sess.Session, nil
+}
+
+// rotateLocked issues a fresh token pair for sess, retiring any previous
+// refresh token and invalidating the previous access token.
+func (a *Authenticator) rotateLocked(sess *sessionState) error {
+	access, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+	refresh, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+	if sess.AccessToken != "" {
+		delete(a.byAccess, sess.AccessToken)
+	}
+	if sess.RefreshToken != "" {
+		delete(a.byRefresh, sess.RefreshToken)
+		a.retired[sess.RefreshToken] = sess.ID
+	}
+	sess.AccessToken = access
+	sess.RefreshToken = refresh
+	sess.ExpiresAt = a.now().Add(accessTTL)
+	a.byAccess[access] = sess.ID
+	a.byRefresh[refresh] = sess.ID
+	return nil
+}
+
+// Verify checks an access token and returns the session's subject.
+func (a *Authenticator) Verify(accessToken string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	id, ok := a.byAccess[accessToken]
+	if !ok {
+		return "", false
+	}
+	sess := a.sessions[id]
+	if a.now().After(sess.ExpiresAt) {
+		return "", false
+	}
+	return sess.Subject, true
+}
+
+// Sessions lists active sessions, newest first; subject filters to one
+// user when non-empty.
+func (a *Authenticator) Sessions(subject string) []SessionInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]SessionInfo, 0, len(a.sessions))
+	for _, sess := range a.sessions {
+		if subject != "" && sess.Subject != subject {
+			continue
+		}
+		out = append(out, SessionInfo{
+			ID:          sess.ID,
+			Subject:     sess.Subject,
+			CreatedAt:   sess.CreatedAt,
+			RefreshedAt: sess.refreshedAt,
+			ExpiresAt:   sess.ExpiresAt,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// Revoke invalidates a session and every token it ever held.
+func (a *Authenticator) Revoke(id string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.sessions[id]; !ok {
+		return false
+	}
+	a.revokeLocked(id)
+	return true
+}
+
+func (a *Authenticator) revokeLocked(id string) {
+	sess, ok := a.sessions[id]
+	if !ok {
+		return
+	}
+	delete(a.sessions, id)
+	delete(a.byAccess, sess.AccessToken)
+	delete(a.byRefresh, sess.RefreshToken)
+	for tok, owner := range a.retired {
+		if owner == id {
+			delete(a.retired, tok)
+		}
+	}
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAuthenticatorLogin(t *testing.T) {
+	a := NewAuthenticator()
+	local, err := a.LocalToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Login("wrong"); err == nil {
+		t.Error("wrong local token accepted")
+	}
+	if _, err := a.Login(""); err == nil {
+		t.Error("empty local token accepted")
+	}
+	sess, err := a.Login(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.AccessToken == "" || sess.RefreshToken == "" || sess.Subject != "local" {
+		t.Fatalf("session = %+v", sess)
+	}
+	if subject, ok := a.Verify(sess.AccessToken); !ok || subject != "local" {
+		t.Errorf("Verify = %q, %v", subject, ok)
+	}
+	if _, ok := a.Verify("bogus"); ok {
+		t.Error("bogus access token verified")
+	}
+}
+
+func TestAuthenticatorSessionExpiry(t *testing.T) {
+	a := NewAuthenticator()
+	base := time.Now()
+	a.now = func() time.Time { return base }
+	local, err := a.LocalToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := a.Login(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base = base.Add(accessTTL + time.Minute)
+	if _, ok := a.Verify(sess.AccessToken); ok {
+		t.Error("expired session verified")
+	}
+}
+
+func TestRefreshRotation(t *testing.T) {
+	a := NewAuthenticator()
+	local, err := a.LocalToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := a.Login(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := a.Refresh(sess.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.ID != sess.ID {
+		t.Errorf("rotated session ID = %q, want %q", rotated.ID, sess.ID)
+	}
+	if rotated.AccessToken == sess.AccessToken || rotated.RefreshToken == sess.RefreshToken {
+		t.Error("tokens not rotated")
+	}
+	if _, ok := a.Verify(sess.AccessToken); ok {
+		t.Error("old access token still valid after rotation")
+	}
+	if _, ok := a.Verify(rotated.AccessToken); !ok {
+		t.Error("new access token not valid")
+	}
+
+	// Replaying the retired refresh token revokes the whole session.
+	if _, err := a.Refresh(sess.RefreshToken); err == nil {
+		t.Fatal("retired refresh token accepted")
+	}
+	if _, ok := a.Verify(rotated.AccessToken); ok {
+		t.Error("session still valid after refresh token reuse")
+	}
+	if _, err := a.Refresh(rotated.RefreshToken); err == nil {
+		t.Error("refresh token of revoked session accepted")
+	}
+}
+
+func TestSessionsListAndRevoke(t *testing.T) {
+	a := NewAuthenticator()
+	local, err := a.LocalToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := a.Login(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := a.Login(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list := a.Sessions(""); len(list) != 2 {
+		t.Fatalf("sessions = %d, want 2", len(list))
+	}
+	if list := a.Sessions("nobody"); len(list) != 0 {
+		t.Errorf("sessions for unknown subject = %d, want 0", len(list))
+	}
+	if !a.Revoke(first.ID) {
+		t.Fatal("revoke of known session failed")
+	}
+	if a.Revoke(first.ID) {
+		t.Error("revoke of revoked session succeeded")
+	}
+	if _, ok := a.Verify(first.AccessToken); ok {
+		t.Error("revoked session's access token still valid")
+	}
+	if list := a.Sessions("local"); len(list) != 1 || list[0].ID != second.ID {
+		t.Errorf("sessions after revoke = %+v", list)
+	}
+}
+
+func TestWriteTokenFile(t *testing.T) {
+	a := NewAuthenticator()
+	path := filepath.Join(t.TempDir(), "token")
+	if err := a.WriteTokenFile(path); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("token file mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, _ := a.LocalToken()
+	if string(data) != local+"\n" {
+		t.Errorf("token file content = %q", data)
+	}
+}
@@ -0,0 +1,89 @@
+// Package breaker implements a per-host circuit breaker. After a run of
+// consecutive failures talking to a host the circuit opens and callers fail
+// fast instead of waiting on a dead upstream; once the cooldown elapses a
+// single probe request is let through, and its outcome closes or re-opens
+// the circuit.
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultThreshold is how many consecutive failures open a circuit.
+	DefaultThreshold = 5
+	// DefaultCooldown is how long an open circuit rejects requests before
+	// letting a probe through.
+	DefaultCooldown = 30 * time.Second
+)
+
+type hostState struct {
+	failures int
+	openedAt time.Time
+	// probing marks that the one half-open probe is already in flight.
+	probing bool
+}
+
+// Breaker tracks circuit state per upstream host.
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	hosts     map[string]*hostState
+	now       func() time.Time
+}
+
+// New returns a Breaker with the default threshold and cooldown.
+func New() *Breaker {
+	return &Breaker{
+		threshold: DefaultThreshold,
+		cooldown:  DefaultCooldown,
+		hosts:     make(map[string]*hostState),
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a request to host may proceed. When the circuit is
+// open it returns false along with how long to wait before retrying; once
+// the cooldown has elapsed exactly one caller is admitted as a probe.
+func (b *Breaker) Allow(host string) (ok bool, retryAfter time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st := b.hosts[host]
+	if st == nil || st.failures < b.threshold {
+		return true, 0
+	}
+	remaining := b.cooldown - b.now().Sub(st.openedAt)
+	if remaining <= 0 && !st.probing {
+		st.probing = true
+		return true, 0
+	}
+	if remaining <= 0 {
+		// A probe is already in flight; retry once its cooldown would elapse.
+		remaining = b.cooldown
+	}
+	return false, remaining
+}
+
+// Report records the outcome of a request to host. A success closes the
+// circuit; a failure counts toward opening it (or re-opens it after a
+// failed probe).
+func (b *Breaker) Report(host string, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		delete(b.hosts, host)
+		return
+	}
+	st := b.hosts[host]
+	if st == nil {
+		st = &hostState{}
+		b.hosts[host] = st
+	}
+	st.failures++
+	st.probing = false
+	if st.failures >= b.threshold {
+		st.openedAt = b.now()
+	}
+}
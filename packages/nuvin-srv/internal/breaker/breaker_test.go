@@ -0,0 +1,80 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New()
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < DefaultThreshold; i++ {
+		if ok, _ := b.Allow("api.example.com"); !ok {
+			t.Fatalf("request %d rejected before threshold", i+1)
+		}
+		b.Report("api.example.com", false)
+	}
+
+	ok, retryAfter := b.Allow("api.example.com")
+	if ok {
+		t.Fatal("request allowed with circuit open")
+	}
+	if retryAfter <= 0 {
+		t.Errorf("retryAfter = %v, want positive", retryAfter)
+	}
+
+	// Other hosts are unaffected.
+	if ok, _ := b.Allow("other.example.com"); !ok {
+		t.Error("circuit leaked across hosts")
+	}
+}
+
+func TestBreakerProbesAndCloses(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New()
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < DefaultThreshold; i++ {
+		b.Report("api.example.com", false)
+	}
+
+	// After the cooldown exactly one probe is admitted.
+	now = now.Add(DefaultCooldown)
+	if ok, _ := b.Allow("api.example.com"); !ok {
+		t.Fatal("probe rejected after cooldown")
+	}
+	if ok, _ := b.Allow("api.example.com"); ok {
+		t.Fatal("second request admitted while probe in flight")
+	}
+
+	// A successful probe closes the circuit.
+	b.Report("api.example.com", true)
+	if ok, _ := b.Allow("api.example.com"); !ok {
+		t.Error("request rejected after circuit closed")
+	}
+}
+
+func TestBreakerFailedProbeReopens(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New()
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < DefaultThreshold; i++ {
+		b.Report("api.example.com", false)
+	}
+	now = now.Add(DefaultCooldown)
+	if ok, _ := b.Allow("api.example.com"); !ok {
+		t.Fatal("probe rejected after cooldown")
+	}
+	b.Report("api.example.com", false)
+
+	if ok, _ := b.Allow("api.example.com"); ok {
+		t.Error("request allowed right after failed probe")
+	}
+	now = now.Add(DefaultCooldown)
+	if ok, _ := b.Allow("api.example.com"); !ok {
+		t.Error("next probe rejected after another cooldown")
+	}
+}
@@ -0,0 +1,95 @@
+// Package capture records proxied fetches so traffic can be inspected and
+// replayed later when debugging prompt/response regressions.
+package capture
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// Recording is one captured fetch round trip.
+type Recording struct {
+	ID         string                    `json:"id"`
+	RecordedAt time.Time                 `json:"recordedAt"`
+	Request    fetchproxy.FetchRequest   `json:"request"`
+	Response   *fetchproxy.FetchResponse `json:"response,omitempty"`
+	Error      string                    `json:"error,omitempty"`
+}
+
+// Store keeps recordings in memory, bounded to a fixed number of entries;
+// the oldest recordings are evicted first.
+type Store struct {
+	mu    sync.Mutex
+	limit int
+	order []string
+	byID  map[string]*Recording
+}
+
+// NewStore returns a Store keeping at most limit recordings (default 1000
+// when limit <= 0).
+func NewStore(limit int) *Store {
+	if limit <= 0 {
+		limit = 1000
+	}
+	return &Store{limit: limit, byID: make(map[string]*Recording)}
+}
+
+// RecordFetch stores one fetch round trip and returns its recording ID. It
+// matches the fetchproxy.CaptureFunc signature.
+func (s *Store) RecordFetch(req fetchproxy.FetchRequest, resp *fetchproxy.FetchResponse, fetchErr error) {
+	rec := &Recording{
+		ID:         newRecordingID(),
+		RecordedAt: time.Now().UTC(),
+		Request:    req,
+		Response:   resp,
+	}
+	if fetchErr != nil {
+		rec.Error = fetchErr.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byID[rec.ID] = rec
+	s.order = append(s.order, rec.ID)
+	for len(s.order) > s.limit {
+		delete(s.byID, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// Get returns the recording with the given ID.
+func (s *Store) Get(id string) (*Recording, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.byID[id]
+	return rec, ok
+}
+
+// List returns all recordings, newest first.
+func (s *Store) List() []*Recording {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*Recording, 0, len(s.order))
+	for i := len(s.order) - 1; i >= 0; i-- {
+		out = append(out, s.byID[s.order[i]])
+	}
+	return out
+}
+
+// Clear discards all recordings.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byID = make(map[string]*Recording)
+	s.order = nil
+}
+
+func newRecordingID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "rec-" + hex.EncodeToString(b)
+}
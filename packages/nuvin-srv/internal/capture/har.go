@@ -0,0 +1,170 @@
+package capture
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// HAR is the top-level HTTP Archive 1.2 document. Only the fields browser
+// devtools actually read are populated.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+}
+
+type HARRequest struct {
+	Method      string     `json:"method"`
+	URL         string     `json:"url"`
+	HTTPVersion string     `json:"httpVersion"`
+	Headers     []HARNV    `json:"headers"`
+	QueryString []HARNV    `json:"queryString"`
+	PostData    *HARPost   `json:"postData,omitempty"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int        `json:"bodySize"`
+	Cookies     []struct{} `json:"cookies"`
+}
+
+type HARResponse struct {
+	Status      int        `json:"status"`
+	StatusText  string     `json:"statusText"`
+	HTTPVersion string     `json:"httpVersion"`
+	Headers     []HARNV    `json:"headers"`
+	Content     HARContent `json:"content"`
+	RedirectURL string     `json:"redirectURL"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int        `json:"bodySize"`
+	Cookies     []struct{} `json:"cookies"`
+}
+
+type HARNV struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARPost struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// ExportHAR renders the store's recordings (oldest first, as HAR viewers
+// expect) as an HTTP Archive 1.2 document. Failed fetches have no response
+// and are skipped.
+func (s *Store) ExportHAR() *HAR {
+	recordings := s.List()
+	entries := make([]HAREntry, 0, len(recordings))
+	for i := len(recordings) - 1; i >= 0; i-- {
+		rec := recordings[i]
+		if rec.Response == nil {
+			continue
+		}
+		entries = append(entries, harEntry(rec))
+	}
+	return &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "nuvin-srv", Version: "1.0"},
+		Entries: entries,
+	}}
+}
+
+func harEntry(rec *Recording) HAREntry {
+	req := rec.Request
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	harReq := HARRequest{
+		Method:      method,
+		URL:         req.URL,
+		HTTPVersion: "HTTP/1.1",
+		Headers:     nvPairs(req.Headers),
+		QueryString: []HARNV{},
+		HeadersSize: -1,
+		BodySize:    len(req.Body),
+		Cookies:     []struct{}{},
+	}
+	if req.Body != "" {
+		harReq.PostData = &HARPost{
+			MimeType: req.Headers["Content-Type"],
+			Text:     req.Body,
+		}
+	}
+
+	resp := rec.Response
+	content := HARContent{
+		Size:     len(resp.Body),
+		MimeType: resp.Headers["Content-Type"],
+		Text:     resp.Body,
+	}
+	if resp.BodyEncoding == fetchproxy.BodyEncodingBase64 {
+		content.Encoding = "base64"
+	}
+
+	return HAREntry{
+		StartedDateTime: rec.RecordedAt,
+		Request:         harReq,
+		Response: HARResponse{
+			Status:      resp.Status,
+			StatusText:  http.StatusText(resp.Status),
+			HTTPVersion: "HTTP/1.1",
+			Headers:     rawNVPairs(resp.RawHeaders),
+			Content:     content,
+			HeadersSize: -1,
+			BodySize:    len(resp.Body),
+			Cookies:     []struct{}{},
+		},
+		Timings: HARTimings{Send: -1, Wait: -1, Receive: -1},
+	}
+}
+
+func nvPairs(headers map[string]string) []HARNV {
+	out := make([]HARNV, 0, len(headers))
+	for k, v := range headers {
+		out = append(out, HARNV{Name: k, Value: v})
+	}
+	return out
+}
+
+func rawNVPairs(headers map[string][]string) []HARNV {
+	out := make([]HARNV, 0, len(headers))
+	for k, vs := range headers {
+		for _, v := range vs {
+			out = append(out, HARNV{Name: k, Value: v})
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package capture
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+func TestExportHAR(t *testing.T) {
+	s := NewStore(0)
+	s.RecordFetch(
+		fetchproxy.FetchRequest{
+			URL:     "https://api.example.com/v1/chat",
+			Method:  "POST",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    `{"q":1}`,
+		},
+		&fetchproxy.FetchResponse{
+			Status:     200,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			RawHeaders: map[string][]string{"Set-Cookie": {"a=1", "b=2"}},
+			Body:       `{"ok":true}`,
+		},
+		nil,
+	)
+	// Failed fetches have no response to archive.
+	s.RecordFetch(fetchproxy.FetchRequest{URL: "https://down.example.com"}, nil, errors.New("dial failed"))
+
+	har := s.ExportHAR()
+	if har.Log.Version != "1.2" {
+		t.Errorf("version = %q", har.Log.Version)
+	}
+	if len(har.Log.Entries) != 1 {
+		t.Fatalf("entries = %d, want 1 (errored fetch skipped)", len(har.Log.Entries))
+	}
+
+	entry := har.Log.Entries[0]
+	if entry.Request.Method != "POST" || entry.Request.URL != "https://api.example.com/v1/chat" {
+		t.Errorf("request = %+v", entry.Request)
+	}
+	if entry.Request.PostData == nil || entry.Request.PostData.MimeType != "application/json" {
+		t.Errorf("postData = %+v", entry.Request.PostData)
+	}
+	if entry.Response.Status != 200 || entry.Response.StatusText != "OK" {
+		t.Errorf("response = %+v", entry.Response)
+	}
+	if len(entry.Response.Headers) != 2 {
+		t.Errorf("response headers = %+v, want both Set-Cookie values", entry.Response.Headers)
+	}
+	if entry.Response.Content.Text != `{"ok":true}` || entry.Response.Content.Encoding != "" {
+		t.Errorf("content = %+v", entry.Response.Content)
+	}
+}
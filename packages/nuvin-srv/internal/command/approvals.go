@@ -0,0 +1,184 @@
+package command
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Approval statuses.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalExpired  = "expired"
+)
+
+const defaultApprovalExpiry = 5 * time.Minute
+
+// ApprovalConfig selects which commands are parked for human approval.
+type ApprovalConfig struct {
+	// Patterns are path.Match patterns against the full command line; a
+	// matching command requires approval before it runs.
+	Patterns []string `json:"patterns"`
+	// ExpiryMs is how long a pending approval stays actionable; 0 means the
+	// default of 5 minutes.
+	ExpiryMs int `json:"expiryMs,omitempty"`
+}
+
+// Approval is one command parked for review. JobID is set once an approved
+// command has been handed to the job queue.
+type Approval struct {
+	ID        string      `json:"id"`
+	Request   ExecRequest `json:"request"`
+	Status    string      `json:"status"`
+	JobID     string      `json:"jobId,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+}
+
+// approvalStore keeps parked commands; expiry is applied lazily on access.
+type approvalStore struct {
+	mu      sync.Mutex
+	cfg     ApprovalConfig
+	pending map[string]*Approval
+	now     func() time.Time
+}
+
+func newApprovalStore() *approvalStore {
+	return &approvalStore{pending: make(map[string]*Approval), now: time.Now}
+}
+
+// SetApprovalConfig replaces the approval rules.
+func (r *Runner) SetApprovalConfig(cfg ApprovalConfig) {
+	r.approvals.mu.Lock()
+	defer r.approvals.mu.Unlock()
+	r.approvals.cfg = cfg
+}
+
+// ApprovalConfig reports the current approval rules.
+func (r *Runner) ApprovalConfig() ApprovalConfig {
+	r.approvals.mu.Lock()
+	defer r.approvals.mu.Unlock()
+	return r.approvals.cfg
+}
+
+// RequiresApproval reports whether the command matches an approval pattern.
+func (r *Runner) RequiresApproval(command string) bool {
+	r.approvals.mu.Lock()
+	defer r.approvals.mu.Unlock()
+	for _, p := range r.approvals.cfg.Patterns {
+		if ok, err := path.Match(p, command); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ParkForApproval records the request as pending and returns the approval.
+func (r *Runner) ParkForApproval(req ExecRequest) *Approval {
+	s := r.approvals
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	expiry := defaultApprovalExpiry
+	if s.cfg.ExpiryMs > 0 {
+		expiry = time.Duration(s.cfg.ExpiryMs) * time.Millisecond
+	}
+	a := &Approval{
+		ID:        "appr-" + hex.EncodeToString(buf),
+		Request:   req,
+		Status:    ApprovalPending,
+		CreatedAt: s.now().UTC(),
+		ExpiresAt: s.now().UTC().Add(expiry),
+	}
+	s.pending[a.ID] = a
+	return a
+}
+
+// expireLocked flips a stale pending approval to expired.
+func (s *approvalStore) expireLocked(a *Approval) {
+	if a.Status == ApprovalPending && s.now().After(a.ExpiresAt) {
+		a.Status = ApprovalExpired
+	}
+}
+
+// Approval returns a parked command by ID.
+func (r *Runner) Approval(id string) (*Approval, bool) {
+	s := r.approvals
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.pending[id]
+	if ok {
+		s.expireLocked(a)
+	}
+	return a, ok
+}
+
+// Approvals returns all parked commands, newest first.
+func (r *Runner) Approvals() []*Approval {
+	s := r.approvals
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*Approval, 0, len(s.pending))
+	for _, a := range s.pending {
+		s.expireLocked(a)
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// Approve releases a pending command to the job queue and returns the job.
+func (r *Runner) Approve(id string) (Job, error) {
+	s := r.approvals
+	s.mu.Lock()
+	a, ok := s.pending[id]
+	if ok {
+		s.expireLocked(a)
+	}
+	if !ok {
+		s.mu.Unlock()
+		return Job{}, fmt.Errorf("unknown approval: %s", id)
+	}
+	if a.Status != ApprovalPending {
+		s.mu.Unlock()
+		return Job{}, fmt.Errorf("approval %s is %s", id, a.Status)
+	}
+	req := a.Request
+	s.mu.Unlock()
+
+	job, err := r.Submit(req)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		return Job{}, err
+	}
+	a.Status = ApprovalApproved
+	a.JobID = job.ID
+	return job, nil
+}
+
+// Reject marks a pending command as rejected.
+func (r *Runner) Reject(id string) error {
+	s := r.approvals
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.pending[id]
+	if ok {
+		s.expireLocked(a)
+	}
+	if !ok {
+		return fmt.Errorf("unknown approval: %s", id)
+	}
+	if a.Status != ApprovalPending {
+		return fmt.Errorf("approval %s is %s", id, a.Status)
+	}
+	a.Status = ApprovalRejected
+	return nil
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApprovalFlowApprove(t *testing.T) {
+	r := NewRunner()
+	r.SetApprovalConfig(ApprovalConfig{Patterns: []string{"*apt-get*"}})
+
+	if !r.RequiresApproval("sudo apt-get install jq") {
+		t.Fatal("matching command should require approval")
+	}
+	if r.RequiresApproval("echo hi") {
+		t.Fatal("non-matching command should not require approval")
+	}
+
+	a := r.ParkForApproval(ExecRequest{Command: "echo pretend-apt-get"})
+	if a.Status != ApprovalPending {
+		t.Fatalf("status = %q", a.Status)
+	}
+
+	job, err := r.Approve(a.ID)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	got, _ := r.Approval(a.ID)
+	if got.Status != ApprovalApproved || got.JobID != job.ID {
+		t.Fatalf("approval = %+v, want approved with job %s", got, job.ID)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		j, _ := r.Job(job.ID)
+		if j.Status == JobDone {
+			if !strings.Contains(j.Result.Stdout, "pretend-apt-get") {
+				t.Fatalf("job stdout = %q", j.Result.Stdout)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish: %+v", j)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := r.Approve(a.ID); err == nil {
+		t.Fatal("second approve should fail")
+	}
+}
+
+func TestApprovalRejectAndExpiry(t *testing.T) {
+	r := NewRunner()
+	a := r.ParkForApproval(ExecRequest{Command: "echo risky"})
+	if err := r.Reject(a.ID); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if got, _ := r.Approval(a.ID); got.Status != ApprovalRejected {
+		t.Fatalf("status = %q", got.Status)
+	}
+
+	now := time.Now()
+	r.approvals.now = func() time.Time { return now }
+	b := r.ParkForApproval(ExecRequest{Command: "echo stale"})
+	r.approvals.now = func() time.Time { return now.Add(10 * time.Minute) }
+	if got, _ := r.Approval(b.ID); got.Status != ApprovalExpired {
+		t.Fatalf("status = %q, want expired", got.Status)
+	}
+	if _, err := r.Approve(b.ID); err == nil {
+		t.Fatal("expired approval should not be approvable")
+	}
+}
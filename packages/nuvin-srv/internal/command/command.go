@@ -0,0 +1,279 @@
+// Package command executes shell commands on behalf of the frontend, with
+// buffered and streaming variants.
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/envprofile"
+	"github.com/marschhuynh/nuvin-srv/internal/proctree"
+)
+
+// ExecRequest describes one command execution.
+type ExecRequest struct {
+	// Command is the shell command line, run through the platform shell.
+	Command string `json:"command"`
+	// Cwd is the working directory; empty means the server's.
+	Cwd string `json:"cwd,omitempty"`
+	// Shell overrides the platform default shell (e.g. "bash", "pwsh",
+	// "cmd" on Windows).
+	Shell string `json:"shell,omitempty"`
+	// Env entries are appended to the server's environment.
+	Env map[string]string `json:"env,omitempty"`
+	// Profile names a stored environment profile whose env vars, PATH
+	// additions, and default working directory are applied first.
+	Profile string `json:"profile,omitempty"`
+	// TimeoutMs kills the command after the given duration; 0 means the
+	// default of 5 minutes.
+	TimeoutMs int `json:"timeoutMs,omitempty"`
+	// Stdin is fed to the child process; StdinEncoding "base64" lets
+	// binary input survive JSON transport.
+	Stdin         string `json:"stdin,omitempty"`
+	StdinEncoding string `json:"stdinEncoding,omitempty"`
+	// Limits caps CPU time, memory, process count, and captured output.
+	Limits *Limits `json:"limits,omitempty"`
+	// DryRun returns the execution plan (argv split, policy decision,
+	// resolved binary, effective cwd) without running the command.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Async enqueues the command as a background job instead of waiting;
+	// the handler responds with the job ID.
+	Async bool `json:"async,omitempty"`
+	// Origin identifies the caller for per-origin policy; handlers fill it
+	// from the request subject.
+	Origin string `json:"-"`
+}
+
+// ExecResult is the buffered outcome of a completed command.
+type ExecResult struct {
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	ExitCode   int    `json:"exitCode"`
+	DurationMs int64  `json:"durationMs"`
+	TimedOut   bool   `json:"timedOut,omitempty"`
+	// LimitExceeded names the resource limit that killed the command
+	// ("cpu", "memory", "output"), so callers can tell a limit kill from an
+	// ordinary failure.
+	LimitExceeded string `json:"limitExceeded,omitempty"`
+}
+
+const defaultTimeout = 5 * time.Minute
+
+// Runner executes commands. It is shared by the buffered and streaming
+// handlers so policy and accounting hooks apply to both.
+type Runner struct {
+	// Policy gates every execution and records the decision audit log.
+	Policy *PolicyEngine
+
+	// Profiles holds named environment profiles referenced by ExecRequest.Profile.
+	Profiles *envprofile.Store
+
+	// scheduler caps concurrent executions globally and per origin.
+	scheduler *scheduler
+
+	// approvals parks risky commands for human review before execution.
+	approvals *approvalStore
+
+	mu         sync.Mutex
+	jobs       map[string]*Job
+	jobCancels map[string]context.CancelFunc
+	sandbox    *Sandbox
+	stdinPipes map[string]io.WriteCloser
+	procs      map[string]*os.Process
+	sigAllow   []string
+	history    []HistoryEntry
+}
+
+// NewRunner returns a Runner with the default policy.
+func NewRunner() *Runner {
+	return &Runner{
+		Policy:    NewPolicyEngine(),
+		Profiles:  envprofile.NewStore(),
+		scheduler: newScheduler(),
+		approvals: newApprovalStore(),
+	}
+}
+
+// Execute runs the command and returns its buffered output.
+func (r *Runner) Execute(req ExecRequest) (*ExecResult, error) {
+	if err := r.checkCommand(req); err != nil {
+		return nil, err
+	}
+	return r.execute(context.Background(), req, "")
+}
+
+// execute runs an already policy-checked command. Cancelling parent kills
+// the command (jobs use this for /jobs/:id/cancel). A non-empty procID makes
+// the running process addressable through Signal.
+func (r *Runner) execute(parent context.Context, req ExecRequest, procID string) (result *ExecResult, err error) {
+	if err := r.scheduler.acquire(req.Origin); err != nil {
+		return nil, err
+	}
+	defer r.scheduler.release(req.Origin)
+
+	audited := req
+	defer func() { r.recordHistory(audited, result, err) }()
+
+	if req, err = r.applyProfile(req); err != nil {
+		return nil, err
+	}
+
+	sb := r.SandboxConfig()
+	if sb != nil {
+		var err error
+		if req, err = sb.confine(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Limits != nil {
+		req.Command = req.Limits.shellPrefix() + req.Command
+	}
+
+	ctx, cancel := execContext(parent, req)
+	defer cancel()
+
+	cmd, err := shellCommand(ctx, req, sb)
+	if err != nil {
+		return nil, err
+	}
+	// Timeouts and cancellation kill the whole process tree, not just the
+	// shell; otherwise background children keep running (and keep the output
+	// pipes open) after the command is nominally dead.
+	tree := proctree.Setup(cmd)
+	cmd.Cancel = tree.Kill
+
+	type output interface {
+		io.Writer
+		String() string
+	}
+	var stdout, stderr output = &bytes.Buffer{}, &bytes.Buffer{}
+	var capped []*limitedBuffer
+	if req.Limits != nil && req.Limits.MaxOutputBytes > 0 {
+		so := &limitedBuffer{limit: req.Limits.MaxOutputBytes, cancel: cancel}
+		se := &limitedBuffer{limit: req.Limits.MaxOutputBytes, cancel: cancel}
+		stdout, stderr = so, se
+		capped = []*limitedBuffer{so, se}
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if req.Stdin != "" {
+		stdin, err := stdinReader(req)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Stdin = stdin
+	}
+
+	start := time.Now()
+	if err = cmd.Start(); err == nil {
+		tree.Adopt()
+		defer tree.Close()
+		if procID != "" {
+			r.registerProcess(procID, cmd.Process)
+			defer r.unregisterProcess(procID)
+		}
+		err = cmd.Wait()
+	}
+	result = &ExecResult{
+		Stdout:     stdout.String(),
+		Stderr:     stderr.String(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+	}
+	for _, buf := range capped {
+		if buf.Exceeded() {
+			result.LimitExceeded = LimitOutput
+		}
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+			if result.LimitExceeded == "" && req.Limits != nil {
+				code := result.ExitCode
+				// When the shell itself is killed (tight loops run in the
+				// shell), the signal arrives directly instead of as 128+sig.
+				if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+					code = 128 + int(status.Signal())
+				}
+				result.LimitExceeded = req.Limits.classifyExit(code)
+			}
+			return result, nil
+		}
+		if result.TimedOut || result.LimitExceeded != "" {
+			result.ExitCode = -1
+			return result, nil
+		}
+		return nil, fmt.Errorf("executing command: %w", err)
+	}
+	return result, nil
+}
+
+func (r *Runner) checkCommand(req ExecRequest) error {
+	if strings.TrimSpace(req.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if d := r.Policy.Check(req.Origin, req.Command); !d.Allowed {
+		return fmt.Errorf("command rejected by policy: %s", d.Reason)
+	}
+	return nil
+}
+
+// stdinReader decodes the request's stdin payload.
+func stdinReader(req ExecRequest) (io.Reader, error) {
+	if req.StdinEncoding == "base64" {
+		raw, err := base64.StdEncoding.DecodeString(req.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("decoding base64 stdin: %w", err)
+		}
+		return bytes.NewReader(raw), nil
+	}
+	return strings.NewReader(req.Stdin), nil
+}
+
+func execContext(parent context.Context, req ExecRequest) (context.Context, context.CancelFunc) {
+	timeout := defaultTimeout
+	if req.TimeoutMs > 0 {
+		timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+func shellCommand(ctx context.Context, req ExecRequest, sb *Sandbox) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	if sb != nil {
+		cmd = sb.wrap(ctx, req)
+	}
+	if cmd == nil {
+		shell, args, err := platformShell(req.Shell)
+		if err != nil {
+			return nil, err
+		}
+		cmd = exec.CommandContext(ctx, shell, append(args, req.Command)...)
+		configureShellCmd(cmd, shell, req.Command)
+		cmd.Dir = req.Cwd
+	}
+	// Without a wait delay, an orphaned child holding the output pipe keeps
+	// Wait blocked long after the shell itself was killed.
+	cmd.WaitDelay = time.Second
+	if len(req.Env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range req.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+	return cmd, nil
+}
@@ -0,0 +1,140 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecuteBuffersOutput(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{Command: "echo out; echo err >&2; exit 3"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if strings.TrimSpace(result.Stdout) != "out" {
+		t.Errorf("Stdout = %q", result.Stdout)
+	}
+	if strings.TrimSpace(result.Stderr) != "err" {
+		t.Errorf("Stderr = %q", result.Stderr)
+	}
+	if result.ExitCode != 3 {
+		t.Errorf("ExitCode = %d, want 3", result.ExitCode)
+	}
+}
+
+// Regression: stderr must be captured even when the command exits zero, and
+// the two streams are capped independently rather than sharing one budget.
+func TestStderrCapturedOnSuccess(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{Command: "echo warning >&2; echo done"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+	}
+	if strings.TrimSpace(result.Stderr) != "warning" {
+		t.Errorf("Stderr = %q, want warning from successful command", result.Stderr)
+	}
+	if strings.TrimSpace(result.Stdout) != "done" {
+		t.Errorf("Stdout = %q", result.Stdout)
+	}
+}
+
+func TestOutputStreamsCappedIndependently(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{
+		Command: "printf abc >&2; printf 1234567890",
+		Limits:  &Limits{MaxOutputBytes: 5},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Stdout != "12345" {
+		t.Errorf("Stdout = %q, want capped to 5 bytes", result.Stdout)
+	}
+	if result.Stderr != "abc" {
+		t.Errorf("Stderr = %q, want full stderr under its own cap", result.Stderr)
+	}
+	if result.LimitExceeded != LimitOutput {
+		t.Errorf("LimitExceeded = %q, want %q", result.LimitExceeded, LimitOutput)
+	}
+}
+
+func TestExecuteRejectsDangerousCommand(t *testing.T) {
+	r := NewRunner()
+	if _, err := r.Execute(ExecRequest{Command: "rm -rf / --no-preserve-root"}); err == nil {
+		t.Error("dangerous command accepted")
+	}
+	if _, err := r.Execute(ExecRequest{Command: "  "}); err == nil {
+		t.Error("empty command accepted")
+	}
+}
+
+func TestExecuteShellOverride(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{Command: "echo $0", Shell: "bash"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if strings.TrimSpace(result.Stdout) != "bash" {
+		t.Errorf("shell = %q, want bash", strings.TrimSpace(result.Stdout))
+	}
+
+	if _, err := r.Execute(ExecRequest{Command: "echo hi", Shell: "fakeshell"}); err == nil {
+		t.Error("unsupported shell accepted")
+	}
+}
+
+func TestExecuteTimeout(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{Command: "sleep 5", TimeoutMs: 50})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !result.TimedOut {
+		t.Error("TimedOut = false, want true")
+	}
+}
+
+func TestExecuteStreamEmitsLinesAndExit(t *testing.T) {
+	r := NewRunner()
+	var events []StreamEvent
+	err := r.ExecuteStream(ExecRequest{Command: "echo one; echo two; echo oops >&2"}, func(ev StreamEvent) {
+		events = append(events, ev)
+	})
+	if err != nil {
+		t.Fatalf("ExecuteStream: %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("events = %+v, want start, 3 lines, exit", events)
+	}
+	if events[0].Type != "start" {
+		t.Errorf("first event = %+v, want start", events[0])
+	}
+
+	var stdout []string
+	var stderrSeen, exitSeen bool
+	for _, ev := range events {
+		switch ev.Type {
+		case "stdout":
+			stdout = append(stdout, ev.Data)
+		case "stderr":
+			stderrSeen = ev.Data == "oops"
+		case "exit":
+			exitSeen = true
+			if ev.ExitCode != 0 {
+				t.Errorf("exit code = %d", ev.ExitCode)
+			}
+		}
+	}
+	if len(stdout) != 2 || stdout[0] != "one" || stdout[1] != "two" {
+		t.Errorf("stdout lines = %v", stdout)
+	}
+	if !stderrSeen || !exitSeen {
+		t.Errorf("stderrSeen = %v, exitSeen = %v", stderrSeen, exitSeen)
+	}
+	if events[len(events)-1].Type != "exit" {
+		t.Error("exit event is not last")
+	}
+}
@@ -0,0 +1,122 @@
+package command
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ExecPlan is the would-be execution of a command: what would run, where,
+// with what environment, and whether policy allows it. Nothing is executed.
+type ExecPlan struct {
+	Command string   `json:"command"`
+	Shell   string   `json:"shell"`
+	Argv    []string `json:"argv"`
+	// Binary is the resolved path of the first word, when it resolves.
+	Binary  string            `json:"binary,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Policy  Decision          `json:"policy"`
+	Sandbox *Sandbox          `json:"sandbox,omitempty"`
+	Limits  *Limits           `json:"limits,omitempty"`
+	// Warnings flag problems that would fail the real execution.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Plan validates the request and returns its execution plan without running
+// anything, so the UI can show a confirmation prompt.
+func (r *Runner) Plan(req ExecRequest) (*ExecPlan, error) {
+	if strings.TrimSpace(req.Command) == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+
+	plan := &ExecPlan{
+		Command: req.Command,
+		Cwd:     req.Cwd,
+		Env:     req.Env,
+		Limits:  req.Limits,
+		Policy:  r.Policy.Check(req.Origin, req.Command),
+	}
+
+	shell, _, err := platformShell(req.Shell)
+	if err != nil {
+		plan.Warnings = append(plan.Warnings, err.Error())
+	} else {
+		plan.Shell = shell
+	}
+
+	argv, err := splitShellWords(req.Command)
+	if err != nil {
+		plan.Warnings = append(plan.Warnings, err.Error())
+	}
+	plan.Argv = argv
+	if len(argv) > 0 {
+		if binary, err := exec.LookPath(argv[0]); err == nil {
+			plan.Binary = binary
+		} else {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("binary %q not found in PATH", argv[0]))
+		}
+	}
+
+	if sb := r.SandboxConfig(); sb != nil {
+		plan.Sandbox = sb
+		confined, err := sb.confine(req)
+		if err != nil {
+			plan.Warnings = append(plan.Warnings, err.Error())
+		} else {
+			plan.Cwd = confined.Cwd
+		}
+	}
+	return plan, nil
+}
+
+// splitShellWords splits a command line into words honoring single quotes,
+// double quotes, and backslash escapes. It does not expand variables or
+// globs — the plan shows words as the shell would group them.
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	var inWord bool
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				cur.WriteRune(runes[i])
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\':
+			if i+1 < len(runes) {
+				i++
+				cur.WriteRune(runes[i])
+				inWord = true
+			}
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return words, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanDescribesExecution(t *testing.T) {
+	r := NewRunner()
+	plan, err := r.Plan(ExecRequest{Command: `echo "hello world" twice`, Cwd: "/tmp"})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if !plan.Policy.Allowed {
+		t.Errorf("policy = %+v", plan.Policy)
+	}
+	if want := []string{"echo", "hello world", "twice"}; !reflect.DeepEqual(plan.Argv, want) {
+		t.Errorf("Argv = %v, want %v", plan.Argv, want)
+	}
+	if plan.Binary == "" {
+		t.Error("echo binary not resolved")
+	}
+	if plan.Shell == "" || plan.Cwd != "/tmp" {
+		t.Errorf("plan = %+v", plan)
+	}
+}
+
+func TestPlanFlagsProblems(t *testing.T) {
+	r := NewRunner()
+
+	plan, err := r.Plan(ExecRequest{Command: "rm -rf / all"})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if plan.Policy.Allowed {
+		t.Error("plan reports denied command as allowed")
+	}
+
+	plan, err = r.Plan(ExecRequest{Command: "definitely-not-a-binary --flag"})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if len(plan.Warnings) == 0 {
+		t.Error("missing warning for unresolvable binary")
+	}
+}
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`a b c`, []string{"a", "b", "c"}},
+		{`a 'b c' d`, []string{"a", "b c", "d"}},
+		{`a "b \" c"`, []string{"a", `b " c`}},
+		{`a\ b`, []string{"a b"}},
+		{``, nil},
+	}
+	for _, tt := range tests {
+		got, err := splitShellWords(tt.in)
+		if err != nil {
+			t.Errorf("splitShellWords(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitShellWords(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := splitShellWords(`echo "unterminated`); err == nil {
+		t.Error("unterminated quote accepted")
+	}
+}
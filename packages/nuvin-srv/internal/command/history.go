@@ -0,0 +1,102 @@
+package command
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// historyLimit bounds the in-memory history; the oldest entries are evicted
+// first.
+const historyLimit = 1000
+
+// historyOutputCap truncates stored outputs so one noisy build cannot eat
+// the whole history budget.
+const historyOutputCap = 4096
+
+// HistoryEntry is one audited command invocation.
+type HistoryEntry struct {
+	ID         string    `json:"id"`
+	Command    string    `json:"command"`
+	Cwd        string    `json:"cwd,omitempty"`
+	Origin     string    `json:"origin,omitempty"`
+	ExitCode   int       `json:"exitCode"`
+	DurationMs int64     `json:"durationMs"`
+	Stdout     string    `json:"stdout,omitempty"`
+	Stderr     string    `json:"stderr,omitempty"`
+	Truncated  bool      `json:"truncated,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// recordHistory appends one invocation to the audit history.
+func (r *Runner) recordHistory(req ExecRequest, result *ExecResult, execErr error) {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	entry := HistoryEntry{
+		ID:        "hist-" + hex.EncodeToString(buf),
+		Command:   req.Command,
+		Cwd:       req.Cwd,
+		Origin:    req.Origin,
+		CreatedAt: time.Now().UTC(),
+	}
+	if result != nil {
+		entry.ExitCode = result.ExitCode
+		entry.DurationMs = result.DurationMs
+		entry.Stdout, entry.Truncated = truncateOutput(result.Stdout)
+		var stderrTruncated bool
+		entry.Stderr, stderrTruncated = truncateOutput(result.Stderr)
+		entry.Truncated = entry.Truncated || stderrTruncated
+	}
+	if execErr != nil {
+		entry.Error = execErr.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = append(r.history, entry)
+	if len(r.history) > historyLimit {
+		r.history = r.history[len(r.history)-historyLimit:]
+	}
+}
+
+func truncateOutput(s string) (string, bool) {
+	if len(s) > historyOutputCap {
+		return s[:historyOutputCap], true
+	}
+	return s, false
+}
+
+// History returns recorded invocations, newest first, optionally filtered by
+// a case-insensitive substring match on the command line. limit <= 0 means
+// all entries.
+func (r *Runner) History(query string, limit int) []HistoryEntry {
+	query = strings.ToLower(query)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]HistoryEntry, 0, len(r.history))
+	for i := len(r.history) - 1; i >= 0; i-- {
+		entry := r.history[i]
+		if query != "" && !strings.Contains(strings.ToLower(entry.Command), query) {
+			continue
+		}
+		out = append(out, entry)
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// HistoryEntry returns one recorded invocation by ID.
+func (r *Runner) HistoryEntry(id string) (HistoryEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range r.history {
+		if entry.ID == id {
+			return entry, true
+		}
+	}
+	return HistoryEntry{}, false
+}
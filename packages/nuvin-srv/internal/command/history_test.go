@@ -0,0 +1,59 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHistoryRecordsInvocations(t *testing.T) {
+	r := NewRunner()
+	r.Execute(ExecRequest{Command: "echo first", Origin: "ui"})
+	r.Execute(ExecRequest{Command: "false"})
+
+	history := r.History("", 0)
+	if len(history) != 2 {
+		t.Fatalf("history = %d entries, want 2", len(history))
+	}
+	// Newest first.
+	if history[0].Command != "false" || history[0].ExitCode != 1 {
+		t.Errorf("history[0] = %+v", history[0])
+	}
+	if history[1].Command != "echo first" || history[1].Origin != "ui" {
+		t.Errorf("history[1] = %+v", history[1])
+	}
+	if strings.TrimSpace(history[1].Stdout) != "first" {
+		t.Errorf("stdout = %q", history[1].Stdout)
+	}
+
+	entry, ok := r.HistoryEntry(history[0].ID)
+	if !ok || entry.Command != "false" {
+		t.Errorf("HistoryEntry = %+v, %v", entry, ok)
+	}
+}
+
+func TestHistorySearchAndLimit(t *testing.T) {
+	r := NewRunner()
+	r.Execute(ExecRequest{Command: "echo alpha"})
+	r.Execute(ExecRequest{Command: "echo beta"})
+	r.Execute(ExecRequest{Command: "echo alpha again"})
+
+	matches := r.History("ALPHA", 0)
+	if len(matches) != 2 {
+		t.Fatalf("search matches = %d, want 2", len(matches))
+	}
+	if limited := r.History("alpha", 1); len(limited) != 1 || limited[0].Command != "echo alpha again" {
+		t.Errorf("limited = %+v", limited)
+	}
+}
+
+func TestHistoryTruncatesLongOutput(t *testing.T) {
+	r := NewRunner()
+	r.Execute(ExecRequest{Command: "head -c 10000 /dev/zero | tr '\\0' 'x'"})
+	history := r.History("", 1)
+	if len(history) != 1 {
+		t.Fatal("missing history entry")
+	}
+	if !history[0].Truncated || len(history[0].Stdout) != historyOutputCap {
+		t.Errorf("Truncated = %v, len = %d", history[0].Truncated, len(history[0].Stdout))
+	}
+}
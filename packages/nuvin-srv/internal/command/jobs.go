@@ -0,0 +1,133 @@
+package command
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"time"
+)
+
+// Job statuses.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobDone      = "done"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Job is one asynchronous command execution. Result is populated once the
+// job reaches a terminal status. Accessors return copies: the runner keeps
+// mutating its own record until the job finishes.
+type Job struct {
+	ID         string      `json:"id"`
+	Request    ExecRequest `json:"request"`
+	Status     string      `json:"status"`
+	Result     *ExecResult `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+}
+
+// Submit enqueues the command as a background job and returns immediately.
+// The command still passes the policy check up front so a denied command
+// fails fast instead of surfacing later in job status.
+func (r *Runner) Submit(req ExecRequest) (Job, error) {
+	if err := r.checkCommand(req); err != nil {
+		return Job{}, err
+	}
+
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &Job{
+		ID:        "job-" + hex.EncodeToString(buf),
+		Request:   req,
+		Status:    JobQueued,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	r.mu.Lock()
+	if r.jobs == nil {
+		r.jobs = make(map[string]*Job)
+	}
+	if r.jobCancels == nil {
+		r.jobCancels = make(map[string]context.CancelFunc)
+	}
+	r.jobs[job.ID] = job
+	r.jobCancels[job.ID] = cancel
+	r.mu.Unlock()
+
+	go r.runJob(ctx, job)
+	return *job, nil
+}
+
+func (r *Runner) runJob(ctx context.Context, job *Job) {
+	r.mu.Lock()
+	if job.Status == JobCancelled {
+		r.mu.Unlock()
+		return
+	}
+	job.Status = JobRunning
+	r.mu.Unlock()
+
+	result, err := r.execute(ctx, job.Request, job.ID)
+
+	now := time.Now().UTC()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.jobCancels, job.ID)
+	job.FinishedAt = &now
+	if job.Status == JobCancelled {
+		return
+	}
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		return
+	}
+	job.Status = JobDone
+	job.Result = result
+}
+
+// Job returns a snapshot of a job by ID.
+func (r *Runner) Job(id string) (Job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j, ok := r.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *j, true
+}
+
+// Jobs returns snapshots of all jobs, newest first.
+func (r *Runner) Jobs() []Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Job, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		out = append(out, *j)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// CancelJob stops a queued or running job.
+func (r *Runner) CancelJob(id string) bool {
+	r.mu.Lock()
+	job, ok := r.jobs[id]
+	var cancel context.CancelFunc
+	if ok && (job.Status == JobQueued || job.Status == JobRunning) {
+		job.Status = JobCancelled
+		cancel = r.jobCancels[id]
+	} else {
+		ok = false
+	}
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	return ok
+}
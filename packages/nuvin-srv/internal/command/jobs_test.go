@@ -0,0 +1,79 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func waitForStatus(t *testing.T, r *Runner, id string, statuses ...string) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, ok := r.Job(id)
+		if !ok {
+			t.Fatalf("job %s disappeared", id)
+		}
+		for _, s := range statuses {
+			if job.Status == s {
+				return job
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	job, _ := r.Job(id)
+	t.Fatalf("job %s stuck in status %q, want one of %v", id, job.Status, statuses)
+	return Job{}
+}
+
+func TestSubmitRunsJobToCompletion(t *testing.T) {
+	r := NewRunner()
+	job, err := r.Submit(ExecRequest{Command: "echo from-job"})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if job.Status != JobQueued && job.Status != JobRunning {
+		t.Errorf("initial status = %q", job.Status)
+	}
+
+	done := waitForStatus(t, r, job.ID, JobDone)
+	if done.Result == nil || strings.TrimSpace(done.Result.Stdout) != "from-job" {
+		t.Errorf("result = %+v", done.Result)
+	}
+	if done.FinishedAt == nil {
+		t.Error("FinishedAt not set")
+	}
+
+	jobs := r.Jobs()
+	if len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Errorf("Jobs = %+v", jobs)
+	}
+}
+
+func TestSubmitRejectsDeniedCommand(t *testing.T) {
+	r := NewRunner()
+	if _, err := r.Submit(ExecRequest{Command: "rm -rf / now"}); err == nil {
+		t.Error("Submit accepted a policy-denied command")
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	r := NewRunner()
+	job, err := r.Submit(ExecRequest{Command: "sleep 30"})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	waitForStatus(t, r, job.ID, JobRunning)
+
+	if !r.CancelJob(job.ID) {
+		t.Fatal("CancelJob returned false for running job")
+	}
+	got := waitForStatus(t, r, job.ID, JobCancelled)
+	if got.Status != JobCancelled {
+		t.Errorf("status = %q", got.Status)
+	}
+	// A finished job cannot be cancelled again.
+	if r.CancelJob(job.ID) {
+		t.Error("CancelJob returned true for cancelled job")
+	}
+}
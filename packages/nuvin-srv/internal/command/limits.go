@@ -0,0 +1,110 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Limit kinds reported in ExecResult.LimitExceeded.
+const (
+	LimitCPU    = "cpu"
+	LimitMemory = "memory"
+	LimitOutput = "output"
+)
+
+// Limits caps the resources one command may consume. CPU, memory, and
+// process limits are applied with ulimit inside the shell so they bind the
+// whole process tree; the output limit is enforced server-side.
+type Limits struct {
+	CPUSeconds     int   `json:"cpuSeconds,omitempty"`
+	MemoryBytes    int64 `json:"memoryBytes,omitempty"`
+	MaxProcesses   int   `json:"maxProcesses,omitempty"`
+	MaxOutputBytes int64 `json:"maxOutputBytes,omitempty"`
+}
+
+// shellPrefix renders the ulimit invocations prepended to the command line.
+func (l *Limits) shellPrefix() string {
+	var parts []string
+	if l.CPUSeconds > 0 {
+		parts = append(parts, "ulimit -t "+strconv.Itoa(l.CPUSeconds))
+	}
+	if l.MemoryBytes > 0 {
+		// ulimit -v takes KiB.
+		parts = append(parts, "ulimit -v "+strconv.FormatInt((l.MemoryBytes+1023)/1024, 10))
+	}
+	if l.MaxProcesses > 0 {
+		parts = append(parts, "ulimit -u "+strconv.Itoa(l.MaxProcesses))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, "; ") + "; "
+}
+
+// classifyExit maps an exit code to the limit that likely killed the
+// command: the shell reports a child killed by SIGXCPU or SIGKILL as
+// 128+signal, and SIGKILL with a memory cap set almost always means the
+// allocator hit the cap.
+func (l *Limits) classifyExit(exitCode int) string {
+	const sigKill, sigXCPU = 9, 24
+	switch exitCode {
+	case 128 + sigXCPU:
+		if l.CPUSeconds > 0 {
+			return LimitCPU
+		}
+	case 128 + sigKill:
+		// Some kernels enforce RLIMIT_CPU with SIGKILL too; a KILL with
+		// only a memory cap set is attributed to memory, otherwise CPU.
+		if l.MemoryBytes > 0 {
+			return LimitMemory
+		}
+		if l.CPUSeconds > 0 {
+			return LimitCPU
+		}
+	}
+	return ""
+}
+
+// limitedBuffer buffers output up to limit bytes, then flags the overflow
+// and cancels the command instead of buffering unbounded output.
+type limitedBuffer struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	limit    int64
+	exceeded bool
+	cancel   context.CancelFunc
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	remaining := b.limit - int64(b.buf.Len())
+	if remaining > 0 {
+		if int64(len(p)) > remaining {
+			b.buf.Write(p[:remaining])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	if int64(b.buf.Len()) >= b.limit && int64(len(p)) > remaining && !b.exceeded {
+		b.exceeded = true
+		b.cancel()
+	}
+	// Report success so the pipe keeps draining until the kill lands.
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *limitedBuffer) Exceeded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.exceeded
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputLimitKillsCommand(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{
+		Command: "yes overflowing-line",
+		Limits:  &Limits{MaxOutputBytes: 4096},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.LimitExceeded != LimitOutput {
+		t.Errorf("LimitExceeded = %q, want %q", result.LimitExceeded, LimitOutput)
+	}
+	if len(result.Stdout) > 4096 {
+		t.Errorf("captured %d bytes, want at most the 4096 limit", len(result.Stdout))
+	}
+}
+
+func TestCPULimitReportedDistinctly(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{
+		Command:   "while :; do :; done",
+		Limits:    &Limits{CPUSeconds: 1},
+		TimeoutMs: 30000,
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.LimitExceeded != LimitCPU {
+		t.Errorf("LimitExceeded = %q (exit %d), want %q", result.LimitExceeded, result.ExitCode, LimitCPU)
+	}
+}
+
+func TestLimitsShellPrefix(t *testing.T) {
+	l := &Limits{CPUSeconds: 2, MemoryBytes: 64 * 1024 * 1024, MaxProcesses: 16}
+	prefix := l.shellPrefix()
+	for _, want := range []string{"ulimit -t 2", "ulimit -v 65536", "ulimit -u 16"} {
+		if !strings.Contains(prefix, want) {
+			t.Errorf("prefix %q missing %q", prefix, want)
+		}
+	}
+	if (&Limits{MaxOutputBytes: 10}).shellPrefix() != "" {
+		t.Error("output-only limits should not add a shell prefix")
+	}
+}
@@ -0,0 +1,138 @@
+package command
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy decides which command lines an origin may run. Patterns use
+// path.Match syntax matched against the whole command line ("git *",
+// "npm run *"); deny patterns always win, and a non-empty allowlist makes
+// the policy default-deny.
+type Policy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+	// StrictArgs rejects shell metacharacters (command chaining,
+	// substitution) so allow patterns cannot be smuggled past with
+	// "git status; rm -rf /".
+	StrictArgs bool `json:"strictArgs,omitempty"`
+}
+
+// Decision is one audit log entry: what was asked, by whom, and why it was
+// allowed or denied.
+type Decision struct {
+	Time    time.Time `json:"time"`
+	Origin  string    `json:"origin,omitempty"`
+	Command string    `json:"command"`
+	Allowed bool      `json:"allowed"`
+	Rule    string    `json:"rule,omitempty"`
+	Reason  string    `json:"reason"`
+}
+
+// defaultDeny blocks the obviously destructive patterns previously hard-coded
+// as dangerousCommands.
+var defaultDeny = []string{
+	"*rm -rf /*",
+	"*mkfs*",
+	"*dd if=*",
+	"*:(){*",
+}
+
+// strictArgMeta are the shell metacharacters StrictArgs forbids.
+var strictArgMeta = []string{";", "&&", "||", "|", "$(", "`", "\n"}
+
+const decisionLogLimit = 1000
+
+// PolicyEngine evaluates command lines against a default policy plus
+// per-origin overrides, and keeps a bounded audit log of every decision.
+type PolicyEngine struct {
+	mu        sync.Mutex
+	def       Policy
+	perOrigin map[string]Policy
+	decisions []Decision
+}
+
+// NewPolicyEngine returns an engine with the default deny patterns.
+func NewPolicyEngine() *PolicyEngine {
+	return &PolicyEngine{
+		def:       Policy{Deny: defaultDeny},
+		perOrigin: make(map[string]Policy),
+	}
+}
+
+// SetPolicy installs the policy for origin; an empty origin sets the default
+// policy applied when no per-origin policy exists.
+func (e *PolicyEngine) SetPolicy(origin string, p Policy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if origin == "" {
+		e.def = p
+		return
+	}
+	e.perOrigin[origin] = p
+}
+
+// PolicyFor returns the policy that applies to origin.
+func (e *PolicyEngine) PolicyFor(origin string) Policy {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if p, ok := e.perOrigin[origin]; ok {
+		return p
+	}
+	return e.def
+}
+
+// Check evaluates the command line for origin, records the decision in the
+// audit log, and returns it.
+func (e *PolicyEngine) Check(origin, cmdline string) Decision {
+	d := e.evaluate(e.PolicyFor(origin), cmdline)
+	d.Time = time.Now().UTC()
+	d.Origin = origin
+	d.Command = cmdline
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.decisions = append(e.decisions, d)
+	if len(e.decisions) > decisionLogLimit {
+		e.decisions = e.decisions[len(e.decisions)-decisionLogLimit:]
+	}
+	return d
+}
+
+func (e *PolicyEngine) evaluate(p Policy, cmdline string) Decision {
+	for _, pattern := range p.Deny {
+		if matched, _ := path.Match(pattern, cmdline); matched {
+			return Decision{Allowed: false, Rule: pattern, Reason: "matched deny pattern"}
+		}
+	}
+	if p.StrictArgs {
+		for _, meta := range strictArgMeta {
+			if strings.Contains(cmdline, meta) {
+				return Decision{Allowed: false, Rule: "strictArgs", Reason: fmt.Sprintf("argument contains %q", meta)}
+			}
+		}
+	}
+	if len(p.Allow) > 0 {
+		for _, pattern := range p.Allow {
+			if matched, _ := path.Match(pattern, cmdline); matched {
+				return Decision{Allowed: true, Rule: pattern, Reason: "matched allow pattern"}
+			}
+		}
+		return Decision{Allowed: false, Reason: "no allow pattern matched"}
+	}
+	return Decision{Allowed: true, Reason: "no policy restriction"}
+}
+
+// Decisions returns the audit log, newest first.
+func (e *PolicyEngine) Decisions() []Decision {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Decision, 0, len(e.decisions))
+	for i := len(e.decisions) - 1; i >= 0; i-- {
+		out = append(out, e.decisions[i])
+	}
+	return out
+}
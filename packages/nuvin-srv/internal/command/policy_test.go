@@ -0,0 +1,79 @@
+package command
+
+import (
+	"testing"
+)
+
+func TestPolicyDefaultDeniesDestructiveCommands(t *testing.T) {
+	e := NewPolicyEngine()
+	if d := e.Check("", "rm -rf / --no-preserve-root"); d.Allowed {
+		t.Error("destructive command allowed by default policy")
+	}
+	if d := e.Check("", "git status"); !d.Allowed {
+		t.Errorf("harmless command denied: %+v", d)
+	}
+}
+
+func TestPolicyAllowlistIsDefaultDeny(t *testing.T) {
+	e := NewPolicyEngine()
+	e.SetPolicy("ui", Policy{Allow: []string{"git *", "npm run *"}})
+
+	if d := e.Check("ui", "git log --oneline"); !d.Allowed {
+		t.Errorf("allowlisted command denied: %+v", d)
+	}
+	if d := e.Check("ui", "curl http://evil.example"); d.Allowed {
+		t.Error("non-allowlisted command allowed")
+	}
+	// Other origins keep the default policy.
+	if d := e.Check("other", "curl http://example.com"); !d.Allowed {
+		t.Errorf("default policy applied deny for other origin: %+v", d)
+	}
+}
+
+func TestPolicyDenyWinsOverAllow(t *testing.T) {
+	e := NewPolicyEngine()
+	e.SetPolicy("ui", Policy{Allow: []string{"git *"}, Deny: []string{"git push*"}})
+	if d := e.Check("ui", "git push origin main"); d.Allowed {
+		t.Error("deny pattern did not win over allow")
+	}
+}
+
+func TestPolicyStrictArgs(t *testing.T) {
+	e := NewPolicyEngine()
+	e.SetPolicy("ui", Policy{Allow: []string{"git *"}, StrictArgs: true})
+	if d := e.Check("ui", "git status; rm -rf /tmp/x"); d.Allowed {
+		t.Error("chained command allowed with StrictArgs")
+	}
+	if d := e.Check("ui", "git log $(cat /etc/passwd)"); d.Allowed {
+		t.Error("command substitution allowed with StrictArgs")
+	}
+}
+
+func TestPolicyAuditLog(t *testing.T) {
+	e := NewPolicyEngine()
+	e.Check("ui", "git status")
+	e.Check("ui", "rm -rf / ")
+
+	decisions := e.Decisions()
+	if len(decisions) != 2 {
+		t.Fatalf("decisions = %d, want 2", len(decisions))
+	}
+	// Newest first.
+	if decisions[0].Allowed || decisions[0].Command != "rm -rf / " {
+		t.Errorf("decisions[0] = %+v", decisions[0])
+	}
+	if !decisions[1].Allowed || decisions[1].Origin != "ui" {
+		t.Errorf("decisions[1] = %+v", decisions[1])
+	}
+}
+
+func TestRunnerConsultsPolicy(t *testing.T) {
+	r := NewRunner()
+	r.Policy.SetPolicy("", Policy{Allow: []string{"echo *"}})
+	if _, err := r.Execute(ExecRequest{Command: "echo hi"}); err != nil {
+		t.Errorf("allowed command failed: %v", err)
+	}
+	if _, err := r.Execute(ExecRequest{Command: "true"}); err == nil {
+		t.Error("non-allowlisted command executed")
+	}
+}
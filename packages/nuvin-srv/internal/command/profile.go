@@ -0,0 +1,37 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// applyProfile resolves the request's named environment profile, if any,
+// merging the profile's env (request values win), prepending its PATH
+// entries, and defaulting the working directory.
+func (r *Runner) applyProfile(req ExecRequest) (ExecRequest, error) {
+	if req.Profile == "" {
+		return req, nil
+	}
+	p, ok := r.Profiles.Get(req.Profile)
+	if !ok {
+		return req, fmt.Errorf("unknown environment profile: %q", req.Profile)
+	}
+
+	env := make(map[string]string, len(p.Env)+len(req.Env)+1)
+	for k, v := range p.Env {
+		env[k] = v
+	}
+	if len(p.Path) > 0 {
+		sep := string(os.PathListSeparator)
+		env["PATH"] = strings.Join(p.Path, sep) + sep + os.Getenv("PATH")
+	}
+	for k, v := range req.Env {
+		env[k] = v
+	}
+	req.Env = env
+	if req.Cwd == "" {
+		req.Cwd = p.Cwd
+	}
+	return req, nil
+}
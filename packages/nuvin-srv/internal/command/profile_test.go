@@ -0,0 +1,51 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/envprofile"
+)
+
+func TestExecuteWithEnvironmentProfile(t *testing.T) {
+	r := NewRunner()
+	if err := r.Profiles.Save(envprofile.Profile{
+		Name: "ci",
+		Env:  map[string]string{"GREETING": "from-profile", "OVERRIDDEN": "profile"},
+	}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	res, err := r.Execute(ExecRequest{
+		Command: "echo $GREETING $OVERRIDDEN",
+		Profile: "ci",
+		Env:     map[string]string{"OVERRIDDEN": "request"},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got := strings.TrimSpace(res.Stdout); got != "from-profile request" {
+		t.Fatalf("stdout = %q, want profile env with request override", got)
+	}
+}
+
+func TestExecuteUnknownProfile(t *testing.T) {
+	r := NewRunner()
+	if _, err := r.Execute(ExecRequest{Command: "true", Profile: "nope"}); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestProfilePathPrepended(t *testing.T) {
+	r := NewRunner()
+	if err := r.Profiles.Save(envprofile.Profile{Name: "tools", Path: []string{"/opt/nuvin/bin"}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	res, err := r.Execute(ExecRequest{Command: "echo $PATH", Profile: "tools"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !strings.HasPrefix(res.Stdout, "/opt/nuvin/bin:") {
+		t.Fatalf("PATH = %q, want /opt/nuvin/bin prepended", res.Stdout)
+	}
+}
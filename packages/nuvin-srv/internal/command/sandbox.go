@@ -0,0 +1,152 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Sandbox backends.
+const (
+	// BackendAuto picks bwrap when installed, plain confinement otherwise.
+	BackendAuto = "auto"
+	// BackendNone confines paths but runs the command without isolation.
+	BackendNone = "none"
+	// BackendBwrap wraps the command in bubblewrap with only the workspace
+	// root bind-mounted writable.
+	BackendBwrap = "bwrap"
+	// BackendChroot chroots into the workspace root (requires privileges).
+	BackendChroot = "chroot"
+)
+
+// Sandbox confines command execution to a workspace root.
+type Sandbox struct {
+	// Root is the workspace directory commands may touch.
+	Root string `json:"root"`
+	// Backend is the isolation mechanism; see the Backend constants.
+	Backend string `json:"backend"`
+}
+
+// systemPrefixes are directories absolute-path arguments may reference even
+// when confined: program and library locations, not user data.
+var systemPrefixes = []string{"/bin/", "/sbin/", "/usr/", "/lib/", "/lib64/", "/opt/", "/dev/null", "/tmp/"}
+
+// SetSandbox confines all future executions to the workspace root. backend
+// "" or "auto" selects bwrap when available. A nil-equivalent empty root
+// removes confinement.
+func (r *Runner) SetSandbox(root, backend string) error {
+	if root == "" {
+		r.mu.Lock()
+		r.sandbox = nil
+		r.mu.Unlock()
+		return nil
+	}
+
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("resolving workspace root: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return fmt.Errorf("resolving workspace root: %w", err)
+	}
+
+	switch backend {
+	case "", BackendAuto:
+		backend = BackendNone
+		if _, err := exec.LookPath("bwrap"); err == nil {
+			backend = BackendBwrap
+		}
+	case BackendNone, BackendChroot:
+	case BackendBwrap:
+		if _, err := exec.LookPath("bwrap"); err != nil {
+			return fmt.Errorf("bwrap backend requested but bwrap is not installed")
+		}
+	default:
+		return fmt.Errorf("unknown sandbox backend %q", backend)
+	}
+
+	r.mu.Lock()
+	r.sandbox = &Sandbox{Root: resolved, Backend: backend}
+	r.mu.Unlock()
+	return nil
+}
+
+// SandboxConfig returns the active sandbox, or nil when unconfined.
+func (r *Runner) SandboxConfig() *Sandbox {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sandbox
+}
+
+// confine validates the request against the sandbox and returns it with the
+// working directory defaulted to the workspace root.
+func (s *Sandbox) confine(req ExecRequest) (ExecRequest, error) {
+	if req.Cwd == "" {
+		req.Cwd = s.Root
+	}
+	cwd, err := filepath.Abs(req.Cwd)
+	if err != nil {
+		return req, fmt.Errorf("resolving working directory: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	if !s.within(cwd) {
+		return req, fmt.Errorf("working directory %q is outside the workspace root", req.Cwd)
+	}
+	req.Cwd = cwd
+
+	for _, token := range strings.Fields(req.Command) {
+		token = strings.Trim(token, `"'`)
+		if !strings.HasPrefix(token, "/") {
+			continue
+		}
+		if s.within(token) || systemPath(token) {
+			continue
+		}
+		return req, fmt.Errorf("argument %q references a path outside the workspace root", token)
+	}
+	return req, nil
+}
+
+func (s *Sandbox) within(path string) bool {
+	rel, err := filepath.Rel(s.Root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+func systemPath(path string) bool {
+	for _, prefix := range systemPrefixes {
+		if path == strings.TrimSuffix(prefix, "/") || strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap builds the isolated command for the selected backend.
+func (s *Sandbox) wrap(ctx context.Context, req ExecRequest) *exec.Cmd {
+	switch s.Backend {
+	case BackendBwrap:
+		args := []string{
+			"--bind", s.Root, s.Root,
+			"--ro-bind", "/usr", "/usr",
+			"--ro-bind", "/bin", "/bin",
+			"--ro-bind", "/lib", "/lib",
+			"--dev", "/dev",
+			"--proc", "/proc",
+			"--tmpfs", "/tmp",
+			"--unshare-pid",
+			"--die-with-parent",
+			"--chdir", req.Cwd,
+			"/bin/sh", "-c", req.Command,
+		}
+		return exec.CommandContext(ctx, "bwrap", args...)
+	case BackendChroot:
+		return exec.CommandContext(ctx, "chroot", s.Root, "/bin/sh", "-c", req.Command)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSandboxConfinesWorkingDirectory(t *testing.T) {
+	root := t.TempDir()
+	r := NewRunner()
+	if err := r.SetSandbox(root, BackendNone); err != nil {
+		t.Fatalf("SetSandbox: %v", err)
+	}
+
+	// Cwd defaults to the workspace root.
+	result, err := r.Execute(ExecRequest{Command: "pwd"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	resolved, _ := filepath.EvalSymlinks(root)
+	if strings.TrimSpace(result.Stdout) != resolved {
+		t.Errorf("pwd = %q, want %q", strings.TrimSpace(result.Stdout), resolved)
+	}
+
+	// A cwd outside the root is rejected.
+	if _, err := r.Execute(ExecRequest{Command: "pwd", Cwd: os.TempDir()}); err == nil {
+		t.Error("cwd outside workspace accepted")
+	}
+
+	sub := filepath.Join(root, "sub")
+	os.Mkdir(sub, 0o755)
+	if _, err := r.Execute(ExecRequest{Command: "pwd", Cwd: sub}); err != nil {
+		t.Errorf("cwd inside workspace rejected: %v", err)
+	}
+}
+
+func TestSandboxRejectsOutsidePathArguments(t *testing.T) {
+	root := t.TempDir()
+	r := NewRunner()
+	if err := r.SetSandbox(root, BackendNone); err != nil {
+		t.Fatalf("SetSandbox: %v", err)
+	}
+
+	if _, err := r.Execute(ExecRequest{Command: "cat /etc/passwd"}); err == nil {
+		t.Error("argument outside workspace accepted")
+	}
+	// Paths inside the workspace and system binaries are fine.
+	inside := filepath.Join(root, "f.txt")
+	os.WriteFile(inside, []byte("ok"), 0o644)
+	result, err := r.Execute(ExecRequest{Command: "/bin/cat " + inside})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Stdout != "ok" {
+		t.Errorf("Stdout = %q", result.Stdout)
+	}
+}
+
+func TestSetSandboxValidatesBackend(t *testing.T) {
+	r := NewRunner()
+	if err := r.SetSandbox(t.TempDir(), "vmware"); err == nil {
+		t.Error("unknown backend accepted")
+	}
+	if err := r.SetSandbox("", ""); err != nil {
+		t.Errorf("removing sandbox: %v", err)
+	}
+	if r.SandboxConfig() != nil {
+		t.Error("sandbox still set after removal")
+	}
+}
@@ -0,0 +1,117 @@
+package command
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SchedulerConfig caps simultaneous command executions. Zero values mean
+// unlimited / no queueing.
+type SchedulerConfig struct {
+	// MaxConcurrent caps executions across all callers.
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+	// MaxPerOrigin caps executions per authenticated subject.
+	MaxPerOrigin int `json:"maxPerOrigin,omitempty"`
+	// MaxQueueWaitMs is how long an overflowing request may wait for a slot
+	// before being rejected; 0 rejects immediately.
+	MaxQueueWaitMs int `json:"maxQueueWaitMs,omitempty"`
+}
+
+// SchedulerState is a snapshot of the execution queue.
+type SchedulerState struct {
+	Config    SchedulerConfig `json:"config"`
+	Running   int             `json:"running"`
+	Waiting   int             `json:"waiting"`
+	PerOrigin map[string]int  `json:"perOrigin,omitempty"`
+}
+
+// scheduler serializes slot accounting for the Runner.
+type scheduler struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	cfg       SchedulerConfig
+	running   int
+	waiting   int
+	perOrigin map[string]int
+}
+
+func newScheduler() *scheduler {
+	s := &scheduler{perOrigin: make(map[string]int)}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+func (s *scheduler) setConfig(cfg SchedulerConfig) {
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+func (s *scheduler) canRun(origin string) bool {
+	if s.cfg.MaxConcurrent > 0 && s.running >= s.cfg.MaxConcurrent {
+		return false
+	}
+	if s.cfg.MaxPerOrigin > 0 && s.perOrigin[origin] >= s.cfg.MaxPerOrigin {
+		return false
+	}
+	return true
+}
+
+// acquire blocks until a slot is free, up to the configured queue wait.
+func (s *scheduler) acquire(origin string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.canRun(origin) {
+		if s.cfg.MaxQueueWaitMs <= 0 {
+			return fmt.Errorf("execution limit reached")
+		}
+		deadline := time.Now().Add(time.Duration(s.cfg.MaxQueueWaitMs) * time.Millisecond)
+		for !s.canRun(origin) {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return fmt.Errorf("execution limit reached: queued longer than %dms", s.cfg.MaxQueueWaitMs)
+			}
+			timer := time.AfterFunc(remaining, s.cond.Broadcast)
+			s.waiting++
+			s.cond.Wait()
+			s.waiting--
+			timer.Stop()
+		}
+	}
+	s.running++
+	s.perOrigin[origin]++
+	return nil
+}
+
+func (s *scheduler) release(origin string) {
+	s.mu.Lock()
+	s.running--
+	if s.perOrigin[origin]--; s.perOrigin[origin] <= 0 {
+		delete(s.perOrigin, origin)
+	}
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+func (s *scheduler) state() SchedulerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	per := make(map[string]int, len(s.perOrigin))
+	for k, v := range s.perOrigin {
+		per[k] = v
+	}
+	return SchedulerState{Config: s.cfg, Running: s.running, Waiting: s.waiting, PerOrigin: per}
+}
+
+// SetSchedulerConfig replaces the execution caps; waiters re-check.
+func (r *Runner) SetSchedulerConfig(cfg SchedulerConfig) {
+	r.scheduler.setConfig(cfg)
+}
+
+// SchedulerState reports the current queue and running counts.
+func (r *Runner) SchedulerState() SchedulerState {
+	return r.scheduler.state()
+}
@@ -0,0 +1,101 @@
+package command
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchedulerRejectsOverCapWithoutQueue(t *testing.T) {
+	s := newScheduler()
+	s.setConfig(SchedulerConfig{MaxConcurrent: 1})
+
+	if err := s.acquire("a"); err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+	if err := s.acquire("a"); err == nil {
+		t.Fatal("expected second acquire to be rejected")
+	}
+	s.release("a")
+	if err := s.acquire("a"); err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+}
+
+func TestSchedulerPerOriginCap(t *testing.T) {
+	s := newScheduler()
+	s.setConfig(SchedulerConfig{MaxPerOrigin: 1})
+
+	if err := s.acquire("alice"); err != nil {
+		t.Fatalf("alice acquire: %v", err)
+	}
+	if err := s.acquire("alice"); err == nil {
+		t.Fatal("expected alice's second acquire to be rejected")
+	}
+	if err := s.acquire("bob"); err != nil {
+		t.Fatalf("bob should not be limited by alice's slot: %v", err)
+	}
+}
+
+func TestSchedulerQueuedRequestGetsFreedSlot(t *testing.T) {
+	s := newScheduler()
+	s.setConfig(SchedulerConfig{MaxConcurrent: 1, MaxQueueWaitMs: 2000})
+
+	if err := s.acquire("a"); err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	errCh := make(chan error, 1)
+	go func() {
+		defer wg.Done()
+		errCh <- s.acquire("b")
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if st := s.state(); st.Waiting != 1 {
+		t.Fatalf("waiting = %d, want 1", st.Waiting)
+	}
+	s.release("a")
+	wg.Wait()
+	if err := <-errCh; err != nil {
+		t.Fatalf("queued acquire after slot freed: %v", err)
+	}
+}
+
+func TestSchedulerQueueWaitTimesOut(t *testing.T) {
+	s := newScheduler()
+	s.setConfig(SchedulerConfig{MaxConcurrent: 1, MaxQueueWaitMs: 50})
+
+	if err := s.acquire("a"); err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+	err := s.acquire("b")
+	if err == nil {
+		t.Fatal("expected queued acquire to time out")
+	}
+	if !strings.Contains(err.Error(), "queued") {
+		t.Fatalf("error = %q, want queue timeout", err)
+	}
+}
+
+func TestSchedulerStateCounts(t *testing.T) {
+	s := newScheduler()
+	if err := s.acquire("alice"); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if err := s.acquire("alice"); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	st := s.state()
+	if st.Running != 2 || st.PerOrigin["alice"] != 2 {
+		t.Fatalf("state = %+v, want running 2 with alice 2", st)
+	}
+	s.release("alice")
+	s.release("alice")
+	if st := s.state(); st.Running != 0 || len(st.PerOrigin) != 0 {
+		t.Fatalf("state after release = %+v, want empty", st)
+	}
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"strings"
+)
+
+// ScriptRequest describes a multi-line script execution. The script is staged
+// to a temp file and handed to the interpreter directly, sidestepping shell
+// escaping for embedded quotes and newlines.
+type ScriptRequest struct {
+	// Script is the full script body.
+	Script string `json:"script"`
+	// Interpreter selects the runtime: "bash", "sh", "python", or "node".
+	Interpreter string `json:"interpreter"`
+	// Cwd, Env, TimeoutMs, and Limits behave as in ExecRequest.
+	Cwd       string            `json:"cwd,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	TimeoutMs int               `json:"timeoutMs,omitempty"`
+	Limits    *Limits           `json:"limits,omitempty"`
+	// Origin identifies the caller, as in ExecRequest.
+	Origin string `json:"-"`
+}
+
+// interpreters maps the accepted interpreter names to the binary invoked and
+// the staged file's extension.
+var interpreters = map[string]struct {
+	binary string
+	ext    string
+}{
+	"bash":   {"bash", ".sh"},
+	"sh":     {"sh", ".sh"},
+	"python": {"python3", ".py"},
+	"node":   {"node", ".js"},
+}
+
+// ExecuteScript stages the script to a temp file, runs it through the normal
+// execution path (policy, sandbox, limits, history), and removes the file.
+func (r *Runner) ExecuteScript(req ScriptRequest) (*ExecResult, error) {
+	if strings.TrimSpace(req.Script) == "" {
+		return nil, fmt.Errorf("script is required")
+	}
+	interp, ok := interpreters[req.Interpreter]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interpreter: %q", req.Interpreter)
+	}
+
+	// Stage inside the workspace root when a sandbox is configured: the
+	// bwrap backend mounts a fresh tmpfs over /tmp, so a script staged in
+	// the host temp dir would not exist inside the namespace.
+	dir := ""
+	if sb := r.SandboxConfig(); sb != nil {
+		dir = sb.Root
+	}
+	file, err := os.CreateTemp(dir, "nuvin-script-*"+interp.ext)
+	if err != nil {
+		return nil, fmt.Errorf("staging script: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(req.Script); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("staging script: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("staging script: %w", err)
+	}
+
+	return r.Execute(ExecRequest{
+		Command:   interp.binary + " " + file.Name(),
+		Cwd:       req.Cwd,
+		Env:       req.Env,
+		TimeoutMs: req.TimeoutMs,
+		Limits:    req.Limits,
+		Origin:    req.Origin,
+	})
+}
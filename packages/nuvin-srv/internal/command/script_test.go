@@ -0,0 +1,58 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecuteScriptMultiLine(t *testing.T) {
+	r := NewRunner()
+	res, err := r.ExecuteScript(ScriptRequest{
+		Interpreter: "bash",
+		Script:      "greeting=\"hello 'world'\"\necho \"$greeting\"\necho warn >&2\n",
+	})
+	if err != nil {
+		t.Fatalf("ExecuteScript: %v", err)
+	}
+	if got := strings.TrimSpace(res.Stdout); got != "hello 'world'" {
+		t.Fatalf("stdout = %q", got)
+	}
+	if got := strings.TrimSpace(res.Stderr); got != "warn" {
+		t.Fatalf("stderr = %q", got)
+	}
+	if res.ExitCode != 0 {
+		t.Fatalf("exit code = %d", res.ExitCode)
+	}
+}
+
+func TestExecuteScriptRejectsUnknownInterpreter(t *testing.T) {
+	r := NewRunner()
+	if _, err := r.ExecuteScript(ScriptRequest{Interpreter: "ruby", Script: "puts 1"}); err == nil {
+		t.Fatal("expected error for unknown interpreter")
+	}
+}
+
+func TestExecuteScriptRequiresScript(t *testing.T) {
+	r := NewRunner()
+	if _, err := r.ExecuteScript(ScriptRequest{Interpreter: "bash", Script: "  \n"}); err == nil {
+		t.Fatal("expected error for empty script")
+	}
+}
+
+func TestExecuteScriptStagesInsideSandboxRoot(t *testing.T) {
+	r := NewRunner()
+	root := t.TempDir()
+	if err := r.SetSandbox(root, BackendNone); err != nil {
+		t.Fatalf("SetSandbox: %v", err)
+	}
+	// $0 is the staged script path; bwrap mounts a tmpfs over /tmp, so the
+	// file must live under the workspace root to survive every backend.
+	res, err := r.ExecuteScript(ScriptRequest{Interpreter: "sh", Script: "echo $0\n"})
+	if err != nil {
+		t.Fatalf("ExecuteScript: %v", err)
+	}
+	staged := strings.TrimSpace(res.Stdout)
+	if !strings.HasPrefix(staged, root+"/") {
+		t.Fatalf("script staged at %q, want under %q", staged, root)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package command
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// platformShell resolves the shell binary and the arguments that introduce a
+// command string. An empty override keeps the portable default of /bin/sh.
+func platformShell(override string) (string, []string, error) {
+	switch override {
+	case "":
+		return "/bin/sh", []string{"-c"}, nil
+	case "sh", "bash", "zsh", "fish", "dash":
+		return override, []string{"-c"}, nil
+	case "pwsh", "powershell":
+		return "pwsh", []string{"-NoProfile", "-Command"}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported shell %q", override)
+	}
+}
+
+// configureShellCmd applies platform quoting fixups; POSIX shells take the
+// command string as a single argv entry, so nothing is needed here.
+func configureShellCmd(cmd *exec.Cmd, shell, cmdline string) {}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// platformShell resolves the shell binary and the arguments that introduce a
+// command string. The default is %COMSPEC% (cmd.exe); PowerShell variants
+// are selected explicitly.
+func platformShell(override string) (string, []string, error) {
+	switch override {
+	case "", "cmd", "cmd.exe":
+		shell := os.Getenv("COMSPEC")
+		if shell == "" {
+			shell = "cmd.exe"
+		}
+		return shell, []string{"/C"}, nil
+	case "powershell", "powershell.exe":
+		return "powershell.exe", []string{"-NoProfile", "-Command"}, nil
+	case "pwsh", "pwsh.exe":
+		return "pwsh.exe", []string{"-NoProfile", "-Command"}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported shell %q", override)
+	}
+}
+
+// configureShellCmd bypasses Go's argv re-quoting for cmd.exe, which parses
+// its command line itself and mangles quoted arguments otherwise.
+func configureShellCmd(cmd *exec.Cmd, shell, cmdline string) {
+	if !strings.EqualFold(strings.TrimSuffix(shell, ".exe"), "cmd") &&
+		!strings.HasSuffix(strings.ToLower(shell), "cmd.exe") {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine: fmt.Sprintf(`/S /C "%s"`, cmdline),
+	}
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// signalNames maps the accepted signal names to their numbers.
+var signalNames = map[string]syscall.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGINT":  syscall.SIGINT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGKILL": syscall.SIGKILL,
+}
+
+// SignalPolicy lists which signals callers may deliver. SIGKILL is excluded
+// by default so the graceful signals stay the easy path.
+type SignalPolicy struct {
+	Allow []string `json:"allow"`
+}
+
+var defaultSignalAllow = []string{"SIGTERM", "SIGINT", "SIGHUP"}
+
+// SetSignalPolicy replaces the allowed-signal list; nil Allow restores the
+// default.
+func (r *Runner) SetSignalPolicy(p SignalPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sigAllow = p.Allow
+}
+
+// SignalPolicy reports the signals currently allowed.
+func (r *Runner) SignalPolicy() SignalPolicy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	allow := r.sigAllow
+	if allow == nil {
+		allow = defaultSignalAllow
+	}
+	return SignalPolicy{Allow: append([]string(nil), allow...)}
+}
+
+// Signal delivers the named signal to the running execution with the given
+// job or command ID.
+func (r *Runner) Signal(id, name string) error {
+	sig, ok := signalNames[name]
+	if !ok {
+		return fmt.Errorf("unsupported signal: %q", name)
+	}
+	allowed := false
+	for _, a := range r.SignalPolicy().Allow {
+		if a == name {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("signal %s rejected by policy", name)
+	}
+
+	r.mu.Lock()
+	proc := r.procs[id]
+	r.mu.Unlock()
+	if proc == nil {
+		return fmt.Errorf("no running execution: %s", id)
+	}
+	if err := signalProcess(proc, sig); err != nil {
+		return fmt.Errorf("signalling %s: %w", id, err)
+	}
+	return nil
+}
+
+// registerProcess makes a started process addressable for Signal.
+func (r *Runner) registerProcess(id string, proc *os.Process) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.procs == nil {
+		r.procs = make(map[string]*os.Process)
+	}
+	r.procs[id] = proc
+}
+
+func (r *Runner) unregisterProcess(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.procs, id)
+}
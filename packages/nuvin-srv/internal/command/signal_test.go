@@ -0,0 +1,69 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignalTerminatesStreamedCommand(t *testing.T) {
+	r := NewRunner()
+	started := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- r.ExecuteStream(ExecRequest{Command: "sleep 30"}, func(ev StreamEvent) {
+			if ev.Type == "start" {
+				started <- ev.Data
+			}
+		})
+	}()
+
+	var id string
+	select {
+	case id = <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no start event")
+	}
+	// The process registers right after the start event; retry briefly.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if err := r.Signal(id, "SIGTERM"); err == nil {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("Signal: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ExecuteStream: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("command did not exit after SIGTERM")
+	}
+}
+
+func TestSignalPolicyBlocksSIGKILLByDefault(t *testing.T) {
+	r := NewRunner()
+	err := r.Signal("job-any", "SIGKILL")
+	if err == nil || !strings.Contains(err.Error(), "rejected by policy") {
+		t.Fatalf("err = %v, want policy rejection", err)
+	}
+
+	r.SetSignalPolicy(SignalPolicy{Allow: []string{"SIGTERM", "SIGKILL"}})
+	err = r.Signal("job-any", "SIGKILL")
+	if err == nil || !strings.Contains(err.Error(), "no running execution") {
+		t.Fatalf("err = %v, want unknown-execution after policy allows SIGKILL", err)
+	}
+}
+
+func TestSignalUnknownTargets(t *testing.T) {
+	r := NewRunner()
+	if err := r.Signal("job-missing", "SIGTERM"); err == nil {
+		t.Fatal("expected error for unknown execution")
+	}
+	if err := r.Signal("job-missing", "SIGFOO"); err == nil {
+		t.Fatal("expected error for unknown signal")
+	}
+}
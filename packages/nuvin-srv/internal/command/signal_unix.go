@@ -0,0 +1,19 @@
+//go:build !windows
+
+package command
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalProcess delivers sig to the execution's whole process group, matching
+// proctree's kill semantics: the direct child is a shell wrapper, and the
+// processes callers actually mean to signal are its descendants. Falls back
+// to the direct child if the group is already gone.
+func signalProcess(proc *os.Process, sig syscall.Signal) error {
+	if err := syscall.Kill(-proc.Pid, sig); err != nil {
+		return proc.Signal(sig)
+	}
+	return nil
+}
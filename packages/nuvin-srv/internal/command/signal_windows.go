@@ -0,0 +1,14 @@
+//go:build windows
+
+package command
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalProcess delivers sig to the direct child; Windows has no process
+// groups to address, and os.Process.Signal only supports Kill there anyway.
+func signalProcess(proc *os.Process, sig syscall.Signal) error {
+	return proc.Signal(sig)
+}
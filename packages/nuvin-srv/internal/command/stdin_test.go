@@ -0,0 +1,88 @@
+package command
+
+import (
+	"encoding/base64"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestExecuteFeedsStdin(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{Command: "cat", Stdin: "piped input"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Stdout != "piped input" {
+		t.Errorf("Stdout = %q", result.Stdout)
+	}
+}
+
+func TestExecuteFeedsBase64Stdin(t *testing.T) {
+	r := NewRunner()
+	result, err := r.Execute(ExecRequest{
+		Command:       "cat",
+		Stdin:         base64.StdEncoding.EncodeToString([]byte("binary\x00input")),
+		StdinEncoding: "base64",
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Stdout != "binary\x00input" {
+		t.Errorf("Stdout = %q", result.Stdout)
+	}
+	if _, err := r.Execute(ExecRequest{Command: "cat", Stdin: "!!", StdinEncoding: "base64"}); err == nil {
+		t.Error("invalid base64 stdin accepted")
+	}
+}
+
+func TestStreamInteractiveStdin(t *testing.T) {
+	r := NewRunner()
+
+	var mu sync.Mutex
+	var events []StreamEvent
+	started := make(chan string, 1)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.ExecuteStream(ExecRequest{Command: "cat"}, func(ev StreamEvent) {
+			mu.Lock()
+			events = append(events, ev)
+			mu.Unlock()
+			if ev.Type == "start" {
+				started <- ev.Data
+			}
+		})
+	}()
+
+	id := <-started
+	if !strings.HasPrefix(id, "cmd-") {
+		t.Errorf("command ID = %q", id)
+	}
+	if err := r.WriteStdin(id, []byte("line-one\n")); err != nil {
+		t.Fatalf("WriteStdin: %v", err)
+	}
+	if err := r.CloseStdin(id); err != nil {
+		t.Fatalf("CloseStdin: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("ExecuteStream: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var sawEcho bool
+	for _, ev := range events {
+		if ev.Type == "stdout" && ev.Data == "line-one" {
+			sawEcho = true
+		}
+	}
+	if !sawEcho {
+		t.Errorf("events = %+v, want echoed stdin line", events)
+	}
+
+	// The pipe is unregistered after the command finishes.
+	if err := r.WriteStdin(id, []byte("late")); err == nil {
+		t.Error("WriteStdin succeeded after command exit")
+	}
+}
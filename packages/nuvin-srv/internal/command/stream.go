@@ -0,0 +1,182 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proctree"
+)
+
+// StreamEvent is one line of live command output, or the final exit event.
+type StreamEvent struct {
+	// Type is "start", "stdout", "stderr", or "exit".
+	Type string `json:"type"`
+	// Data is the output line for stdout/stderr events.
+	Data string `json:"data,omitempty"`
+	// ExitCode and DurationMs are set on the exit event.
+	ExitCode   int   `json:"exitCode,omitempty"`
+	DurationMs int64 `json:"durationMs,omitempty"`
+	TimedOut   bool  `json:"timedOut,omitempty"`
+}
+
+// ExecuteStream runs the command and calls emit for every stdout/stderr line
+// as it appears, followed by exactly one "exit" event. emit is never called
+// concurrently. When the request carries no fixed stdin, the command's stdin
+// stays open and addressable through WriteStdin using the ID from the
+// initial "start" event.
+func (r *Runner) ExecuteStream(req ExecRequest, emit func(StreamEvent)) error {
+	if err := r.checkCommand(req); err != nil {
+		return err
+	}
+	if err := r.scheduler.acquire(req.Origin); err != nil {
+		return err
+	}
+	defer r.scheduler.release(req.Origin)
+
+	var err error
+	if req, err = r.applyProfile(req); err != nil {
+		return err
+	}
+
+	sb := r.SandboxConfig()
+	if sb != nil {
+		var err error
+		if req, err = sb.confine(req); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := execContext(context.Background(), req)
+	defer cancel()
+
+	cmd, err := shellCommand(ctx, req, sb)
+	if err != nil {
+		return err
+	}
+	// As in execute: a timeout must take the command's descendants with it.
+	tree := proctree.Setup(cmd)
+	cmd.Cancel = tree.Kill
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("executing command: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("executing command: %w", err)
+	}
+
+	var commandID string
+	if req.Stdin != "" {
+		stdin, serr := stdinReader(req)
+		if serr != nil {
+			return serr
+		}
+		cmd.Stdin = stdin
+	} else {
+		// Interactive mode: keep stdin open and register it so follow-up
+		// input can be sent to /execute-command/{id}/stdin.
+		pipe, perr := cmd.StdinPipe()
+		if perr != nil {
+			return fmt.Errorf("executing command: %w", perr)
+		}
+		commandID = r.registerStdin(pipe)
+		defer r.CloseStdin(commandID)
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("executing command: %w", err)
+	}
+	tree.Adopt()
+	defer tree.Close()
+	if commandID != "" {
+		r.registerProcess(commandID, cmd.Process)
+		defer r.unregisterProcess(commandID)
+	}
+
+	var emitMu sync.Mutex
+	send := func(ev StreamEvent) {
+		emitMu.Lock()
+		defer emitMu.Unlock()
+		emit(ev)
+	}
+	if commandID != "" {
+		send(StreamEvent{Type: "start", Data: commandID})
+	}
+
+	var wg sync.WaitGroup
+	for _, pipe := range []struct {
+		name string
+		r    io.Reader
+	}{{"stdout", stdout}, {"stderr", stderr}} {
+		wg.Add(1)
+		go func(name string, r io.Reader) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(r)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				send(StreamEvent{Type: name, Data: scanner.Text()})
+			}
+		}(pipe.name, pipe.r)
+	}
+
+	// Wait before draining finishes: an orphaned descendant can hold the
+	// output pipes open forever, and only Wait's WaitDelay closes them.
+	err = cmd.Wait()
+	wg.Wait()
+
+	exit := StreamEvent{
+		Type:       "exit",
+		DurationMs: time.Since(start).Milliseconds(),
+		TimedOut:   ctx.Err() == context.DeadlineExceeded,
+	}
+	if err != nil {
+		exit.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	send(exit)
+	return nil
+}
+
+// registerStdin stores an open stdin pipe and returns its command ID.
+func (r *Runner) registerStdin(pipe io.WriteCloser) string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	id := "cmd-" + hex.EncodeToString(buf)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stdinPipes == nil {
+		r.stdinPipes = make(map[string]io.WriteCloser)
+	}
+	r.stdinPipes[id] = pipe
+	return id
+}
+
+// WriteStdin feeds input to a running streamed command.
+func (r *Runner) WriteStdin(id string, data []byte) error {
+	r.mu.Lock()
+	pipe, ok := r.stdinPipes[id]
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown command: %s", id)
+	}
+	_, err := pipe.Write(data)
+	return err
+}
+
+// CloseStdin closes a streamed command's stdin (EOF for the child).
+func (r *Runner) CloseStdin(id string) error {
+	r.mu.Lock()
+	pipe, ok := r.stdinPipes[id]
+	delete(r.stdinPipes, id)
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown command: %s", id)
+	}
+	return pipe.Close()
+}
@@ -0,0 +1,156 @@
+// Package discovery probes well-known local ports for inference servers
+// (Ollama, LM Studio, llama.cpp server) so their models can be proxied
+// without manual route setup.
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Candidate is one address a Scan probes.
+type Candidate struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
+// defaultCandidates are the ports the common local inference servers bind
+// by default.
+var defaultCandidates = []Candidate{
+	{Kind: "ollama", URL: "http://127.0.0.1:11434"},
+	{Kind: "lmstudio", URL: "http://127.0.0.1:1234"},
+	{Kind: "llamacpp", URL: "http://127.0.0.1:8080"},
+}
+
+// Server is one local inference server a Scan found.
+type Server struct {
+	Kind   string   `json:"kind"`
+	URL    string   `json:"url"`
+	Models []string `json:"models,omitempty"`
+}
+
+// Scanner probes candidate addresses for running inference servers.
+type Scanner struct {
+	client *http.Client
+
+	mu         sync.Mutex
+	candidates []Candidate
+}
+
+// NewScanner returns a Scanner covering the default local ports. Probes use
+// a short timeout so a full scan stays fast when nothing is running.
+func NewScanner() *Scanner {
+	return &Scanner{
+		client:     &http.Client{Timeout: 2 * time.Second},
+		candidates: append([]Candidate(nil), defaultCandidates...),
+	}
+}
+
+// SetCandidates replaces the probed addresses (tests point them at stubs).
+func (s *Scanner) SetCandidates(candidates []Candidate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.candidates = append([]Candidate(nil), candidates...)
+}
+
+// Candidates returns the addresses a Scan will probe.
+func (s *Scanner) Candidates() []Candidate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Candidate(nil), s.candidates...)
+}
+
+// Scan probes every candidate concurrently and returns the servers that
+// answered, in candidate order.
+func (s *Scanner) Scan() []Server {
+	candidates := s.Candidates()
+	found := make([]*Server, len(candidates))
+	var wg sync.WaitGroup
+	for i, c := range candidates {
+		wg.Add(1)
+		go func(i int, c Candidate) {
+			defer wg.Done()
+			if srv, ok := s.probe(c); ok {
+				found[i] = &srv
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	out := make([]Server, 0, len(candidates))
+	for _, srv := range found {
+		if srv != nil {
+			out = append(out, *srv)
+		}
+	}
+	return out
+}
+
+// probe asks one candidate for its model list; a candidate that does not
+// answer with a parseable list is treated as absent.
+func (s *Scanner) probe(c Candidate) (Server, bool) {
+	var models []string
+	var ok bool
+	if c.Kind == "ollama" {
+		models, ok = s.ollamaModels(c.URL)
+	} else {
+		models, ok = s.openAIModels(c.URL)
+	}
+	if !ok {
+		return Server{}, false
+	}
+	return Server{Kind: c.Kind, URL: c.URL, Models: models}, true
+}
+
+// ollamaModels lists models via Ollama's native /api/tags.
+func (s *Scanner) ollamaModels(base string) ([]string, bool) {
+	resp, err := s.client.Get(base + "/api/tags")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, false
+	}
+	defer resp.Body.Close()
+	var payload struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, false
+	}
+	models := make([]string, 0, len(payload.Models))
+	for _, m := range payload.Models {
+		models = append(models, m.Name)
+	}
+	return models, true
+}
+
+// openAIModels lists models via the OpenAI-compatible /v1/models that both
+// LM Studio and llama.cpp server expose.
+func (s *Scanner) openAIModels(base string) ([]string, bool) {
+	resp, err := s.client.Get(base + "/v1/models")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, false
+	}
+	defer resp.Body.Close()
+	var payload struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, false
+	}
+	models := make([]string, 0, len(payload.Data))
+	for _, m := range payload.Data {
+		models = append(models, m.ID)
+	}
+	return models, true
+}
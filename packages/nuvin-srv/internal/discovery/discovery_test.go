@@ -0,0 +1,58 @@
+package discovery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestScanFindsRunningServers(t *testing.T) {
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"models":[{"name":"llama3:8b"},{"name":"qwen2.5:7b"}]}`))
+	}))
+	defer ollama.Close()
+	lmstudio := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"mistral-7b"}]}`))
+	}))
+	defer lmstudio.Close()
+
+	s := NewScanner()
+	s.SetCandidates([]Candidate{
+		{Kind: "ollama", URL: ollama.URL},
+		{Kind: "lmstudio", URL: lmstudio.URL},
+		{Kind: "llamacpp", URL: "http://127.0.0.1:1"}, // nothing listening
+	})
+
+	servers := s.Scan()
+	if len(servers) != 2 {
+		t.Fatalf("found %d servers, want 2: %+v", len(servers), servers)
+	}
+	if servers[0].Kind != "ollama" || !reflect.DeepEqual(servers[0].Models, []string{"llama3:8b", "qwen2.5:7b"}) {
+		t.Errorf("ollama = %+v", servers[0])
+	}
+	if servers[1].Kind != "lmstudio" || !reflect.DeepEqual(servers[1].Models, []string{"mistral-7b"}) {
+		t.Errorf("lmstudio = %+v", servers[1])
+	}
+}
+
+func TestScanIgnoresNonModelServers(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>not an inference server</html>"))
+	}))
+	defer other.Close()
+
+	s := NewScanner()
+	s.SetCandidates([]Candidate{{Kind: "llamacpp", URL: other.URL}})
+	if servers := s.Scan(); len(servers) != 0 {
+		t.Errorf("found %d servers, want 0: %+v", len(servers), servers)
+	}
+}
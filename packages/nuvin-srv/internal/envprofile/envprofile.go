@@ -0,0 +1,71 @@
+// Package envprofile stores named environment profiles that bundle env vars,
+// PATH additions, and a working directory, so callers can reference a profile
+// by name instead of re-sending secrets with every execution.
+package envprofile
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Profile is a named bundle of process environment settings.
+type Profile struct {
+	Name string `json:"name"`
+	// Env entries are added to the process environment; request-level env
+	// values override them on conflict.
+	Env map[string]string `json:"env,omitempty"`
+	// Path entries are prepended to the PATH of the child process.
+	Path []string `json:"path,omitempty"`
+	// Cwd is the default working directory when the request sets none.
+	Cwd string `json:"cwd,omitempty"`
+}
+
+// Store keeps profiles by name.
+type Store struct {
+	mu       sync.RWMutex
+	profiles map[string]Profile
+}
+
+// NewStore returns an empty profile store.
+func NewStore() *Store {
+	return &Store{profiles: make(map[string]Profile)}
+}
+
+// Save adds or replaces a profile.
+func (s *Store) Save(p Profile) error {
+	if p.Name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[p.Name] = p
+	return nil
+}
+
+// Get returns the profile with the given name.
+func (s *Store) Get(name string) (Profile, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.profiles[name]
+	return p, ok
+}
+
+// Delete removes a profile by name.
+func (s *Store) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.profiles, name)
+}
+
+// List returns all profiles sorted by name.
+func (s *Store) List() []Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
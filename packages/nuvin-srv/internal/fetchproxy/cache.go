@@ -0,0 +1,141 @@
+package fetchproxy
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheConfig controls the optional GET cache and request coalescing.
+type CacheConfig struct {
+	// TTLSeconds is how long cached responses stay fresh (default 60).
+	TTLSeconds int `json:"ttlSeconds,omitempty"`
+	// MaxEntries bounds the cache size (default 512, oldest evicted).
+	MaxEntries int `json:"maxEntries,omitempty"`
+}
+
+type cacheEntry struct {
+	resp    FetchResponse
+	expires time.Time
+}
+
+type flight struct {
+	done chan struct{}
+	resp *FetchResponse
+	err  error
+}
+
+// fetchCache caches GET responses and coalesces duplicate in-flight
+// requests so repeated metadata lookups hit the upstream once.
+type fetchCache struct {
+	ttl time.Duration
+	max int
+	now func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]cacheEntry
+	order    []string
+	inflight map[string]*flight
+}
+
+func newFetchCache(cfg CacheConfig) *fetchCache {
+	ttl := time.Duration(cfg.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = 60 * time.Second
+	}
+	max := cfg.MaxEntries
+	if max <= 0 {
+		max = 512
+	}
+	return &fetchCache{
+		ttl:      ttl,
+		max:      max,
+		now:      time.Now,
+		entries:  make(map[string]cacheEntry),
+		inflight: make(map[string]*flight),
+	}
+}
+
+// EnableCache turns on GET caching and coalescing with the given settings.
+func (p *FetchProxy) EnableCache(cfg CacheConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache = newFetchCache(cfg)
+}
+
+// DisableCache turns caching back off and drops all entries.
+func (p *FetchProxy) DisableCache() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache = nil
+}
+
+// cacheKey identifies a request by URL plus its headers in sorted order.
+func cacheKey(req FetchRequest) string {
+	keys := make([]string, 0, len(req.Headers))
+	for k := range req.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(req.URL)
+	for _, k := range keys {
+		b.WriteByte('\n')
+		b.WriteString(k)
+		b.WriteByte(':')
+		b.WriteString(req.Headers[k])
+	}
+	return b.String()
+}
+
+// cacheable reports whether a request may be served from / stored in the
+// cache: plain GETs without session, download, or abort semantics.
+func cacheable(req FetchRequest) bool {
+	return (req.Method == "" || req.Method == "GET") &&
+		req.CookieSession == "" && !req.DownloadTo && req.RequestID == "" && !req.Stream
+}
+
+// fetchCached serves req through the cache, coalescing concurrent misses
+// for the same key into a single upstream call.
+func (c *fetchCache) fetchCached(key string, do func() (*FetchResponse, error)) (*FetchResponse, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && c.now().Before(entry.expires) {
+		c.mu.Unlock()
+		resp := entry.resp
+		return &resp, nil
+	}
+	if f, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-f.done
+		if f.err != nil {
+			return nil, f.err
+		}
+		resp := *f.resp
+		return &resp, nil
+	}
+	f := &flight{done: make(chan struct{})}
+	c.inflight[key] = f
+	c.mu.Unlock()
+
+	f.resp, f.err = do()
+	close(f.done)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if f.err == nil {
+		c.entries[key] = cacheEntry{resp: *f.resp, expires: c.now().Add(c.ttl)}
+		c.order = append(c.order, key)
+		for len(c.order) > c.max {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	c.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+	return &resp, nil
+}
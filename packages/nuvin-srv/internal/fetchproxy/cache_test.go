@@ -0,0 +1,126 @@
+package fetchproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheServesRepeatedGETs(t *testing.T) {
+	var calls int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		_, _ = w.Write([]byte("models"))
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	p.EnableCache(CacheConfig{TTLSeconds: 60})
+
+	for i := 0; i < 5; i++ {
+		resp, err := p.Fetch(FetchRequest{URL: upstream.URL})
+		if err != nil {
+			t.Fatalf("fetch %d: %v", i, err)
+		}
+		if resp.Body != "models" {
+			t.Errorf("fetch %d body = %q", i, resp.Body)
+		}
+	}
+	if got := atomic.LoadInt64(&calls); got != 1 {
+		t.Errorf("upstream called %d times, want 1", got)
+	}
+
+	// Different headers are a different cache key.
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL, Headers: map[string]string{"X-Key": "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt64(&calls); got != 2 {
+		t.Errorf("upstream called %d times, want 2", got)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	var calls int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	p.EnableCache(CacheConfig{TTLSeconds: 30})
+	now := time.Now()
+	p.cache.now = func() time.Time { return now }
+
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(31 * time.Second)
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt64(&calls); got != 2 {
+		t.Errorf("upstream called %d times, want 2 after TTL expiry", got)
+	}
+}
+
+func TestCacheCoalescesConcurrentFetches(t *testing.T) {
+	var calls int64
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		<-release
+		_, _ = w.Write([]byte("shared"))
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	p.EnableCache(CacheConfig{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := p.Fetch(FetchRequest{URL: upstream.URL})
+			if err != nil {
+				t.Errorf("coalesced fetch: %v", err)
+				return
+			}
+			if resp.Body != "shared" {
+				t.Errorf("body = %q", resp.Body)
+			}
+		}()
+	}
+	// Give the goroutines time to pile onto the same flight, then let the
+	// single upstream call finish.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&calls); got != 1 {
+		t.Errorf("upstream called %d times, want 1 (coalescing broken)", got)
+	}
+}
+
+func TestPOSTsAreNotCached(t *testing.T) {
+	var calls int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	p.EnableCache(CacheConfig{})
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.Fetch(FetchRequest{URL: upstream.URL, Method: http.MethodPost}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := atomic.LoadInt64(&calls); got != 3 {
+		t.Errorf("upstream called %d times, want 3 (POSTs must not cache)", got)
+	}
+}
@@ -0,0 +1,108 @@
+package fetchproxy
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// SessionCookie is the inspectable view of one stored cookie.
+type SessionCookie struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Domain  string `json:"domain,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Expires string `json:"expires,omitempty"`
+}
+
+// sessionJar wraps a cookiejar.Jar and records stored cookies by host so a
+// jar can be inspected (net/http/cookiejar cannot enumerate its contents).
+type sessionJar struct {
+	jar *cookiejar.Jar
+
+	mu     sync.Mutex
+	stored map[string][]SessionCookie
+}
+
+func newSessionJar() (*sessionJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionJar{jar: jar, stored: make(map[string][]SessionCookie)}, nil
+}
+
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, c := range cookies {
+		sc := SessionCookie{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path}
+		if !c.Expires.IsZero() {
+			sc.Expires = c.Expires.UTC().Format(time.RFC3339)
+		}
+		host := u.Hostname()
+		replaced := false
+		for i, existing := range j.stored[host] {
+			if existing.Name == sc.Name && existing.Path == sc.Path {
+				j.stored[host][i] = sc
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			j.stored[host] = append(j.stored[host], sc)
+		}
+	}
+}
+
+func (j *sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+func (j *sessionJar) snapshot() map[string][]SessionCookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	out := make(map[string][]SessionCookie, len(j.stored))
+	for host, cookies := range j.stored {
+		out[host] = append([]SessionCookie(nil), cookies...)
+	}
+	return out
+}
+
+// jarFor returns the jar for a cookie session, creating it on first use.
+func (p *FetchProxy) jarFor(session string) (*sessionJar, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if j, ok := p.jars[session]; ok {
+		return j, nil
+	}
+	j, err := newSessionJar()
+	if err != nil {
+		return nil, err
+	}
+	p.jars[session] = j
+	return j, nil
+}
+
+// SessionCookies returns the cookies stored for a session, grouped by host.
+// An unknown session yields an empty map.
+func (p *FetchProxy) SessionCookies(session string) map[string][]SessionCookie {
+	p.mu.RLock()
+	j, ok := p.jars[session]
+	p.mu.RUnlock()
+	if !ok {
+		return map[string][]SessionCookie{}
+	}
+	return j.snapshot()
+}
+
+// ClearCookieSession discards a session's jar entirely.
+func (p *FetchProxy) ClearCookieSession(session string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.jars, session)
+}
@@ -0,0 +1,57 @@
+package fetchproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCookieSessionPersistsAcrossFetches(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "secret", Path: "/"})
+		case "/me":
+			c, err := r.Cookie("sid")
+			if err != nil || c.Value != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL + "/login", CookieSession: "s1"}); err != nil {
+		t.Fatalf("login fetch: %v", err)
+	}
+
+	resp, err := p.Fetch(FetchRequest{URL: upstream.URL + "/me", CookieSession: "s1"})
+	if err != nil {
+		t.Fatalf("authed fetch: %v", err)
+	}
+	if resp.Status != http.StatusOK {
+		t.Errorf("cookie not replayed: status = %d, want 200", resp.Status)
+	}
+
+	// A different session does not see the cookie.
+	resp, err = p.Fetch(FetchRequest{URL: upstream.URL + "/me", CookieSession: "s2"})
+	if err != nil {
+		t.Fatalf("other-session fetch: %v", err)
+	}
+	if resp.Status != http.StatusUnauthorized {
+		t.Errorf("cookie leaked across sessions: status = %d, want 401", resp.Status)
+	}
+
+	// The jar is inspectable and clearable.
+	cookies := p.SessionCookies("s1")
+	if len(cookies) == 0 {
+		t.Fatal("SessionCookies returned no hosts")
+	}
+	p.ClearCookieSession("s1")
+	if got := p.SessionCookies("s1"); len(got) != 0 {
+		t.Errorf("jar not cleared: %v", got)
+	}
+}
@@ -0,0 +1,26 @@
+package fetchproxy
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// decompressReader wraps r to decode the given Content-Encoding. It returns
+// nil when the encoding is empty, "identity", or unrecognized, meaning the
+// body should pass through unchanged.
+func decompressReader(encoding string, r io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(r)
+	case "deflate":
+		return flate.NewReader(r), nil
+	case "br":
+		return brotli.NewReader(r), nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,82 @@
+package fetchproxy
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func compressedUpstream(t *testing.T, encoding string, payload []byte) *httptest.Server {
+	t.Helper()
+	var buf bytes.Buffer
+	switch encoding {
+	case "gzip":
+		w := gzip.NewWriter(&buf)
+		_, _ = w.Write(payload)
+		_ = w.Close()
+	case "deflate":
+		w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+		_, _ = w.Write(payload)
+		_ = w.Close()
+	case "br":
+		w := brotli.NewWriter(&buf)
+		_, _ = w.Write(payload)
+		_ = w.Close()
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", encoding)
+		_, _ = w.Write(buf.Bytes())
+	}))
+}
+
+func TestFetchDecompressesResponses(t *testing.T) {
+	payload := []byte(`{"message":"hello compressed world"}`)
+	for _, encoding := range []string{"gzip", "deflate", "br"} {
+		t.Run(encoding, func(t *testing.T) {
+			upstream := compressedUpstream(t, encoding, payload)
+			defer upstream.Close()
+
+			p := NewWithClient(upstream.Client())
+			resp, err := p.Fetch(FetchRequest{
+				URL:     upstream.URL,
+				Headers: map[string]string{"Accept-Encoding": encoding},
+			})
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+			if resp.Body != string(payload) {
+				t.Errorf("body = %q, want decoded payload", resp.Body)
+			}
+			if resp.Headers["Content-Encoding"] != "" {
+				t.Error("Content-Encoding header should be dropped after decoding")
+			}
+		})
+	}
+}
+
+func TestFetchDisableDecompression(t *testing.T) {
+	payload := []byte("raw bytes please")
+	upstream := compressedUpstream(t, "gzip", payload)
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	resp, err := p.Fetch(FetchRequest{
+		URL:                  upstream.URL,
+		Headers:              map[string]string{"Accept-Encoding": "gzip"},
+		DisableDecompression: true,
+	})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if resp.Headers["Content-Encoding"] != "gzip" {
+		t.Error("Content-Encoding header should be preserved when decompression is disabled")
+	}
+	if resp.Body == string(payload) {
+		t.Error("body was decompressed despite disableDecompression")
+	}
+}
@@ -0,0 +1,769 @@
+// Package fetchproxy implements the server-side fetch bridge used by the
+// frontend. The UI cannot issue cross-origin requests to arbitrary LLM
+// providers directly, so it hands a FetchRequest to this service and gets a
+// FetchResponse back.
+package fetchproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marschhuynh/nuvin-srv/internal/breaker"
+	"github.com/marschhuynh/nuvin-srv/internal/outbound"
+	"github.com/marschhuynh/nuvin-srv/internal/ratelimit"
+	"github.com/marschhuynh/nuvin-srv/internal/urlcheck"
+)
+
+// RateLimitError is returned when a fetch would exceed the configured limit
+// for its upstream host; handlers translate it into 429 with Retry-After.
+type RateLimitError struct {
+	Host       string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for host %s (retry in %s)", e.Host, e.RetryAfter.Round(time.Millisecond))
+}
+
+// CircuitOpenError is returned when a fetch targets a host whose circuit
+// breaker is open; handlers translate it into 503 with Retry-After.
+type CircuitOpenError struct {
+	Host       string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for host %s (retry in %s)", e.Host, e.RetryAfter.Round(time.Millisecond))
+}
+
+// BodyEncodingBase64 marks a request or response body as base64-encoded so
+// binary payloads survive JSON transport intact.
+const BodyEncodingBase64 = "base64"
+
+// Redirect policies accepted in FetchRequest.Redirect.
+const (
+	RedirectFollow = "follow"
+	RedirectManual = "manual"
+	RedirectError  = "error"
+)
+
+// errRedirectBlocked signals that a redirect occurred under the "error"
+// policy; http.Client wraps it in a *url.Error.
+var errRedirectBlocked = fmt.Errorf("redirect blocked by policy")
+
+// FetchRequest describes an outgoing HTTP request on behalf of the frontend.
+type FetchRequest struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	// BodyEncoding set to "base64" means Body is base64-encoded binary; the
+	// decoded bytes are sent upstream and the response body comes back
+	// base64-encoded as well.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+	// RequestID, when set, registers the fetch so it can be cancelled
+	// mid-flight via Abort.
+	RequestID string `json:"requestId,omitempty"`
+	// TimeoutMs overrides the client's default timeout for this request.
+	// Zero keeps the default; use a large value for long LLM generations.
+	TimeoutMs int `json:"timeoutMs,omitempty"`
+	// Retries is how many times a failed attempt is retried (0 = no
+	// retries). Attempts back off exponentially with jitter and honor
+	// upstream Retry-After headers.
+	Retries int `json:"retries,omitempty"`
+	// RetryOn lists the response status codes that trigger a retry.
+	// Empty defaults to 429, 502, and 503. Network errors always retry.
+	RetryOn []int `json:"retryOn,omitempty"`
+	// Stream forces chunked streaming of the response through the HTTP
+	// fetch handler even when the upstream Content-Type is not a
+	// recognized streaming type.
+	Stream bool `json:"stream,omitempty"`
+	// DisableDecompression leaves compressed response bodies as-is instead
+	// of transparently decoding gzip/deflate/brotli Content-Encoding.
+	DisableDecompression bool `json:"disableDecompression,omitempty"`
+	// MaxBodyBytes caps how many response bytes the proxy will accept.
+	// Zero means no limit. Oversized responses fail the fetch.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+	// DownloadTo streams the response body to a server-managed temp file
+	// instead of buffering it; the response carries the file path.
+	DownloadTo bool `json:"downloadTo,omitempty"`
+	// Signing makes the proxy sign the request (AWS SigV4 or HMAC) before
+	// sending it, keeping signing credentials server-side.
+	Signing *SigningOptions `json:"signing,omitempty"`
+	// TLS customizes upstream TLS (client certs, CA bundle, skip-verify)
+	// for this request.
+	TLS *outbound.TLSOptions `json:"tls,omitempty"`
+	// CookieSession names a server-side cookie jar. Fetches sharing the
+	// same session ID accumulate and send cookies like a browser session.
+	CookieSession string `json:"cookieSession,omitempty"`
+	// Redirect selects redirect handling, matching browser fetch semantics:
+	// "follow" (default) follows redirects, "manual" returns the 30x
+	// response itself, "error" fails the fetch on any redirect.
+	Redirect string `json:"redirect,omitempty"`
+	// Parts, when non-empty, makes the proxy build a multipart/form-data
+	// request body from the parts and ignore Body.
+	Parts []Part `json:"parts,omitempty"`
+	// Endpoint is an optional logical endpoint name. When set, a body
+	// template registered under the same name is merged into the request
+	// body before the request is sent.
+	Endpoint string `json:"endpoint,omitempty"`
+	// UploadID sends a body previously staged chunk by chunk via
+	// /fetch/upload instead of Body. The staged file is streamed, so the
+	// body never has to fit in memory.
+	UploadID string `json:"uploadId,omitempty"`
+}
+
+// Part is one field of a multipart/form-data upload. Content is always
+// base64-encoded so binary files survive JSON transport.
+type Part struct {
+	Name        string `json:"name"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Content     string `json:"content"`
+}
+
+// FetchResponse carries the upstream response back to the frontend.
+type FetchResponse struct {
+	Status int `json:"status"`
+	// Headers collapses each header to its first value, kept for existing
+	// callers. RawHeaders preserves every value (Set-Cookie especially).
+	Headers    map[string]string   `json:"headers"`
+	RawHeaders map[string][]string `json:"rawHeaders"`
+	Body       string              `json:"body"`
+	// BodyEncoding is "base64" when Body holds base64-encoded binary data.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+	// FilePath is set instead of Body when the request used DownloadTo.
+	FilePath string `json:"filePath,omitempty"`
+	// Size is the number of body bytes written when DownloadTo was used.
+	Size int64 `json:"size,omitempty"`
+}
+
+// FetchProxy performs HTTP requests on behalf of the frontend. It is bound
+// into the Wails runtime and also exposed over HTTP by handlers.FetchHandler.
+type FetchProxy struct {
+	client *http.Client
+
+	// breaker fails fetches fast while an upstream host keeps erroring.
+	breaker *breaker.Breaker
+
+	// sleep is swapped out in tests so retry backoff does not slow them.
+	sleep func(time.Duration)
+
+	// Connection reuse counters, updated atomically per fetch.
+	conns       int64
+	reusedConns int64
+
+	mu        sync.RWMutex
+	capture   CaptureFunc
+	cache     *fetchCache
+	policy    AccessPolicy
+	limiter   *ratelimit.Limiter
+	templates map[string]map[string]any
+	jars      map[string]*sessionJar
+	inflight  map[string]context.CancelFunc
+	secrets   map[string]originSecret
+	uploads   map[string]*stagedUpload
+}
+
+// defaultRetryStatuses are the response codes retried when RetryOn is empty.
+var defaultRetryStatuses = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable}
+
+// New returns a FetchProxy whose client shares one keep-alive transport
+// across all fetches.
+func New() *FetchProxy {
+	return NewWithClient(&http.Client{
+		Timeout:   120 * time.Second,
+		Transport: newTransport(TransportConfig{}),
+	})
+}
+
+// NewWithClient returns a FetchProxy that sends requests through client.
+// Tests use this to stub the upstream.
+func NewWithClient(client *http.Client) *FetchProxy {
+	return &FetchProxy{
+		client:    client,
+		breaker:   breaker.New(),
+		sleep:     time.Sleep,
+		templates: make(map[string]map[string]any),
+		jars:      make(map[string]*sessionJar),
+		inflight:  make(map[string]context.CancelFunc),
+		secrets:   make(map[string]originSecret),
+		uploads:   make(map[string]*stagedUpload),
+	}
+}
+
+// RegisterBodyTemplate registers default body fields for a logical endpoint.
+// Template fields are merged into outgoing request bodies fill-if-absent:
+// fields the client sets explicitly always win. Passing a nil template
+// removes a previously registered one.
+func (p *FetchProxy) RegisterBodyTemplate(endpoint string, template map[string]any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if template == nil {
+		delete(p.templates, endpoint)
+		return
+	}
+	p.templates[endpoint] = template
+}
+
+// CaptureFunc observes every completed fetch (capture.Store implements it).
+type CaptureFunc func(req FetchRequest, resp *FetchResponse, err error)
+
+// SetCapture installs fn to record fetches; nil disables recording.
+func (p *FetchProxy) SetCapture(fn CaptureFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.capture = fn
+}
+
+// Fetch executes the request and returns the upstream response.
+func (p *FetchProxy) Fetch(req FetchRequest) (*FetchResponse, error) {
+	p.mu.RLock()
+	cache := p.cache
+	p.mu.RUnlock()
+
+	var resp *FetchResponse
+	var err error
+	if cache != nil && cacheable(req) {
+		resp, err = cache.fetchCached(cacheKey(req), func() (*FetchResponse, error) {
+			return p.fetch(req)
+		})
+	} else {
+		resp, err = p.fetch(req)
+	}
+
+	p.mu.RLock()
+	capture := p.capture
+	p.mu.RUnlock()
+	if capture != nil {
+		capture(req, resp, err)
+	}
+	return resp, err
+}
+
+func (p *FetchProxy) fetch(req FetchRequest) (*FetchResponse, error) {
+	resp, err := p.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return p.ReadResponse(req, resp)
+}
+
+// SetHostLimit rate-limits fetches to host with a token bucket; RPS 0
+// removes the limit.
+func (p *FetchProxy) SetHostLimit(host string, limit ratelimit.HostLimit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.limiter == nil {
+		p.limiter = ratelimit.NewLimiter()
+	}
+	p.limiter.SetLimit(host, limit)
+}
+
+// HostLimits reports the configured per-host fetch limits.
+func (p *FetchProxy) HostLimits() map[string]ratelimit.HostLimit {
+	p.mu.RLock()
+	limiter := p.limiter
+	p.mu.RUnlock()
+	if limiter == nil {
+		return map[string]ratelimit.HostLimit{}
+	}
+	return limiter.Limits()
+}
+
+// SetHostLimits replaces the whole per-host limit table; hosts absent from
+// limits lose theirs.
+func (p *FetchProxy) SetHostLimits(limits map[string]ratelimit.HostLimit) {
+	for host := range p.HostLimits() {
+		if _, ok := limits[host]; !ok {
+			p.SetHostLimit(host, ratelimit.HostLimit{})
+		}
+	}
+	for host, limit := range limits {
+		p.SetHostLimit(host, limit)
+	}
+}
+
+// Do executes the request and returns the raw upstream response with a live
+// body, for callers that stream instead of buffering (e.g. SSE/NDJSON
+// pass-through). Closing the body releases the request's abort registration.
+// Most callers want Fetch.
+func (p *FetchProxy) Do(req FetchRequest) (*http.Response, error) {
+	normalized, err := urlcheck.Normalize(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = normalized
+
+	var host string
+	if u, err := url.Parse(req.URL); err == nil {
+		host = u.Hostname()
+	}
+
+	p.mu.RLock()
+	limiter := p.limiter
+	p.mu.RUnlock()
+	if limiter != nil && host != "" {
+		if ok, retryAfter := limiter.Acquire(host); !ok {
+			return nil, &RateLimitError{Host: host, RetryAfter: retryAfter}
+		}
+	}
+	if host != "" {
+		if ok, retryAfter := p.breaker.Allow(host); !ok {
+			return nil, &CircuitOpenError{Host: host, RetryAfter: retryAfter}
+		}
+	}
+
+	body, err := p.applyBodyTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rawBody := []byte(body)
+	if req.BodyEncoding == BodyEncodingBase64 {
+		rawBody, err = base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, fmt.Errorf("decoding base64 request body: %w", err)
+		}
+	}
+
+	var multipartType string
+	if len(req.Parts) > 0 {
+		rawBody, multipartType, err = buildMultipartBody(req.Parts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.UploadID != "" && req.Signing != nil {
+		// Signing hashes the full body, which defeats streaming it.
+		return nil, fmt.Errorf("request signing is not supported with staged uploads")
+	}
+
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	client, err := p.clientForRedirectPolicy(req.Redirect)
+	if err != nil {
+		return nil, err
+	}
+	if req.CookieSession != "" {
+		jar, err := p.jarFor(req.CookieSession)
+		if err != nil {
+			return nil, fmt.Errorf("creating cookie session %q: %w", req.CookieSession, err)
+		}
+		if client == p.client {
+			c := *p.client
+			client = &c
+		}
+		client.Jar = jar
+	}
+	if req.TimeoutMs > 0 {
+		if client == p.client {
+			c := *p.client
+			client = &c
+		}
+		client.Timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+	if req.TLS != nil {
+		tlsCfg, err := req.TLS.Config()
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls options: %w", err)
+		}
+		if client == p.client {
+			c := *p.client
+			client = &c
+		}
+		base, ok := client.Transport.(*http.Transport)
+		if !ok || base == nil {
+			base = newTransport(TransportConfig{})
+		}
+		custom := base.Clone()
+		custom.TLSClientConfig = tlsCfg
+		client.Transport = custom
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cleanup := func() {
+		cancel()
+		if req.RequestID != "" {
+			p.unregisterInflight(req.RequestID)
+		}
+	}
+	if req.RequestID != "" {
+		if err := p.registerInflight(req.RequestID, cancel); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	retryOn := req.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = defaultRetryStatuses
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			atomic.AddInt64(&p.conns, 1)
+			if info.Reused {
+				atomic.AddInt64(&p.reusedConns, 1)
+			}
+		},
+	}
+	traceCtx := httptrace.WithClientTrace(ctx, trace)
+
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		var bodyReader io.Reader = bytes.NewReader(rawBody)
+		contentLength := int64(len(rawBody))
+		if req.UploadID != "" {
+			// Reopen per attempt so retries can replay the staged body.
+			f, size, err := p.openUpload(req.UploadID)
+			if err != nil {
+				cleanup()
+				return nil, err
+			}
+			bodyReader, contentLength = f, size
+		}
+		httpReq, err := http.NewRequestWithContext(traceCtx, method, req.URL, bodyReader)
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("invalid fetch request: %w", err)
+		}
+		httpReq.ContentLength = contentLength
+		for k, v := range req.Headers {
+			httpReq.Header.Set(k, v)
+		}
+		// Connection-scoped headers must not travel with the proxied hop.
+		StripHopByHop(httpReq.Header)
+		if secret, ok := p.secretFor(req.URL); ok && httpReq.Header.Get(secret.header) == "" {
+			// A header the client set explicitly wins over the vault.
+			httpReq.Header.Set(secret.header, secret.value)
+		}
+		if multipartType != "" {
+			// The generated boundary must win over any client-set Content-Type.
+			httpReq.Header.Set("Content-Type", multipartType)
+		}
+		if req.Signing != nil {
+			if err := signRequest(httpReq, rawBody, req.Signing, time.Now()); err != nil {
+				cleanup()
+				return nil, fmt.Errorf("signing fetch request: %w", err)
+			}
+		}
+
+		resp, err = client.Do(httpReq)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				cleanup()
+				return nil, fmt.Errorf("fetch aborted: %s", req.RequestID)
+			}
+			if errors.Is(err, errRedirectBlocked) {
+				cleanup()
+				return nil, fmt.Errorf("fetch failed: upstream responded with a redirect and redirect policy is %q", RedirectError)
+			}
+			if host != "" {
+				p.breaker.Report(host, false)
+			}
+			if attempt < req.Retries {
+				p.sleep(retryDelay(attempt, ""))
+				continue
+			}
+			cleanup()
+			return nil, fmt.Errorf("fetch failed: %w", err)
+		}
+		if attempt < req.Retries && statusIn(resp.StatusCode, retryOn) {
+			retryAfter := resp.Header.Get("Retry-After")
+			resp.Body.Close()
+			p.sleep(retryDelay(attempt, retryAfter))
+			continue
+		}
+		break
+	}
+	if host != "" {
+		p.breaker.Report(host, true)
+	}
+
+	// Tie the abort registration and context to the body's lifetime.
+	resp.Body = &cleanupReadCloser{ReadCloser: resp.Body, cleanup: cleanup}
+	return resp, nil
+}
+
+// cleanupReadCloser runs cleanup exactly once when the body is closed.
+type cleanupReadCloser struct {
+	io.ReadCloser
+	cleanup func()
+	once    sync.Once
+}
+
+func (c *cleanupReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.once.Do(c.cleanup)
+	return err
+}
+
+// ReadResponse buffers and post-processes an upstream response obtained from
+// Do according to the request's body options.
+func (p *FetchProxy) ReadResponse(req FetchRequest, resp *http.Response) (*FetchResponse, error) {
+	headers := make(map[string]string, len(resp.Header))
+	rawHeaders := make(map[string][]string, len(resp.Header))
+	hop := HopByHop(resp.Header)
+	for k, vs := range resp.Header {
+		if hop[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		headers[k] = resp.Header.Get(k)
+		rawHeaders[k] = append([]string(nil), vs...)
+	}
+	out := &FetchResponse{
+		Status:     resp.StatusCode,
+		Headers:    headers,
+		RawHeaders: rawHeaders,
+	}
+
+	bodyReader := io.Reader(resp.Body)
+	if !req.DisableDecompression {
+		decoded, err := decompressReader(resp.Header.Get("Content-Encoding"), bodyReader)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s response body: %w", resp.Header.Get("Content-Encoding"), err)
+		}
+		if decoded != nil {
+			bodyReader = decoded
+			// The body the client sees is no longer encoded.
+			delete(out.Headers, "Content-Encoding")
+			delete(out.Headers, "Content-Length")
+			delete(out.RawHeaders, "Content-Encoding")
+			delete(out.RawHeaders, "Content-Length")
+		}
+	}
+	if req.MaxBodyBytes > 0 {
+		// Read one extra byte so we can tell "exactly at the limit" from
+		// "over the limit".
+		bodyReader = io.LimitReader(bodyReader, req.MaxBodyBytes+1)
+	}
+
+	if req.DownloadTo {
+		f, err := os.CreateTemp("", "nuvin-fetch-*")
+		if err != nil {
+			return nil, fmt.Errorf("creating download file: %w", err)
+		}
+		size, err := io.Copy(f, bodyReader)
+		closeErr := f.Close()
+		if err == nil {
+			err = closeErr
+		}
+		if err == nil && req.MaxBodyBytes > 0 && size > req.MaxBodyBytes {
+			err = fmt.Errorf("response body exceeds maxBodyBytes (%d)", req.MaxBodyBytes)
+		}
+		if err != nil {
+			os.Remove(f.Name())
+			return nil, fmt.Errorf("downloading response body: %w", err)
+		}
+		out.FilePath = f.Name()
+		out.Size = size
+		return out, nil
+	}
+
+	respBody, err := io.ReadAll(bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	if req.MaxBodyBytes > 0 && int64(len(respBody)) > req.MaxBodyBytes {
+		return nil, fmt.Errorf("response body exceeds maxBodyBytes (%d)", req.MaxBodyBytes)
+	}
+	// Base64-encode the response when the client asked for it, or when the
+	// body is not valid UTF-8 and would be corrupted by JSON transport.
+	if req.BodyEncoding == BodyEncodingBase64 || !utf8.Valid(respBody) {
+		out.Body = base64.StdEncoding.EncodeToString(respBody)
+		out.BodyEncoding = BodyEncodingBase64
+	} else {
+		out.Body = string(respBody)
+	}
+	return out, nil
+}
+
+// statusIn reports whether status appears in codes.
+func statusIn(status int, codes []int) bool {
+	for _, c := range codes {
+		if c == status {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay computes the pause before the next attempt: exponential backoff
+// from 250ms with up to 50% random jitter, capped at 10s. A parseable
+// Retry-After (delta seconds) from the upstream takes precedence.
+func retryDelay(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	backoff := 250 * time.Millisecond << uint(attempt)
+	if backoff > 10*time.Second {
+		backoff = 10 * time.Second
+	}
+	jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+	return backoff + jitter
+}
+
+// registerInflight makes a running fetch abortable under requestID.
+func (p *FetchProxy) registerInflight(requestID string, cancel context.CancelFunc) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, exists := p.inflight[requestID]; exists {
+		return fmt.Errorf("a fetch with requestId %q is already in flight", requestID)
+	}
+	p.inflight[requestID] = cancel
+	return nil
+}
+
+func (p *FetchProxy) unregisterInflight(requestID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.inflight, requestID)
+}
+
+// Abort cancels the in-flight fetch registered under requestID. It reports
+// whether a matching fetch was found.
+func (p *FetchProxy) Abort(requestID string) bool {
+	p.mu.Lock()
+	cancel, ok := p.inflight[requestID]
+	p.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// clientForRedirectPolicy returns the client to use for the given redirect
+// policy. "manual" and "error" use a shallow copy of the shared client (the
+// Transport is still reused) with a policy-specific CheckRedirect.
+func (p *FetchProxy) clientForRedirectPolicy(policy string) (*http.Client, error) {
+	switch policy {
+	case "", RedirectFollow:
+		return p.client, nil
+	case RedirectManual:
+		c := *p.client
+		c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		return &c, nil
+	case RedirectError:
+		c := *p.client
+		c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return errRedirectBlocked
+		}
+		return &c, nil
+	default:
+		return nil, fmt.Errorf("invalid redirect policy %q (want follow, manual, or error)", policy)
+	}
+}
+
+// buildMultipartBody assembles a multipart/form-data body from parts and
+// returns it with the Content-Type carrying the generated boundary.
+func buildMultipartBody(parts []Part) ([]byte, string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for i, part := range parts {
+		if part.Name == "" {
+			return nil, "", fmt.Errorf("multipart part %d: name is required", i)
+		}
+		content, err := base64.StdEncoding.DecodeString(part.Content)
+		if err != nil {
+			return nil, "", fmt.Errorf("multipart part %q: decoding base64 content: %w", part.Name, err)
+		}
+
+		header := make(textproto.MIMEHeader)
+		disposition := fmt.Sprintf(`form-data; name=%q`, part.Name)
+		if part.Filename != "" {
+			disposition += fmt.Sprintf(`; filename=%q`, part.Filename)
+		}
+		header.Set("Content-Disposition", disposition)
+		if part.ContentType != "" {
+			header.Set("Content-Type", part.ContentType)
+		}
+
+		w, err := mw.CreatePart(header)
+		if err != nil {
+			return nil, "", fmt.Errorf("multipart part %q: %w", part.Name, err)
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, "", fmt.Errorf("multipart part %q: %w", part.Name, err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", fmt.Errorf("finalizing multipart body: %w", err)
+	}
+	return buf.Bytes(), mw.FormDataContentType(), nil
+}
+
+// applyBodyTemplate merges the endpoint's registered template into the
+// request body. Only JSON object bodies participate; anything else is sent
+// unchanged.
+func (p *FetchProxy) applyBodyTemplate(req FetchRequest) (string, error) {
+	if req.Endpoint == "" {
+		return req.Body, nil
+	}
+	p.mu.RLock()
+	template, ok := p.templates[req.Endpoint]
+	p.mu.RUnlock()
+	if !ok {
+		return req.Body, nil
+	}
+
+	body := make(map[string]any)
+	if strings.TrimSpace(req.Body) != "" {
+		if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+			// Non-object body: templates only apply to JSON objects.
+			return req.Body, nil
+		}
+	}
+
+	mergeAbsent(body, template)
+
+	merged, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("merging body template for %q: %w", req.Endpoint, err)
+	}
+	return string(merged), nil
+}
+
+// mergeAbsent copies template fields into dst that dst does not already set.
+// Nested objects are merged recursively so a client overriding one nested
+// field keeps the template's siblings.
+func mergeAbsent(dst map[string]any, template map[string]any) {
+	for k, tv := range template {
+		dv, exists := dst[k]
+		if !exists {
+			dst[k] = tv
+			continue
+		}
+		dstMap, dstOK := dv.(map[string]any)
+		tmplMap, tmplOK := tv.(map[string]any)
+		if dstOK && tmplOK {
+			mergeAbsent(dstMap, tmplMap)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package fetchproxy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/ratelimit"
+)
+
+func TestFetchMergesBodyTemplate(t *testing.T) {
+	var received map[string]any
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Fatalf("upstream got invalid JSON: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	p.RegisterBodyTemplate("chat", map[string]any{
+		"model":       "gpt-4o",
+		"temperature": 0.2,
+	})
+
+	_, err := p.Fetch(FetchRequest{
+		URL:      upstream.URL,
+		Method:   http.MethodPost,
+		Endpoint: "chat",
+		Body:     `{"messages":[{"role":"user","content":"hi"}],"temperature":0.9}`,
+	})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if got := received["model"]; got != "gpt-4o" {
+		t.Errorf("template field model = %v, want gpt-4o", got)
+	}
+	if got := received["temperature"]; got != 0.9 {
+		t.Errorf("client field temperature = %v, want 0.9 (client must win)", got)
+	}
+	if _, ok := received["messages"]; !ok {
+		t.Error("client field messages missing from merged body")
+	}
+}
+
+func TestFetchBase64BodyRoundTrip(t *testing.T) {
+	binary := make([]byte, 256)
+	for i := range binary {
+		binary[i] = byte(i)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ := io.ReadAll(r.Body)
+		if !bytes.Equal(got, binary) {
+			t.Errorf("upstream received corrupted body (%d bytes)", len(got))
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(binary)
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	resp, err := p.Fetch(FetchRequest{
+		URL:          upstream.URL,
+		Method:       http.MethodPost,
+		Body:         base64.StdEncoding.EncodeToString(binary),
+		BodyEncoding: BodyEncodingBase64,
+	})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if resp.BodyEncoding != BodyEncodingBase64 {
+		t.Fatalf("response bodyEncoding = %q, want base64", resp.BodyEncoding)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(resp.Body)
+	if err != nil {
+		t.Fatalf("response body is not valid base64: %v", err)
+	}
+	if !bytes.Equal(decoded, binary) {
+		t.Error("response body corrupted in round trip")
+	}
+}
+
+func TestFetchBuildsMultipartBody(t *testing.T) {
+	fileContent := []byte{0x89, 0x50, 0x4e, 0x47, 0x00, 0x01}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("upstream could not parse multipart body: %v", err)
+		}
+		if got := r.FormValue("purpose"); got != "vision" {
+			t.Errorf("field purpose = %q, want vision", got)
+		}
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("file part missing: %v", err)
+		}
+		defer f.Close()
+		if header.Filename != "image.png" {
+			t.Errorf("filename = %q, want image.png", header.Filename)
+		}
+		if ct := header.Header.Get("Content-Type"); ct != "image/png" {
+			t.Errorf("part content type = %q, want image/png", ct)
+		}
+		got, _ := io.ReadAll(f)
+		if !bytes.Equal(got, fileContent) {
+			t.Error("file content corrupted")
+		}
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	_, err := p.Fetch(FetchRequest{
+		URL:    upstream.URL,
+		Method: http.MethodPost,
+		Parts: []Part{
+			{Name: "purpose", Content: base64.StdEncoding.EncodeToString([]byte("vision"))},
+			{
+				Name:        "file",
+				Filename:    "image.png",
+				ContentType: "image/png",
+				Content:     base64.StdEncoding.EncodeToString(fileContent),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+}
+
+func TestFetchRedirectPolicies(t *testing.T) {
+	var upstream *httptest.Server
+	upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			http.Redirect(w, r, upstream.URL+"/final", http.StatusFound)
+		case "/final":
+			_, _ = w.Write([]byte("arrived"))
+		}
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+
+	// Default: follow.
+	resp, err := p.Fetch(FetchRequest{URL: upstream.URL + "/start"})
+	if err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if resp.Status != http.StatusOK || resp.Body != "arrived" {
+		t.Errorf("follow: status=%d body=%q, want 200 arrived", resp.Status, resp.Body)
+	}
+
+	// Manual: the 302 itself comes back with its Location header.
+	resp, err = p.Fetch(FetchRequest{URL: upstream.URL + "/start", Redirect: RedirectManual})
+	if err != nil {
+		t.Fatalf("manual: %v", err)
+	}
+	if resp.Status != http.StatusFound {
+		t.Errorf("manual: status = %d, want 302", resp.Status)
+	}
+	if resp.Headers["Location"] == "" {
+		t.Error("manual: Location header missing")
+	}
+
+	// Error: the fetch fails.
+	if _, err = p.Fetch(FetchRequest{URL: upstream.URL + "/start", Redirect: RedirectError}); err == nil {
+		t.Error("error policy: expected a fetch error, got none")
+	}
+
+	// Unknown policy is rejected.
+	if _, err = p.Fetch(FetchRequest{URL: upstream.URL + "/start", Redirect: "bogus"}); err == nil {
+		t.Error("invalid policy: expected an error, got none")
+	}
+}
+
+func TestFetchPerRequestTimeout(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	p := NewWithClient(slow.Client())
+	start := time.Now()
+	_, err := p.Fetch(FetchRequest{URL: slow.URL, TimeoutMs: 50})
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("timeout took %v, want ~50ms", elapsed)
+	}
+}
+
+func TestFetchAbortCancelsInFlightRequest(t *testing.T) {
+	started := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	p := NewWithClient(slow.Client())
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Fetch(FetchRequest{URL: slow.URL, RequestID: "req-1"})
+		done <- err
+	}()
+
+	<-started
+	if !p.Abort("req-1") {
+		t.Fatal("Abort did not find the in-flight request")
+	}
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("aborted fetch returned no error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fetch did not return after abort")
+	}
+
+	if p.Abort("req-1") {
+		t.Error("Abort found a request that already finished")
+	}
+}
+
+func TestFetchMaxBodyBytes(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("x"), 1024))
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL, MaxBodyBytes: 100}); err == nil {
+		t.Error("oversized response did not fail")
+	}
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL, MaxBodyBytes: 1024}); err != nil {
+		t.Errorf("response exactly at the limit failed: %v", err)
+	}
+}
+
+func TestFetchDownloadToFile(t *testing.T) {
+	payload := bytes.Repeat([]byte("data"), 4096)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	resp, err := p.Fetch(FetchRequest{URL: upstream.URL, DownloadTo: true})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if resp.FilePath == "" {
+		t.Fatal("FilePath not set in downloadTo mode")
+	}
+	defer os.Remove(resp.FilePath)
+	if resp.Body != "" {
+		t.Error("Body should be empty in downloadTo mode")
+	}
+	if resp.Size != int64(len(payload)) {
+		t.Errorf("Size = %d, want %d", resp.Size, len(payload))
+	}
+	got, err := os.ReadFile(resp.FilePath)
+	if err != nil {
+		t.Fatalf("reading downloaded file: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Error("downloaded file content mismatch")
+	}
+}
+
+func TestFetchRetriesTransientStatuses(t *testing.T) {
+	var calls int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	var slept []time.Duration
+	p.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	resp, err := p.Fetch(FetchRequest{URL: upstream.URL, Retries: 3})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if resp.Status != http.StatusOK || resp.Body != "ok" {
+		t.Errorf("status=%d body=%q, want 200 ok", resp.Status, resp.Body)
+	}
+	if calls != 3 {
+		t.Errorf("upstream called %d times, want 3", calls)
+	}
+	if len(slept) != 2 {
+		t.Errorf("slept %d times between attempts, want 2", len(slept))
+	}
+}
+
+func TestFetchRetriesExhausted(t *testing.T) {
+	var calls int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	p.sleep = func(time.Duration) {}
+
+	resp, err := p.Fetch(FetchRequest{URL: upstream.URL, Retries: 2})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if resp.Status != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want the final 429 surfaced", resp.Status)
+	}
+	if calls != 3 {
+		t.Errorf("upstream called %d times, want 3 (1 + 2 retries)", calls)
+	}
+}
+
+func TestFetchNoRetriesByDefault(t *testing.T) {
+	var calls int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL}); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("upstream called %d times, want 1", calls)
+	}
+}
+
+func TestFetchRawHeadersPreserveMultipleValues(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Set-Cookie", "a=1; Path=/")
+		w.Header().Add("Set-Cookie", "b=2; Path=/")
+		w.Header().Add("X-Single", "only")
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	resp, err := p.Fetch(FetchRequest{URL: upstream.URL})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if got := resp.RawHeaders["Set-Cookie"]; len(got) != 2 {
+		t.Errorf("rawHeaders Set-Cookie = %v, want both cookies", got)
+	}
+	// The flat map still carries the first value for existing callers.
+	if resp.Headers["Set-Cookie"] != "a=1; Path=/" {
+		t.Errorf("headers Set-Cookie = %q", resp.Headers["Set-Cookie"])
+	}
+	if got := resp.RawHeaders["X-Single"]; len(got) != 1 || got[0] != "only" {
+		t.Errorf("rawHeaders X-Single = %v", got)
+	}
+}
+
+func TestFetchHostRateLimit(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	p.SetHostLimit("127.0.0.1", ratelimit.HostLimit{RPS: 1, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.Fetch(FetchRequest{URL: upstream.URL}); err != nil {
+			t.Fatalf("burst fetch %d: %v", i+1, err)
+		}
+	}
+	_, err := p.Fetch(FetchRequest{URL: upstream.URL})
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("err = %v, want RateLimitError", err)
+	}
+	if rateErr.Host != "127.0.0.1" || rateErr.RetryAfter <= 0 {
+		t.Errorf("unexpected RateLimitError: %+v", rateErr)
+	}
+}
+
+func TestFetchWithoutTemplateLeavesBodyUntouched(t *testing.T) {
+	var received string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	_, err := p.Fetch(FetchRequest{URL: upstream.URL, Method: http.MethodPost, Body: "not json"})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if received != "not json" {
+		t.Errorf("body = %q, want unchanged", received)
+	}
+}
@@ -0,0 +1,48 @@
+package fetchproxy
+
+import (
+	"net/http"
+	"strings"
+)
+
+// hopByHopHeaders are always hop-by-hop per RFC 7230 §6.1: they describe
+// one connection and must not be forwarded by a proxy.
+var hopByHopHeaders = map[string]bool{
+	"Connection":          true,
+	"Proxy-Connection":    true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+}
+
+// HopByHop returns the hop-by-hop header names for h in canonical form:
+// the fixed RFC 7230 set plus any header nominated by h's Connection
+// header, which is equally connection-scoped.
+func HopByHop(h http.Header) map[string]bool {
+	hop := make(map[string]bool, len(hopByHopHeaders)+2)
+	for k := range hopByHopHeaders {
+		hop[k] = true
+	}
+	for _, v := range h.Values("Connection") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				hop[http.CanonicalHeaderKey(name)] = true
+			}
+		}
+	}
+	return hop
+}
+
+// StripHopByHop removes hop-by-hop headers from h in place.
+func StripHopByHop(h http.Header) {
+	hop := HopByHop(h)
+	for k := range h {
+		if hop[http.CanonicalHeaderKey(k)] {
+			h.Del(k)
+		}
+	}
+}
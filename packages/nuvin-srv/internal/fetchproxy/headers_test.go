@@ -0,0 +1,42 @@
+package fetchproxy
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHopByHopIncludesConnectionNominated(t *testing.T) {
+	h := http.Header{
+		"Connection":     {"keep-alive, X-Custom-Token"},
+		"X-Custom-Token": {"secret"},
+		"Authorization":  {"Bearer x"},
+	}
+	hop := HopByHop(h)
+	for _, name := range []string{"Connection", "Keep-Alive", "Transfer-Encoding", "X-Custom-Token"} {
+		if !hop[name] {
+			t.Errorf("%s not marked hop-by-hop", name)
+		}
+	}
+	if hop["Authorization"] {
+		t.Error("Authorization wrongly marked hop-by-hop")
+	}
+}
+
+func TestStripHopByHop(t *testing.T) {
+	h := http.Header{
+		"Connection":    {"x-per-hop"},
+		"X-Per-Hop":     {"1"},
+		"Keep-Alive":    {"timeout=5"},
+		"Content-Type":  {"application/json"},
+		"Authorization": {"Bearer x"},
+	}
+	StripHopByHop(h)
+	for _, name := range []string{"Connection", "X-Per-Hop", "Keep-Alive"} {
+		if h.Get(name) != "" {
+			t.Errorf("%s survived stripping", name)
+		}
+	}
+	if h.Get("Content-Type") == "" || h.Get("Authorization") == "" {
+		t.Error("end-to-end headers were stripped")
+	}
+}
@@ -0,0 +1,61 @@
+package fetchproxy
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// originSecret is a credential bound to one upstream origin. The raw value
+// stays server-side; fetches to that origin get the header injected so keys
+// never travel through the browser layer.
+type originSecret struct {
+	header string
+	value  string
+}
+
+// SetOriginSecret stores a credential for origin ("https://api.example.com").
+// header defaults to Authorization; an empty value removes the secret.
+func (p *FetchProxy) SetOriginSecret(origin, header, value string) {
+	key := canonicalOrigin(origin)
+	if header == "" {
+		header = "Authorization"
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if value == "" {
+		delete(p.secrets, key)
+		return
+	}
+	p.secrets[key] = originSecret{header: header, value: value}
+}
+
+// SecretOrigins lists the origins that have a stored credential, sorted.
+// Values are deliberately not exposed.
+func (p *FetchProxy) SecretOrigins() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	out := make([]string, 0, len(p.secrets))
+	for origin := range p.secrets {
+		out = append(out, origin)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// secretFor returns the credential for the request URL's origin, if any.
+func (p *FetchProxy) secretFor(rawURL string) (originSecret, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return originSecret{}, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	s, ok := p.secrets[strings.ToLower(u.Scheme+"://"+u.Host)]
+	return s, ok
+}
+
+func canonicalOrigin(origin string) string {
+	origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+	return strings.ToLower(origin)
+}
@@ -0,0 +1,63 @@
+package fetchproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginSecretInjection(t *testing.T) {
+	var gotAuth, gotKey string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotKey = r.Header.Get("X-Api-Key")
+	}))
+	defer upstream.Close()
+
+	p := New()
+	p.SetOriginSecret(upstream.URL, "", "Bearer vault-key")
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL + "/v1/chat"}); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if gotAuth != "Bearer vault-key" {
+		t.Errorf("Authorization = %q, want injected vault value", gotAuth)
+	}
+
+	// An explicit client header wins over the vault.
+	if _, err := p.Fetch(FetchRequest{
+		URL:     upstream.URL,
+		Headers: map[string]string{"Authorization": "Bearer mine"},
+	}); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if gotAuth != "Bearer mine" {
+		t.Errorf("Authorization = %q, want client-supplied value", gotAuth)
+	}
+
+	// Custom header name, and other origins stay untouched.
+	p.SetOriginSecret(upstream.URL, "X-Api-Key", "k-123")
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL}); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if gotKey != "k-123" {
+		t.Errorf("X-Api-Key = %q, want vault value", gotKey)
+	}
+
+	if _, ok := p.secretFor("https://other.example.com/x"); ok {
+		t.Error("secretFor matched an origin with no stored credential")
+	}
+}
+
+func TestSecretOriginsNeverExposesValues(t *testing.T) {
+	p := New()
+	p.SetOriginSecret("https://API.Example.com/", "", "s3cret")
+	origins := p.SecretOrigins()
+	if len(origins) != 1 || origins[0] != "https://api.example.com" {
+		t.Fatalf("SecretOrigins = %v, want canonicalized origin", origins)
+	}
+
+	p.SetOriginSecret("https://api.example.com", "", "")
+	if got := p.SecretOrigins(); len(got) != 0 {
+		t.Errorf("SecretOrigins after removal = %v", got)
+	}
+}
@@ -0,0 +1,93 @@
+package fetchproxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignRequestHMAC(t *testing.T) {
+	body := []byte(`{"prompt":"hi"}`)
+	var gotSig string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get("X-Signature")
+		received, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("shared-secret"))
+		mac.Write(received)
+		if gotSig != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer upstream.Close()
+
+	p := NewWithClient(upstream.Client())
+	resp, err := p.Fetch(FetchRequest{
+		URL:    upstream.URL,
+		Method: http.MethodPost,
+		Body:   string(body),
+		Signing: &SigningOptions{
+			Algorithm: SigningHMACSHA256,
+			Secret:    "shared-secret",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if resp.Status != http.StatusOK {
+		t.Errorf("upstream rejected signature (status %d, sig %q)", resp.Status, gotSig)
+	}
+}
+
+func TestSignRequestAWSSigV4(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://bedrock-runtime.us-east-1.amazonaws.com/model/test/invoke", strings.NewReader("{}"))
+	now := time.Date(2025, 8, 30, 12, 36, 0, 0, time.UTC)
+	opts := &SigningOptions{
+		Algorithm: SigningAWSSigV4,
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:    "us-east-1",
+		Service:   "bedrock",
+	}
+	if err := signRequest(req, []byte("{}"), opts, now); err != nil {
+		t.Fatal(err)
+	}
+
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20250830/us-east-1/bedrock/aws4_request") {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if !strings.Contains(auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date") {
+		t.Errorf("signed headers missing from %q", auth)
+	}
+	if req.Header.Get("X-Amz-Date") != "20250830T123600Z" {
+		t.Errorf("X-Amz-Date = %q", req.Header.Get("X-Amz-Date"))
+	}
+
+	// The signature is deterministic for a fixed time and inputs.
+	req2, _ := http.NewRequest(http.MethodPost, "https://bedrock-runtime.us-east-1.amazonaws.com/model/test/invoke", strings.NewReader("{}"))
+	if err := signRequest(req2, []byte("{}"), opts, now); err != nil {
+		t.Fatal(err)
+	}
+	if req2.Header.Get("Authorization") != auth {
+		t.Error("signature is not deterministic")
+	}
+}
+
+func TestSignRequestValidation(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err := signRequest(req, nil, &SigningOptions{Algorithm: "rot13"}, time.Now()); err == nil {
+		t.Error("unknown algorithm accepted")
+	}
+	if err := signRequest(req, nil, &SigningOptions{Algorithm: SigningAWSSigV4}, time.Now()); err == nil {
+		t.Error("sigv4 without credentials accepted")
+	}
+	if err := signRequest(req, nil, &SigningOptions{Algorithm: SigningHMACSHA256}, time.Now()); err == nil {
+		t.Error("hmac without secret accepted")
+	}
+}
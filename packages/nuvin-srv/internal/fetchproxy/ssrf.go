@@ -0,0 +1,190 @@
+package fetchproxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AccessPolicy restricts which hosts the proxy will dial. Entries in Allow
+// and Deny are hostnames ("api.openai.com"), wildcard hostnames
+// ("*.openai.com"), or CIDRs ("10.0.0.0/8"). The policy is enforced in the
+// dialer, so it also applies to every hop of a redirect chain.
+//
+// Evaluation order: Deny always wins, then an Allow match accepts, then
+// BlockPrivate rejects private/loopback/link-local ranges, and finally a
+// non-empty Allow list rejects anything unlisted.
+type AccessPolicy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+	// BlockPrivate rejects loopback, RFC1918, link-local (including the
+	// cloud metadata address), and unique-local destinations unless they
+	// are explicitly allowed.
+	BlockPrivate bool `json:"blockPrivate"`
+}
+
+type compiledPolicy struct {
+	allowHosts   []string
+	allowNets    []*net.IPNet
+	denyHosts    []string
+	denyNets     []*net.IPNet
+	blockPrivate bool
+	restrictive  bool // len(Allow) > 0
+
+	dialer *net.Dialer
+}
+
+func compilePolicy(policy AccessPolicy) (*compiledPolicy, error) {
+	c := &compiledPolicy{
+		blockPrivate: policy.BlockPrivate,
+		restrictive:  len(policy.Allow) > 0,
+		dialer:       &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+	}
+	parse := func(entries []string, hosts *[]string, nets *[]*net.IPNet) error {
+		for _, e := range entries {
+			if strings.Contains(e, "/") {
+				_, ipNet, err := net.ParseCIDR(e)
+				if err != nil {
+					return fmt.Errorf("invalid CIDR %q: %w", e, err)
+				}
+				*nets = append(*nets, ipNet)
+				continue
+			}
+			*hosts = append(*hosts, strings.ToLower(e))
+		}
+		return nil
+	}
+	if err := parse(policy.Allow, &c.allowHosts, &c.allowNets); err != nil {
+		return nil, err
+	}
+	if err := parse(policy.Deny, &c.denyHosts, &c.denyNets); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func hostMatches(host string, patterns []string) bool {
+	for _, p := range patterns {
+		if suffix, ok := strings.CutPrefix(p, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) || host == suffix {
+				return true
+			}
+			continue
+		}
+		if host == p {
+			return true
+		}
+	}
+	return false
+}
+
+func ipInAny(ip net.IP, nets []*net.IPNet) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// check decides whether a destination host resolving to ips may be dialed.
+func (c *compiledPolicy) check(host string, ips []net.IP) error {
+	host = strings.ToLower(host)
+	if hostMatches(host, c.denyHosts) {
+		return fmt.Errorf("host %q is denied by access policy", host)
+	}
+	for _, ip := range ips {
+		if ipInAny(ip, c.denyNets) {
+			return fmt.Errorf("address %s for host %q is denied by access policy", ip, host)
+		}
+	}
+	if hostMatches(host, c.allowHosts) {
+		return nil
+	}
+	allowedByNet := false
+	for _, ip := range ips {
+		if ipInAny(ip, c.allowNets) {
+			allowedByNet = true
+			break
+		}
+	}
+	if allowedByNet {
+		return nil
+	}
+	if c.blockPrivate {
+		for _, ip := range ips {
+			if isPrivateIP(ip) {
+				return fmt.Errorf("address %s for host %q is in a blocked private range", ip, host)
+			}
+		}
+	}
+	if c.restrictive {
+		return fmt.Errorf("host %q is not in the access allowlist", host)
+	}
+	return nil
+}
+
+// dialContext resolves addr, applies the policy to the hostname and every
+// resolved address, and dials a vetted IP directly so DNS rebinding cannot
+// bypass the check.
+func (c *compiledPolicy) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+
+	if err := c.check(host, ips); err != nil {
+		return nil, err
+	}
+	return c.dialer.DialContext(ctx, network, net.JoinHostPort(ips[0].String(), port))
+}
+
+// SetAccessPolicy installs an SSRF access policy on the shared transport.
+func (p *FetchProxy) SetAccessPolicy(policy AccessPolicy) error {
+	compiled, err := compilePolicy(policy)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	transport, ok := p.client.Transport.(*http.Transport)
+	if !ok || transport == nil {
+		transport = newTransport(TransportConfig{})
+		p.client.Transport = transport
+	}
+	transport.DialContext = compiled.dialContext
+	transport.CloseIdleConnections()
+	p.policy = policy
+	return nil
+}
+
+// AccessPolicy reports the currently installed access policy.
+func (p *FetchProxy) AccessPolicy() AccessPolicy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	policy := p.policy
+	policy.Allow = append([]string(nil), policy.Allow...)
+	policy.Deny = append([]string(nil), policy.Deny...)
+	return policy
+}
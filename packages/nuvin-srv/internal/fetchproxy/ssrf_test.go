@@ -0,0 +1,90 @@
+package fetchproxy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessPolicyBlocksPrivateRanges(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	p := New()
+	if err := p.SetAccessPolicy(AccessPolicy{BlockPrivate: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The test upstream listens on 127.0.0.1, which is private.
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL}); err == nil {
+		t.Error("fetch to loopback succeeded despite BlockPrivate")
+	}
+
+	// The cloud metadata address must be blocked before dialing.
+	if _, err := p.Fetch(FetchRequest{URL: "http://169.254.169.254/latest/meta-data/", TimeoutMs: 1000}); err == nil {
+		t.Error("fetch to metadata address succeeded despite BlockPrivate")
+	}
+}
+
+func TestAccessPolicyAllowlistOverridesBlockPrivate(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	p := New()
+	if err := p.SetAccessPolicy(AccessPolicy{Allow: []string{"127.0.0.1"}, BlockPrivate: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := p.Fetch(FetchRequest{URL: upstream.URL})
+	if err != nil {
+		t.Fatalf("allowlisted fetch failed: %v", err)
+	}
+	if resp.Status != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.Status)
+	}
+}
+
+func TestAccessPolicyDenylist(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	p := New()
+	if err := p.SetAccessPolicy(AccessPolicy{Deny: []string{"127.0.0.0/8"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := p.Fetch(FetchRequest{URL: upstream.URL})
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("denylisted fetch error = %v, want denied", err)
+	}
+}
+
+func TestAccessPolicyBlocksRedirectTarget(t *testing.T) {
+	var target *httptest.Server
+	target = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			// Redirect from the allowed 127.0.0.1 host to the denied
+			// "localhost" spelling of the same server.
+			_, port, _ := net.SplitHostPort(target.Listener.Addr().String())
+			http.Redirect(w, r, "http://localhost:"+port+"/private", http.StatusFound)
+		case "/private":
+			_, _ = w.Write([]byte("secret"))
+		}
+	}))
+	defer target.Close()
+
+	p := New()
+	if err := p.SetAccessPolicy(AccessPolicy{Allow: []string{"127.0.0.1"}, Deny: []string{"localhost"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := p.Fetch(FetchRequest{URL: target.URL + "/start"})
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("redirect into denied host error = %v, want denied", err)
+	}
+}
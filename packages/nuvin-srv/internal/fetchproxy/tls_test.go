@@ -0,0 +1,60 @@
+package fetchproxy
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/outbound"
+)
+
+func TestFetchCustomTLSOptions(t *testing.T) {
+	upstream := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("secure"))
+	}))
+	defer upstream.Close()
+
+	p := New()
+
+	// The self-signed test cert is rejected by default.
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL}); err == nil {
+		t.Error("fetch with untrusted cert succeeded without TLS options")
+	}
+
+	// insecureSkipVerify bypasses verification.
+	resp, err := p.Fetch(FetchRequest{
+		URL: upstream.URL,
+		TLS: &outbound.TLSOptions{InsecureSkipVerify: true},
+	})
+	if err != nil {
+		t.Fatalf("insecureSkipVerify fetch: %v", err)
+	}
+	if resp.Body != "secure" {
+		t.Errorf("body = %q, want secure", resp.Body)
+	}
+
+	// Trusting the server certificate as a CA also works.
+	caPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: upstream.Certificate().Raw,
+	})
+	resp, err = p.Fetch(FetchRequest{
+		URL: upstream.URL,
+		TLS: &outbound.TLSOptions{CACertPEM: string(caPEM)},
+	})
+	if err != nil {
+		t.Fatalf("custom CA fetch: %v", err)
+	}
+	if resp.Status != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.Status)
+	}
+
+	// Garbage CA bundles are rejected up front.
+	if _, err := p.Fetch(FetchRequest{
+		URL: upstream.URL,
+		TLS: &outbound.TLSOptions{CACertPEM: "not a pem"},
+	}); err == nil {
+		t.Error("invalid CA bundle accepted")
+	}
+}
@@ -0,0 +1,100 @@
+package fetchproxy
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/outbound"
+)
+
+// TransportConfig tunes the shared upstream transport. Zero values keep the
+// defaults listed on each field.
+type TransportConfig struct {
+	// MaxIdleConns caps idle keep-alive connections overall (default 100).
+	MaxIdleConns int `json:"maxIdleConns,omitempty"`
+	// MaxIdleConnsPerHost caps idle connections per upstream host
+	// (default 10).
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
+	// MaxConnsPerHost caps total connections per upstream host
+	// (default unlimited).
+	MaxConnsPerHost int `json:"maxConnsPerHost,omitempty"`
+	// IdleConnTimeoutSec closes idle connections after this many seconds
+	// (default 90).
+	IdleConnTimeoutSec int `json:"idleConnTimeoutSec,omitempty"`
+	// DisableHTTP2 turns off HTTP/2 negotiation.
+	DisableHTTP2 bool `json:"disableHttp2,omitempty"`
+}
+
+// TransportStats reports connection reuse across all fetches.
+type TransportStats struct {
+	// Conns counts connections handed out for fetches.
+	Conns int64 `json:"conns"`
+	// ReusedConns counts how many of those were keep-alive reuses.
+	ReusedConns int64 `json:"reusedConns"`
+}
+
+// newTransport builds an *http.Transport from cfg.
+func newTransport(cfg TransportConfig) *http.Transport {
+	t := &http.Transport{
+		ForceAttemptHTTP2:   !cfg.DisableHTTP2,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	if cfg.MaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+	if cfg.IdleConnTimeoutSec > 0 {
+		t.IdleConnTimeout = time.Duration(cfg.IdleConnTimeoutSec) * time.Second
+	}
+	return t
+}
+
+// ConfigureTransport replaces the shared transport with one built from cfg.
+// Existing idle connections on the old transport are closed.
+func (p *FetchProxy) ConfigureTransport(cfg TransportConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if old, ok := p.client.Transport.(*http.Transport); ok && old != nil {
+		old.CloseIdleConnections()
+	}
+	p.client.Transport = newTransport(cfg)
+}
+
+// SetOutboundProxy routes all fetches through an HTTP or SOCKS5 proxy URL;
+// an empty URL restores direct egress.
+func (p *FetchProxy) SetOutboundProxy(proxyURL string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	base, ok := p.client.Transport.(*http.Transport)
+	if !ok || base == nil {
+		base = newTransport(TransportConfig{})
+	}
+	if proxyURL == "" {
+		base.Proxy = http.ProxyFromEnvironment
+		p.client.Transport = base
+		return nil
+	}
+	t, err := outbound.Apply(base, proxyURL)
+	if err != nil {
+		return err
+	}
+	base.CloseIdleConnections()
+	p.client.Transport = t
+	return nil
+}
+
+// Stats returns cumulative connection reuse counters.
+func (p *FetchProxy) Stats() TransportStats {
+	return TransportStats{
+		Conns:       atomic.LoadInt64(&p.conns),
+		ReusedConns: atomic.LoadInt64(&p.reusedConns),
+	}
+}
@@ -0,0 +1,45 @@
+package fetchproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSharedTransportReusesConnections(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	p := New()
+	for i := 0; i < 5; i++ {
+		if _, err := p.Fetch(FetchRequest{URL: upstream.URL}); err != nil {
+			t.Fatalf("fetch %d: %v", i, err)
+		}
+	}
+
+	stats := p.Stats()
+	if stats.Conns != 5 {
+		t.Errorf("Conns = %d, want 5", stats.Conns)
+	}
+	if stats.ReusedConns < 3 {
+		t.Errorf("ReusedConns = %d, want at least 3 (keep-alive not working)", stats.ReusedConns)
+	}
+}
+
+func TestConfigureTransport(t *testing.T) {
+	p := New()
+	p.ConfigureTransport(TransportConfig{MaxIdleConnsPerHost: 2, MaxConnsPerHost: 4})
+
+	tr, ok := p.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is %T, want *http.Transport", p.client.Transport)
+	}
+	if tr.MaxIdleConnsPerHost != 2 || tr.MaxConnsPerHost != 4 {
+		t.Errorf("limits = (%d, %d), want (2, 4)", tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+	if !tr.ForceAttemptHTTP2 {
+		t.Error("HTTP/2 should be enabled by default")
+	}
+}
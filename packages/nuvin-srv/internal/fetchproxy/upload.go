@@ -0,0 +1,94 @@
+package fetchproxy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// stagedUpload is a request body accumulated chunk by chunk in a temp file,
+// so large uploads never have to fit in a JSON string.
+type stagedUpload struct {
+	path string
+	size int64
+}
+
+// StartUpload creates an empty staged upload and returns its ID. Chunks are
+// appended with AppendUpload and the whole body is sent by setting
+// FetchRequest.UploadID.
+func (p *FetchProxy) StartUpload() (string, error) {
+	f, err := os.CreateTemp("", "nuvin-upload-*")
+	if err != nil {
+		return "", fmt.Errorf("staging upload: %w", err)
+	}
+	f.Close()
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	id := "up-" + hex.EncodeToString(buf)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uploads[id] = &stagedUpload{path: f.Name()}
+	return id, nil
+}
+
+// AppendUpload appends a chunk to the staged upload and returns the total
+// size staged so far.
+func (p *FetchProxy) AppendUpload(id string, chunk io.Reader) (int64, error) {
+	p.mu.Lock()
+	up, ok := p.uploads[id]
+	p.mu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("unknown upload: %s", id)
+	}
+
+	f, err := os.OpenFile(up.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("opening staged upload: %w", err)
+	}
+	n, err := io.Copy(f, chunk)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("appending upload chunk: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	up.size += n
+	return up.size, nil
+}
+
+// DiscardUpload removes a staged upload and its backing file.
+func (p *FetchProxy) DiscardUpload(id string) {
+	p.mu.Lock()
+	up, ok := p.uploads[id]
+	delete(p.uploads, id)
+	p.mu.Unlock()
+	if ok {
+		os.Remove(up.path)
+	}
+}
+
+// openUpload opens the staged body for sending. Each retry attempt reopens
+// the file so the body can be replayed.
+func (p *FetchProxy) openUpload(id string) (*os.File, int64, error) {
+	p.mu.RLock()
+	up, ok := p.uploads[id]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown upload: %s", id)
+	}
+	f, err := os.Open(up.path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening staged upload: %w", err)
+	}
+	return f, up.size, nil
+}
@@ -0,0 +1,89 @@
+package fetchproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStagedUploadStreamsToUpstream(t *testing.T) {
+	var gotBody string
+	var gotLength int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		gotLength = r.ContentLength
+	}))
+	defer upstream.Close()
+
+	p := New()
+	id, err := p.StartUpload()
+	if err != nil {
+		t.Fatalf("StartUpload: %v", err)
+	}
+	if _, err := p.AppendUpload(id, strings.NewReader("chunk-one ")); err != nil {
+		t.Fatalf("AppendUpload: %v", err)
+	}
+	size, err := p.AppendUpload(id, strings.NewReader("chunk-two"))
+	if err != nil {
+		t.Fatalf("AppendUpload: %v", err)
+	}
+	if size != int64(len("chunk-one chunk-two")) {
+		t.Errorf("staged size = %d", size)
+	}
+
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL, Method: http.MethodPut, UploadID: id}); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if gotBody != "chunk-one chunk-two" {
+		t.Errorf("upstream body = %q", gotBody)
+	}
+	if gotLength != size {
+		t.Errorf("Content-Length = %d, want %d", gotLength, size)
+	}
+
+	// Discarding removes the backing file and the ID.
+	path := p.uploads[id].path
+	p.DiscardUpload(id)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("staged file still exists after discard: %v", err)
+	}
+	if _, err := p.Fetch(FetchRequest{URL: upstream.URL, UploadID: id}); err == nil {
+		t.Error("fetch with discarded upload succeeded")
+	}
+}
+
+func TestStagedUploadReplaysAcrossRetries(t *testing.T) {
+	var bodies []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer upstream.Close()
+
+	p := New()
+	p.sleep = func(d time.Duration) {}
+	id, err := p.StartUpload()
+	if err != nil {
+		t.Fatalf("StartUpload: %v", err)
+	}
+	p.AppendUpload(id, strings.NewReader("payload"))
+
+	resp, err := p.Fetch(FetchRequest{URL: upstream.URL, Method: http.MethodPost, UploadID: id, Retries: 1})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if resp.Status != http.StatusOK {
+		t.Errorf("status = %d", resp.Status)
+	}
+	if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+		t.Errorf("upstream bodies = %q, want payload sent twice", bodies)
+	}
+}
@@ -0,0 +1,190 @@
+// Package github integrates nuvin-srv with GitHub: Copilot token exchange
+// and the API surface built on top of the stored OAuth token.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// copilotTokenURL is where a GitHub OAuth token is exchanged for a
+// short-lived Copilot bearer token.
+const copilotTokenURL = "https://api.github.com/copilot_internal/v2/token"
+
+// refreshMargin is how long before expiry a cached Copilot token is treated
+// as stale. Copilot tokens live ~30 minutes; refreshing a couple of minutes
+// early means callers never receive a token that dies mid-request.
+const refreshMargin = 2 * time.Minute
+
+// CopilotToken is a Copilot bearer token and when it expires.
+type CopilotToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// TokenManager holds the GitHub OAuth token and keeps a valid Copilot
+// bearer token derived from it, re-exchanging proactively before expiry so
+// Current always hands back a token with useful lifetime left.
+type TokenManager struct {
+	mu          sync.Mutex
+	githubToken string
+	copilot     CopilotToken
+
+	client      *http.Client
+	exchangeURL string
+	apiBase     string
+	lastOK      time.Time
+	now         func() time.Time
+}
+
+// NewTokenManager returns a manager with no stored token.
+func NewTokenManager() *TokenManager {
+	return &TokenManager{
+		client:      &http.Client{Timeout: 15 * time.Second},
+		exchangeURL: copilotTokenURL,
+		apiBase:     "https://api.github.com",
+		now:         time.Now,
+	}
+}
+
+// SetExchangeURL points the manager at a different token exchange endpoint
+// (GitHub Enterprise hosts, or a stub in tests).
+func (m *TokenManager) SetExchangeURL(url string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exchangeURL = url
+}
+
+// SetGitHubToken stores the long-lived GitHub OAuth token and drops any
+// Copilot token cached from a previous one.
+func (m *TokenManager) SetGitHubToken(token string) error {
+	if token == "" {
+		return fmt.Errorf("github token is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.githubToken = token
+	m.copilot = CopilotToken{}
+	return nil
+}
+
+// HasGitHubToken reports whether an OAuth token has been stored.
+func (m *TokenManager) HasGitHubToken() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.githubToken != ""
+}
+
+// GitHubToken returns the stored OAuth token for server-side GitHub API
+// calls; it is never exposed over HTTP.
+func (m *TokenManager) GitHubToken() (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.githubToken, m.githubToken != ""
+}
+
+// Current returns a Copilot token valid for at least refreshMargin,
+// exchanging the stored GitHub token for a fresh one when the cached token
+// is missing or close to expiry.
+func (m *TokenManager) Current(ctx context.Context) (CopilotToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.githubToken == "" {
+		return CopilotToken{}, fmt.Errorf("no github token stored")
+	}
+	if m.copilot.Token != "" && m.now().Add(refreshMargin).Before(m.copilot.ExpiresAt) {
+		return m.copilot, nil
+	}
+	tok, err := m.exchangeLocked(ctx)
+	if err != nil {
+		return CopilotToken{}, err
+	}
+	m.copilot = tok
+	m.lastOK = m.now()
+	return tok, nil
+}
+
+// SetAPIBase points the manager at a different GitHub API host (GitHub
+// Enterprise, or a stub in tests).
+func (m *TokenManager) SetAPIBase(base string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.apiBase = base
+}
+
+// Probe makes a lightweight authenticated call to verify the stored OAuth
+// token still works, recording the success for LastOK.
+func (m *TokenManager) Probe(ctx context.Context) error {
+	m.mu.Lock()
+	token, base := m.githubToken, m.apiBase
+	m.mu.Unlock()
+	if token == "" {
+		return fmt.Errorf("no github token stored")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/user", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Accept", "application/json")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github returned %d", resp.StatusCode)
+	}
+	m.mu.Lock()
+	m.lastOK = m.now()
+	m.mu.Unlock()
+	return nil
+}
+
+// LastOK returns when the token last succeeded against GitHub (a probe or
+// a Copilot exchange); zero when it never has.
+func (m *TokenManager) LastOK() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastOK
+}
+
+// CachedCopilotToken returns the cached Copilot token without refreshing.
+func (m *TokenManager) CachedCopilotToken() (CopilotToken, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.copilot, m.copilot.Token != ""
+}
+
+// exchangeLocked performs the one-shot token exchange against GitHub.
+func (m *TokenManager) exchangeLocked(ctx context.Context) (CopilotToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.exchangeURL, nil)
+	if err != nil {
+		return CopilotToken{}, fmt.Errorf("copilot token exchange: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+m.githubToken)
+	req.Header.Set("Accept", "application/json")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return CopilotToken{}, fmt.Errorf("copilot token exchange: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return CopilotToken{}, fmt.Errorf("copilot token exchange: github returned %d", resp.StatusCode)
+	}
+	var body struct {
+		Token     string `json:"token"`
+		ExpiresAt int64  `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return CopilotToken{}, fmt.Errorf("copilot token exchange: %w", err)
+	}
+	if body.Token == "" {
+		return CopilotToken{}, fmt.Errorf("copilot token exchange: empty token in response")
+	}
+	return CopilotToken{Token: body.Token, ExpiresAt: time.Unix(body.ExpiresAt, 0)}, nil
+}
@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// copilotModelsURL lists the models a Copilot token can use.
+const copilotModelsURL = "https://api.githubcopilot.com/models"
+
+// modelCacheTTL is how long a fetched model list is served without asking
+// Copilot again; the catalog changes rarely.
+const modelCacheTTL = 10 * time.Minute
+
+// Model is a normalized Copilot model entry.
+type Model struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Vendor          string `json:"vendor,omitempty"`
+	ContextWindow   int    `json:"contextWindow,omitempty"`
+	MaxOutputTokens int    `json:"maxOutputTokens,omitempty"`
+	Vision          bool   `json:"vision"`
+}
+
+// ModelCatalog fetches and caches the Copilot model list using the managed
+// token, so the UI gets a normalized catalog instead of reconstructing it
+// from raw API responses.
+type ModelCatalog struct {
+	Tokens *TokenManager
+
+	mu        sync.Mutex
+	cached    []Model
+	fetchedAt time.Time
+
+	client    *http.Client
+	modelsURL string
+	now       func() time.Time
+}
+
+// NewModelCatalog returns a catalog backed by tokens.
+func NewModelCatalog(tokens *TokenManager) *ModelCatalog {
+	return &ModelCatalog{
+		Tokens:    tokens,
+		client:    &http.Client{Timeout: 15 * time.Second},
+		modelsURL: copilotModelsURL,
+		now:       time.Now,
+	}
+}
+
+// SetModelsURL points the catalog at a different endpoint (tests).
+func (c *ModelCatalog) SetModelsURL(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.modelsURL = url
+}
+
+// Models returns the model list, refetching when the cache is older than
+// the TTL or refresh is set.
+func (c *ModelCatalog) Models(ctx context.Context, refresh bool) ([]Model, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !refresh && c.cached != nil && c.now().Sub(c.fetchedAt) < modelCacheTTL {
+		return c.cached, nil
+	}
+	models, err := c.fetchLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.cached = models
+	c.fetchedAt = c.now()
+	return models, nil
+}
+
+// fetchLocked pulls and normalizes the raw Copilot model list.
+func (c *ModelCatalog) fetchLocked(ctx context.Context) ([]Model, error) {
+	tok, err := c.Tokens.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.modelsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("copilot models: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+tok.Token)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("copilot models: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("copilot models: upstream returned %d", resp.StatusCode)
+	}
+	var body struct {
+		Data []struct {
+			ID           string `json:"id"`
+			Name         string `json:"name"`
+			Vendor       string `json:"vendor"`
+			Capabilities struct {
+				Limits struct {
+					MaxContextWindowTokens int `json:"max_context_window_tokens"`
+					MaxOutputTokens        int `json:"max_output_tokens"`
+				} `json:"limits"`
+				Supports struct {
+					Vision bool `json:"vision"`
+				} `json:"supports"`
+			} `json:"capabilities"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("copilot models: %w", err)
+	}
+	models := make([]Model, 0, len(body.Data))
+	for _, m := range body.Data {
+		models = append(models, Model{
+			ID:              m.ID,
+			Name:            m.Name,
+			Vendor:          m.Vendor,
+			ContextWindow:   m.Capabilities.Limits.MaxContextWindowTokens,
+			MaxOutputTokens: m.Capabilities.Limits.MaxOutputTokens,
+			Vision:          m.Capabilities.Supports.Vision,
+		})
+	}
+	return models, nil
+}
@@ -0,0 +1,86 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const modelListJSON = `{"data":[
+	{"id":"gpt-4o","name":"GPT-4o","vendor":"openai",
+	 "capabilities":{"limits":{"max_context_window_tokens":128000,"max_output_tokens":16384},
+	                 "supports":{"vision":true}}},
+	{"id":"o3-mini","name":"o3-mini","vendor":"openai",
+	 "capabilities":{"limits":{"max_context_window_tokens":200000},"supports":{}}}
+]}`
+
+func newCatalogStub(t *testing.T, fetches *int) *ModelCatalog {
+	t.Helper()
+	exchange := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"token":"cop_live","expires_at":%d}`, time.Now().Add(30*time.Minute).Unix())
+	}))
+	t.Cleanup(exchange.Close)
+	models := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer cop_live" {
+			t.Errorf("models call Authorization = %q", got)
+		}
+		*fetches++
+		fmt.Fprint(w, modelListJSON)
+	}))
+	t.Cleanup(models.Close)
+
+	tokens := NewTokenManager()
+	tokens.SetExchangeURL(exchange.URL)
+	tokens.SetGitHubToken("gho_abc")
+	cat := NewModelCatalog(tokens)
+	cat.SetModelsURL(models.URL)
+	return cat
+}
+
+func TestModelsNormalizesAndCaches(t *testing.T) {
+	var fetches int
+	cat := newCatalogStub(t, &fetches)
+
+	models, err := cat.Models(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != 2 {
+		t.Fatalf("models = %+v", models)
+	}
+	if m := models[0]; m.ID != "gpt-4o" || m.ContextWindow != 128000 || m.MaxOutputTokens != 16384 || !m.Vision {
+		t.Errorf("gpt-4o = %+v", m)
+	}
+	if m := models[1]; m.Vision || m.ContextWindow != 200000 {
+		t.Errorf("o3-mini = %+v", m)
+	}
+
+	if _, err := cat.Models(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want cached second read", fetches)
+	}
+
+	// An expired cache refetches; so does an explicit refresh.
+	cat.now = func() time.Time { return time.Now().Add(modelCacheTTL + time.Minute) }
+	if _, err := cat.Models(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cat.Models(context.Background(), true); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 3 {
+		t.Errorf("fetches = %d, want 3", fetches)
+	}
+}
+
+func TestModelsWithoutToken(t *testing.T) {
+	cat := NewModelCatalog(NewTokenManager())
+	if _, err := cat.Models(context.Background(), false); err == nil {
+		t.Fatal("expected error with no github token")
+	}
+}
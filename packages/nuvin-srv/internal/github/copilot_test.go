@@ -0,0 +1,98 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestManager(exchangeURL string) *TokenManager {
+	m := NewTokenManager()
+	m.exchangeURL = exchangeURL
+	return m
+}
+
+func TestCurrentExchangesAndCaches(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token gho_abc" {
+			t.Errorf("Authorization = %q", got)
+		}
+		calls++
+		fmt.Fprintf(w, `{"token":"cop_%d","expires_at":%d}`, calls, time.Now().Add(30*time.Minute).Unix())
+	}))
+	defer srv.Close()
+
+	m := newTestManager(srv.URL)
+	if err := m.SetGitHubToken("gho_abc"); err != nil {
+		t.Fatal(err)
+	}
+	tok, err := m.Current(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.Token != "cop_1" {
+		t.Fatalf("token = %q", tok.Token)
+	}
+	if tok, _ = m.Current(context.Background()); tok.Token != "cop_1" {
+		t.Errorf("second call re-exchanged, got %q", tok.Token)
+	}
+	if calls != 1 {
+		t.Errorf("exchange calls = %d, want 1", calls)
+	}
+}
+
+func TestCurrentRefreshesBeforeExpiry(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, `{"token":"cop_%d","expires_at":%d}`, calls, time.Now().Add(30*time.Minute).Unix())
+	}))
+	defer srv.Close()
+
+	m := newTestManager(srv.URL)
+	if err := m.SetGitHubToken("gho_abc"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Current(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Jump to just inside the refresh margin: still technically valid, but
+	// the manager must re-exchange rather than hand out a dying token.
+	m.now = func() time.Time { return time.Now().Add(29 * time.Minute) }
+	tok, err := m.Current(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.Token != "cop_2" || calls != 2 {
+		t.Errorf("token = %q, calls = %d, want refreshed cop_2", tok.Token, calls)
+	}
+}
+
+func TestCurrentWithoutGitHubToken(t *testing.T) {
+	m := NewTokenManager()
+	if _, err := m.Current(context.Background()); err == nil {
+		t.Fatal("expected error with no stored token")
+	}
+}
+
+func TestSetGitHubTokenDropsCachedCopilotToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"token":"for-%s","expires_at":%d}`,
+			r.Header.Get("Authorization"), time.Now().Add(30*time.Minute).Unix())
+	}))
+	defer srv.Close()
+
+	m := newTestManager(srv.URL)
+	m.SetGitHubToken("gho_one")
+	if tok, _ := m.Current(context.Background()); tok.Token != "for-token gho_one" {
+		t.Fatalf("token = %q", tok.Token)
+	}
+	m.SetGitHubToken("gho_two")
+	if tok, _ := m.Current(context.Background()); tok.Token != "for-token gho_two" {
+		t.Errorf("token = %q, want exchange under the new github token", tok.Token)
+	}
+}
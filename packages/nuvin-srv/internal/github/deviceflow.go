@@ -0,0 +1,265 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitHub's OAuth device-flow endpoints.
+const (
+	deviceCodeURL  = "https://github.com/login/device/code"
+	accessTokenURL = "https://github.com/login/oauth/access_token"
+)
+
+// Device-flow session states.
+const (
+	SessionPending    = "pending"
+	SessionAuthorized = "authorized"
+	SessionExpired    = "expired"
+	SessionDenied     = "denied"
+	SessionFailed     = "failed"
+)
+
+// Session is a device-flow authorization in progress. The server owns the
+// device code and the polling loop; clients only ever see the session ID,
+// the user-facing code, and the resulting status.
+type Session struct {
+	ID              string    `json:"id"`
+	UserCode        string    `json:"userCode"`
+	VerificationURI string    `json:"verificationUri"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+	Status          string    `json:"status"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// session is the server-side state backing a Session snapshot.
+type session struct {
+	mu   sync.Mutex
+	snap Session
+	done chan struct{}
+}
+
+func (s *session) snapshot() Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snap
+}
+
+func (s *session) finish(status, errMsg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snap.Status != SessionPending {
+		return
+	}
+	s.snap.Status = status
+	s.snap.Error = errMsg
+	close(s.done)
+}
+
+// DeviceFlow runs GitHub device-flow authorizations server-side. Start
+// registers a session and spawns the polling loop; the loop honors the
+// advertised interval, backs off on slow_down, gives up at expires_in, and
+// stores the resulting OAuth token in the TokenManager on success.
+type DeviceFlow struct {
+	// Tokens receives the access token when a session completes.
+	Tokens *TokenManager
+
+	mu       sync.Mutex
+	sessions map[string]*session
+
+	client   *http.Client
+	codeURL  string
+	tokenURL string
+	// tick paces the polling loop; tests replace it to poll immediately.
+	tick func(d time.Duration) <-chan time.Time
+}
+
+// NewDeviceFlow returns a flow that stores completed tokens in tokens.
+func NewDeviceFlow(tokens *TokenManager) *DeviceFlow {
+	return &DeviceFlow{
+		Tokens:   tokens,
+		sessions: make(map[string]*session),
+		client:   &http.Client{Timeout: 15 * time.Second},
+		codeURL:  deviceCodeURL,
+		tokenURL: accessTokenURL,
+		tick:     func(d time.Duration) <-chan time.Time { return time.After(d) },
+	}
+}
+
+// SetEndpoints points the flow at different OAuth endpoints (GitHub
+// Enterprise hosts, or stubs in tests).
+func (f *DeviceFlow) SetEndpoints(codeURL, tokenURL string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.codeURL = codeURL
+	f.tokenURL = tokenURL
+}
+
+// Start requests a device code from GitHub and begins polling for the
+// user's authorization in the background.
+func (f *DeviceFlow) Start(ctx context.Context, clientID, scope string) (Session, error) {
+	if clientID == "" {
+		return Session{}, fmt.Errorf("clientId is required")
+	}
+	form := url.Values{"client_id": {clientID}}
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+	f.mu.Lock()
+	codeURL, tokenURL := f.codeURL, f.tokenURL
+	f.mu.Unlock()
+	var code struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	if err := f.postForm(ctx, codeURL, form, &code); err != nil {
+		return Session{}, fmt.Errorf("device flow start: %w", err)
+	}
+	if code.DeviceCode == "" || code.UserCode == "" {
+		return Session{}, fmt.Errorf("device flow start: github returned no device code")
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return Session{}, fmt.Errorf("device flow start: %w", err)
+	}
+	sess := &session{
+		snap: Session{
+			ID:              hex.EncodeToString(buf),
+			UserCode:        code.UserCode,
+			VerificationURI: code.VerificationURI,
+			ExpiresAt:       time.Now().Add(time.Duration(code.ExpiresIn) * time.Second),
+			Status:          SessionPending,
+		},
+		done: make(chan struct{}),
+	}
+	f.mu.Lock()
+	f.sessions[sess.snap.ID] = sess
+	f.mu.Unlock()
+
+	go f.poll(sess, tokenURL, clientID, code.DeviceCode, code.Interval)
+	return sess.snapshot(), nil
+}
+
+// Session returns a snapshot of the session by ID.
+func (f *DeviceFlow) Session(id string) (Session, bool) {
+	f.mu.Lock()
+	sess, ok := f.sessions[id]
+	f.mu.Unlock()
+	if !ok {
+		return Session{}, false
+	}
+	return sess.snapshot(), true
+}
+
+// Wait blocks until the session leaves the pending state or ctx expires,
+// then returns the latest snapshot; long-polling clients call this with a
+// request-scoped deadline.
+func (f *DeviceFlow) Wait(ctx context.Context, id string) (Session, bool) {
+	f.mu.Lock()
+	sess, ok := f.sessions[id]
+	f.mu.Unlock()
+	if !ok {
+		return Session{}, false
+	}
+	select {
+	case <-sess.done:
+	case <-ctx.Done():
+	}
+	return sess.snapshot(), true
+}
+
+// Done exposes the session's completion channel for SSE streaming.
+func (f *DeviceFlow) Done(id string) (<-chan struct{}, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sess, ok := f.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	return sess.done, true
+}
+
+// poll exchanges the device code until GitHub reports a terminal state.
+func (f *DeviceFlow) poll(sess *session, tokenURL, clientID, deviceCode string, interval int) {
+	if interval <= 0 {
+		interval = 5
+	}
+	deadline := sess.snapshot().ExpiresAt
+	for {
+		<-f.tick(time.Duration(interval) * time.Second)
+		if time.Now().After(deadline) {
+			sess.finish(SessionExpired, "device code expired before authorization")
+			return
+		}
+		form := url.Values{
+			"client_id":   {clientID},
+			"device_code": {deviceCode},
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		}
+		var tok struct {
+			AccessToken string `json:"access_token"`
+			Error       string `json:"error"`
+		}
+		if err := f.postForm(context.Background(), tokenURL, form, &tok); err != nil {
+			sess.finish(SessionFailed, err.Error())
+			return
+		}
+		switch tok.Error {
+		case "":
+			if tok.AccessToken == "" {
+				sess.finish(SessionFailed, "github returned neither a token nor an error")
+				return
+			}
+			if f.Tokens != nil {
+				_ = f.Tokens.SetGitHubToken(tok.AccessToken)
+			}
+			sess.finish(SessionAuthorized, "")
+			return
+		case "authorization_pending":
+			// Keep waiting for the user.
+		case "slow_down":
+			interval += 5
+		case "expired_token":
+			sess.finish(SessionExpired, "device code expired before authorization")
+			return
+		case "access_denied":
+			sess.finish(SessionDenied, "the user declined the authorization")
+			return
+		default:
+			sess.finish(SessionFailed, "github returned "+tok.Error)
+			return
+		}
+	}
+}
+
+// postForm sends an OAuth form post and decodes the JSON response.
+func (f *DeviceFlow) postForm(ctx context.Context, endpoint string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github returned %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
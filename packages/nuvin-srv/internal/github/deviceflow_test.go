@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestFlow wires a DeviceFlow to stub endpoints and makes the polling
+// loop run without real sleeps, recording each requested interval.
+func newTestFlow(tokens *TokenManager, codeURL, tokenURL string) (*DeviceFlow, *[]time.Duration) {
+	f := NewDeviceFlow(tokens)
+	f.SetEndpoints(codeURL, tokenURL)
+	intervals := &[]time.Duration{}
+	f.tick = func(d time.Duration) <-chan time.Time {
+		*intervals = append(*intervals, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	}
+	return f, intervals
+}
+
+func TestDeviceFlowAuthorizes(t *testing.T) {
+	var polls atomic.Int32
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/device/code":
+			fmt.Fprint(w, `{"device_code":"dev123","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":5}`)
+		case "/access_token":
+			if r.FormValue("device_code") != "dev123" {
+				t.Errorf("device_code = %q", r.FormValue("device_code"))
+			}
+			switch polls.Add(1) {
+			case 1:
+				fmt.Fprint(w, `{"error":"authorization_pending"}`)
+			case 2:
+				fmt.Fprint(w, `{"error":"slow_down"}`)
+			default:
+				fmt.Fprint(w, `{"access_token":"gho_granted"}`)
+			}
+		}
+	}))
+	defer stub.Close()
+
+	tokens := NewTokenManager()
+	f, intervals := newTestFlow(tokens, stub.URL+"/device/code", stub.URL+"/access_token")
+	sess, err := f.Start(context.Background(), "client123", "read:user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.UserCode != "ABCD-1234" || sess.Status != SessionPending {
+		t.Fatalf("session = %+v", sess)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	final, ok := f.Wait(ctx, sess.ID)
+	if !ok || final.Status != SessionAuthorized {
+		t.Fatalf("final = %+v ok=%v", final, ok)
+	}
+	if tok, _ := tokens.GitHubToken(); tok != "gho_granted" {
+		t.Errorf("stored token = %q", tok)
+	}
+	// slow_down must stretch the polling interval by five seconds.
+	if got := *intervals; len(got) < 3 || got[0] != 5*time.Second || got[2] != 10*time.Second {
+		t.Errorf("intervals = %v", got)
+	}
+}
+
+func TestDeviceFlowDenied(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/device/code" {
+			fmt.Fprint(w, `{"device_code":"dev123","user_code":"ABCD-1234","verification_uri":"x","expires_in":900,"interval":5}`)
+			return
+		}
+		fmt.Fprint(w, `{"error":"access_denied"}`)
+	}))
+	defer stub.Close()
+
+	f, _ := newTestFlow(nil, stub.URL+"/device/code", stub.URL+"/access_token")
+	sess, err := f.Start(context.Background(), "client123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	final, _ := f.Wait(ctx, sess.ID)
+	if final.Status != SessionDenied || final.Error == "" {
+		t.Errorf("final = %+v", final)
+	}
+}
+
+func TestDeviceFlowRequiresClientID(t *testing.T) {
+	f := NewDeviceFlow(nil)
+	if _, err := f.Start(context.Background(), "", ""); err == nil {
+		t.Fatal("expected error without clientId")
+	}
+}
+
+func TestDeviceFlowUnknownSession(t *testing.T) {
+	f := NewDeviceFlow(nil)
+	if _, ok := f.Session("nope"); ok {
+		t.Fatal("unknown session reported as found")
+	}
+	if _, ok := f.Wait(context.Background(), "nope"); ok {
+		t.Fatal("unknown session reported as found")
+	}
+}
@@ -0,0 +1,207 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// RateLimitError reports that GitHub's API rate limit is exhausted and
+// when it resets.
+type RateLimitError struct {
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("github rate limit exhausted, resets at %s", e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// Repo is a normalized repository entry.
+type Repo struct {
+	Name          string    `json:"name"`
+	FullName      string    `json:"fullName"`
+	Private       bool      `json:"private"`
+	DefaultBranch string    `json:"defaultBranch"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	URL           string    `json:"url"`
+}
+
+// Issue is a normalized issue or pull request entry.
+type Issue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	URL       string    `json:"url"`
+	// Head and Base are set on pull requests only.
+	Head string `json:"head,omitempty"`
+	Base string `json:"base,omitempty"`
+}
+
+// Page carries pagination state back to the caller; NextPage is zero on
+// the last page.
+type Page struct {
+	Page     int `json:"page"`
+	NextPage int `json:"nextPage,omitempty"`
+}
+
+// REST is a thin wrapper over the GitHub REST API using the stored OAuth
+// token, so the frontend doesn't go through the generic /fetch path with
+// raw headers.
+type REST struct {
+	Tokens *TokenManager
+
+	client  *http.Client
+	apiBase string
+}
+
+// NewREST returns a client backed by tokens.
+func NewREST(tokens *TokenManager) *REST {
+	return &REST{
+		Tokens:  tokens,
+		client:  &http.Client{Timeout: 15 * time.Second},
+		apiBase: "https://api.github.com",
+	}
+}
+
+// SetAPIBase points the client at a different API host (GitHub Enterprise,
+// or a stub in tests).
+func (c *REST) SetAPIBase(base string) { c.apiBase = base }
+
+// Repos lists the authenticated user's repositories, most recently updated
+// first.
+func (c *REST) Repos(ctx context.Context, page, perPage int) ([]Repo, Page, error) {
+	q := url.Values{"sort": {"updated"}}
+	var raw []struct {
+		Name          string    `json:"name"`
+		FullName      string    `json:"full_name"`
+		Private       bool      `json:"private"`
+		DefaultBranch string    `json:"default_branch"`
+		UpdatedAt     time.Time `json:"updated_at"`
+		HTMLURL       string    `json:"html_url"`
+	}
+	pg, err := c.get(ctx, "/user/repos", q, page, perPage, &raw)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	out := make([]Repo, 0, len(raw))
+	for _, r := range raw {
+		out = append(out, Repo{
+			Name: r.Name, FullName: r.FullName, Private: r.Private,
+			DefaultBranch: r.DefaultBranch, UpdatedAt: r.UpdatedAt, URL: r.HTMLURL,
+		})
+	}
+	return out, pg, nil
+}
+
+// Issues lists issues for a repo ("owner/name"), or the user's assigned
+// issues when repo is empty.
+func (c *REST) Issues(ctx context.Context, repo, state string, page, perPage int) ([]Issue, Page, error) {
+	path := "/issues"
+	if repo != "" {
+		path = "/repos/" + repo + "/issues"
+	}
+	return c.issueList(ctx, path, state, page, perPage)
+}
+
+// PRs lists pull requests for a repo ("owner/name").
+func (c *REST) PRs(ctx context.Context, repo, state string, page, perPage int) ([]Issue, Page, error) {
+	if repo == "" {
+		return nil, Page{}, fmt.Errorf("repo is required")
+	}
+	return c.issueList(ctx, "/repos/"+repo+"/pulls", state, page, perPage)
+}
+
+func (c *REST) issueList(ctx context.Context, path, state string, page, perPage int) ([]Issue, Page, error) {
+	q := url.Values{}
+	if state != "" {
+		q.Set("state", state)
+	}
+	var raw []struct {
+		Number    int       `json:"number"`
+		Title     string    `json:"title"`
+		State     string    `json:"state"`
+		UpdatedAt time.Time `json:"updated_at"`
+		HTMLURL   string    `json:"html_url"`
+		Head      *struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+		Base *struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	}
+	pg, err := c.get(ctx, path, q, page, perPage, &raw)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	out := make([]Issue, 0, len(raw))
+	for _, i := range raw {
+		issue := Issue{
+			Number: i.Number, Title: i.Title, State: i.State,
+			UpdatedAt: i.UpdatedAt, URL: i.HTMLURL,
+		}
+		if i.Head != nil {
+			issue.Head = i.Head.Ref
+		}
+		if i.Base != nil {
+			issue.Base = i.Base.Ref
+		}
+		out = append(out, issue)
+	}
+	return out, pg, nil
+}
+
+// nextPageRe pulls the page parameter out of the Link header's rel="next"
+// entry.
+var nextPageRe = regexp.MustCompile(`[?&]page=(\d+)[^>]*>;\s*rel="next"`)
+
+// get performs an authenticated GET, decoding into out and translating
+// pagination and rate-limit responses.
+func (c *REST) get(ctx context.Context, path string, q url.Values, page, perPage int, out any) (Page, error) {
+	token, ok := c.Tokens.GitHubToken()
+	if !ok {
+		return Page{}, fmt.Errorf("no github token stored")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if perPage <= 0 || perPage > 100 {
+		perPage = 30
+	}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(perPage))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiBase+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return Page{}, err
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Page{}, err
+	}
+	defer resp.Body.Close()
+
+	if (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		return Page{}, &RateLimitError{ResetAt: time.Unix(reset, 0)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("github returned %d for %s", resp.StatusCode, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return Page{}, fmt.Errorf("decoding github response: %w", err)
+	}
+	pg := Page{Page: page}
+	if m := nextPageRe.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		pg.NextPage, _ = strconv.Atoi(m[1])
+	}
+	return pg, nil
+}
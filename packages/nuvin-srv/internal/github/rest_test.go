@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestREST(t *testing.T, handler http.HandlerFunc) *REST {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	tokens := NewTokenManager()
+	tokens.SetGitHubToken("gho_abc")
+	c := NewREST(tokens)
+	c.SetAPIBase(srv.URL)
+	return c
+}
+
+func TestReposPagination(t *testing.T) {
+	c := newTestREST(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/repos" || r.Header.Get("Authorization") != "token gho_abc" {
+			t.Errorf("request = %s %s auth=%q", r.Method, r.URL, r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("page") != "2" || r.URL.Query().Get("per_page") != "1" {
+			t.Errorf("query = %v", r.URL.Query())
+		}
+		w.Header().Set("Link", `<https://api.github.com/user/repos?page=3&per_page=1>; rel="next", <https://api.github.com/user/repos?page=9>; rel="last"`)
+		fmt.Fprint(w, `[{"name":"nuvin-cli","full_name":"marschhuynh/nuvin-cli","private":false,"default_branch":"main","html_url":"https://github.com/marschhuynh/nuvin-cli"}]`)
+	})
+
+	repos, pg, err := c.Repos(context.Background(), 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].FullName != "marschhuynh/nuvin-cli" || repos[0].DefaultBranch != "main" {
+		t.Errorf("repos = %+v", repos)
+	}
+	if pg.Page != 2 || pg.NextPage != 3 {
+		t.Errorf("page = %+v", pg)
+	}
+}
+
+func TestPRsNormalizesBranches(t *testing.T) {
+	c := newTestREST(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/o/r/pulls" || r.URL.Query().Get("state") != "open" {
+			t.Errorf("request = %s %v", r.URL.Path, r.URL.Query())
+		}
+		fmt.Fprint(w, `[{"number":7,"title":"Fix","state":"open","html_url":"u","head":{"ref":"fix-1"},"base":{"ref":"main"}}]`)
+	})
+	prs, _, err := c.PRs(context.Background(), "o/r", "open", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 || prs[0].Head != "fix-1" || prs[0].Base != "main" {
+		t.Errorf("prs = %+v", prs)
+	}
+	if _, _, err := c.PRs(context.Background(), "", "open", 0, 0); err == nil {
+		t.Error("PRs without repo accepted")
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	c := newTestREST(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset))
+		w.WriteHeader(http.StatusForbidden)
+	})
+	_, _, err := c.Repos(context.Background(), 1, 30)
+	var rl *RateLimitError
+	if !errors.As(err, &rl) {
+		t.Fatalf("err = %v, want RateLimitError", err)
+	}
+	if rl.ResetAt.Unix() != reset {
+		t.Errorf("ResetAt = %v", rl.ResetAt)
+	}
+}
+
+func TestRESTWithoutToken(t *testing.T) {
+	c := NewREST(NewTokenManager())
+	if _, _, err := c.Repos(context.Background(), 1, 30); err == nil {
+		t.Fatal("expected error with no stored token")
+	}
+}
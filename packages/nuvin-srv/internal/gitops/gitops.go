@@ -0,0 +1,226 @@
+// Package gitops exposes common git workflows (status, diff, log, branch,
+// commit, apply-patch) as structured operations so the UI never has to build
+// raw shell command lines. Every operation invokes the git binary with a
+// fixed argv — no shell interpretation of user input.
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const gitTimeout = 30 * time.Second
+
+// Service runs git operations against repositories on the server's disk.
+type Service struct{}
+
+// NewService returns a git operations service.
+func NewService() *Service {
+	return &Service{}
+}
+
+// StatusEntry is one changed path from `git status --porcelain`.
+type StatusEntry struct {
+	Path string `json:"path"`
+	// Staged and Unstaged are the two porcelain status letters ("M", "A",
+	// "D", "?", ...), blank when that side is clean.
+	Staged   string `json:"staged,omitempty"`
+	Unstaged string `json:"unstaged,omitempty"`
+}
+
+// Status reports the working tree state of the repository.
+type Status struct {
+	Branch  string        `json:"branch"`
+	Entries []StatusEntry `json:"entries"`
+}
+
+// LogEntry is one commit from the history.
+type LogEntry struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Email   string `json:"email"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+}
+
+// Branches lists local branches and marks the current one.
+type Branches struct {
+	Current  string   `json:"current"`
+	Branches []string `json:"branches"`
+}
+
+// run executes git with the given argv in dir and returns stdout. Git errors
+// surface with stderr attached so callers see the actual complaint.
+func (s *Service) run(dir string, args ...string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("repo path is required")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("repo path is not a directory: %s", dir)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
+
+// Status returns the branch and changed paths of the repository.
+func (s *Service) Status(dir string) (*Status, error) {
+	out, err := s.run(dir, "status", "--porcelain", "--branch")
+	if err != nil {
+		return nil, err
+	}
+	st := &Status{Entries: []StatusEntry{}}
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			st.Branch = strings.TrimPrefix(line, "## ")
+			if i := strings.IndexAny(st.Branch, ". "); i > 0 && strings.Contains(st.Branch, "...") {
+				st.Branch = st.Branch[:strings.Index(st.Branch, "...")]
+			}
+			continue
+		}
+		if len(line) < 4 {
+			continue
+		}
+		entry := StatusEntry{
+			Staged:   strings.TrimSpace(line[0:1]),
+			Unstaged: strings.TrimSpace(line[1:2]),
+			Path:     line[3:],
+		}
+		st.Entries = append(st.Entries, entry)
+	}
+	return st, nil
+}
+
+// Diff returns the unified diff; staged selects the index instead of the
+// working tree, and path narrows it to one file.
+func (s *Service) Diff(dir string, staged bool, path string) (string, error) {
+	args := []string{"diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+	return s.run(dir, args...)
+}
+
+// logFormat uses unit separators so subjects with any printable characters
+// parse unambiguously.
+const logFormat = "%H\x1f%an\x1f%ae\x1f%aI\x1f%s"
+
+// Log returns up to limit commits, newest first (default 20).
+func (s *Service) Log(dir string, limit int) ([]LogEntry, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	out, err := s.run(dir, "log", "--pretty=format:"+logFormat, "-n", strconv.Itoa(limit))
+	if err != nil {
+		return nil, err
+	}
+	entries := []LogEntry{}
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 5 {
+			continue
+		}
+		entries = append(entries, LogEntry{
+			Hash: parts[0], Author: parts[1], Email: parts[2], Date: parts[3], Subject: parts[4],
+		})
+	}
+	return entries, nil
+}
+
+// Branches lists local branches.
+func (s *Service) Branches(dir string) (*Branches, error) {
+	out, err := s.run(dir, "branch", "--format=%(refname:short)\x1f%(HEAD)")
+	if err != nil {
+		return nil, err
+	}
+	b := &Branches{Branches: []string{}}
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+		b.Branches = append(b.Branches, parts[0])
+		if parts[1] == "*" {
+			b.Current = parts[0]
+		}
+	}
+	return b, nil
+}
+
+// CreateBranch creates the branch and checks it out.
+func (s *Service) CreateBranch(dir, name string) error {
+	if name == "" {
+		return fmt.Errorf("branch name is required")
+	}
+	_, err := s.run(dir, "checkout", "-b", name)
+	return err
+}
+
+// Commit stages everything when addAll is set and records a commit.
+func (s *Service) Commit(dir, message string, addAll bool) (string, error) {
+	if strings.TrimSpace(message) == "" {
+		return "", fmt.Errorf("commit message is required")
+	}
+	if addAll {
+		if _, err := s.run(dir, "add", "-A"); err != nil {
+			return "", err
+		}
+	}
+	if _, err := s.run(dir, "commit", "-m", message); err != nil {
+		return "", err
+	}
+	hash, err := s.run(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(hash), nil
+}
+
+// ApplyPatch applies a unified diff to the working tree.
+func (s *Service) ApplyPatch(dir, patch string) error {
+	if strings.TrimSpace(patch) == "" {
+		return fmt.Errorf("patch is required")
+	}
+	if dir == "" {
+		return fmt.Errorf("repo path is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "apply", "--whitespace=nowarn", "-")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(patch)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("git apply: %s", msg)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package gitops
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a throwaway repository with one committed file.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewService()
+	if _, err := s.Commit(dir, "initial", true); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	return dir
+}
+
+func TestStatusAndCommit(t *testing.T) {
+	s := NewService()
+	dir := initRepo(t)
+
+	st, err := s.Status(dir)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.Branch != "main" || len(st.Entries) != 0 {
+		t.Fatalf("status = %+v, want clean main", st)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	st, err = s.Status(dir)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(st.Entries) != 1 || st.Entries[0].Path != "new.txt" || st.Entries[0].Unstaged != "?" {
+		t.Fatalf("entries = %+v, want untracked new.txt", st.Entries)
+	}
+
+	hash, err := s.Commit(dir, "add new.txt", true)
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(hash) != 40 {
+		t.Fatalf("hash = %q", hash)
+	}
+}
+
+func TestLogAndBranches(t *testing.T) {
+	s := NewService()
+	dir := initRepo(t)
+
+	entries, err := s.Log(dir, 10)
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Subject != "initial" || entries[0].Author != "Test" {
+		t.Fatalf("log = %+v", entries)
+	}
+
+	if err := s.CreateBranch(dir, "feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	b, err := s.Branches(dir)
+	if err != nil {
+		t.Fatalf("Branches: %v", err)
+	}
+	if b.Current != "feature" || len(b.Branches) != 2 {
+		t.Fatalf("branches = %+v", b)
+	}
+}
+
+func TestDiffAndApplyPatch(t *testing.T) {
+	s := NewService()
+	dir := initRepo(t)
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hello\nworld\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := s.Diff(dir, false, "")
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if !strings.Contains(diff, "+world") {
+		t.Fatalf("diff = %q", diff)
+	}
+
+	// Revert, then re-apply the same change through ApplyPatch.
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ApplyPatch(dir, diff); err != nil {
+		t.Fatalf("ApplyPatch: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello\nworld\n" {
+		t.Fatalf("file = %q", data)
+	}
+}
+
+func TestRunRejectsBadRepo(t *testing.T) {
+	s := NewService()
+	if _, err := s.Status(""); err == nil {
+		t.Fatal("empty repo path accepted")
+	}
+	if _, err := s.Status("/definitely/not/here"); err == nil {
+		t.Fatal("missing repo path accepted")
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+// APIKeysHandler manages programmatic-client credentials: POST
+// /auth/api-keys issues a key (the plaintext appears in that response and
+// nowhere else), GET lists issued keys, and DELETE /auth/api-keys/{id}
+// revokes one.
+type APIKeysHandler struct {
+	Store *auth.KeyStore
+}
+
+func (h *APIKeysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/auth/api-keys"), "/")
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"keys": h.Store.List()})
+	case rest == "" && r.Method == http.MethodPost:
+		h.issue(w, r)
+	case rest == "":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	case strings.Contains(rest, "/"):
+		writeError(w, http.StatusNotFound, "not found")
+	case r.Method == http.MethodDelete:
+		if !h.Store.Revoke(rest) {
+			writeError(w, http.StatusNotFound, "unknown key: "+rest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *APIKeysHandler) issue(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	plaintext, meta, err := h.Store.Issue(req.Name, req.Scopes)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"key": plaintext, "meta": meta})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+// LoginHandler exchanges the bootstrap local token for a session at
+// POST /auth/login. nuvin-ui reads the token from the file the server
+// writes on startup and calls this once per launch.
+type LoginHandler struct {
+	Auth *auth.Authenticator
+}
+
+func (h *LoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req struct {
+		LocalToken string `json:"localToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	sess, err := h.Auth.Login(req.LocalToken)
+	if err != nil {
+		writeErrorCode(w, http.StatusUnauthorized, "invalid local token", "invalid_local_token")
+		return
+	}
+	writeJSON(w, http.StatusOK, sess)
+}
+
+// RefreshHandler rotates a session's token pair at POST /auth/refresh.
+// Presenting a refresh token that was already rotated out revokes the
+// session, so the UI must always switch to the newest pair.
+type RefreshHandler struct {
+	Auth *auth.Authenticator
+}
+
+func (h *RefreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	sess, err := h.Auth.Refresh(req.RefreshToken)
+	if err != nil {
+		writeErrorCode(w, http.StatusUnauthorized, err.Error(), "invalid_refresh_token")
+		return
+	}
+	writeJSON(w, http.StatusOK, sess)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+// RolesHandler manages the role table: GET /auth/roles lists assignments
+// and the default role, PUT /auth/roles sets the default, PUT
+// /auth/roles/{subject} assigns a role, and DELETE returns a subject to
+// the default.
+type RolesHandler struct {
+	Roles *auth.Roles
+}
+
+func (h *RolesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/auth/roles"), "/")
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"assignments": h.Roles.List(),
+			"defaultRole": h.Roles.Default(),
+		})
+	case rest == "" && r.Method == http.MethodPut:
+		var req struct {
+			DefaultRole string `json:"defaultRole"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if err := h.Roles.SetDefault(req.DefaultRole); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"defaultRole": h.Roles.Default()})
+	case rest == "":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	case strings.Contains(rest, "/"):
+		writeError(w, http.StatusNotFound, "not found")
+	case r.Method == http.MethodPut:
+		var req struct {
+			Role string `json:"role"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if err := h.Roles.Assign(rest, req.Role); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, auth.Assignment{Subject: rest, Role: req.Role})
+	case r.Method == http.MethodDelete:
+		h.Roles.Unassign(rest)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
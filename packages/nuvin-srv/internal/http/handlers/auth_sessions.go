@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+// SessionsHandler lists and revokes active sessions: GET /auth/sessions
+// (optionally ?subject= to filter to one user) and DELETE
+// /auth/sessions/{id}.
+type SessionsHandler struct {
+	Auth *auth.Authenticator
+}
+
+func (h *SessionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/auth/sessions"), "/")
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"sessions": h.Auth.Sessions(r.URL.Query().Get("subject")),
+		})
+	case rest == "":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	case strings.Contains(rest, "/"):
+		writeError(w, http.StatusNotFound, "not found")
+	case r.Method == http.MethodDelete:
+		if !h.Auth.Revoke(rest) {
+			writeError(w, http.StatusNotFound, "session not found: "+rest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/envprofile"
+)
+
+// EnvProfilesHandler manages named environment profiles at /env-profiles
+// (GET lists, POST saves) and /env-profiles/{name} (GET, DELETE).
+type EnvProfilesHandler struct {
+	Store *envprofile.Store
+}
+
+func (h *EnvProfilesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/env-profiles")
+	name = strings.TrimPrefix(name, "/")
+	if strings.Contains(name, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"profiles": h.Store.List()})
+		case http.MethodPost:
+			var p envprofile.Profile
+			if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			if err := h.Store.Save(p); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, p)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		p, ok := h.Store.Get(name)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown profile: "+name)
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	case http.MethodDelete:
+		h.Store.Delete(name)
+		writeJSON(w, http.StatusOK, map[string]any{"name": name, "deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+	"github.com/marschhuynh/nuvin-srv/internal/http/middleware"
+)
+
+// ExecuteCommandHandler runs a command to completion at POST /execute-command
+// and returns the buffered result.
+type ExecuteCommandHandler struct {
+	Runner *command.Runner
+}
+
+func (h *ExecuteCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req command.ExecRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	req.Origin = middleware.Subject(r)
+
+	if req.DryRun {
+		plan, err := h.Runner.Plan(req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, plan)
+		return
+	}
+
+	if h.Runner.RequiresApproval(req.Command) {
+		a := h.Runner.ParkForApproval(req)
+		writeJSON(w, http.StatusAccepted, map[string]any{"approvalId": a.ID, "status": a.Status, "expiresAt": a.ExpiresAt})
+		return
+	}
+
+	if req.Async {
+		job, err := h.Runner.Submit(req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]any{"jobId": job.ID, "status": job.Status})
+		return
+	}
+
+	result, err := h.Runner.Execute(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// ExecuteCommandStreamHandler runs a command at POST /execute-command/stream
+// and emits one SSE event per output line ("stdout"/"stderr") plus a final
+// "exit" event, so long builds show progress live.
+type ExecuteCommandStreamHandler struct {
+	Runner *command.Runner
+}
+
+func (h *ExecuteCommandStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req command.ExecRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	req.Origin = middleware.Subject(r)
+
+	flusher, _ := w.(http.Flusher)
+
+	// Headers go out with the first event so validation failures can still
+	// return a plain JSON error.
+	var started sync.Once
+	err := h.Runner.ExecuteStream(req, func(ev command.StreamEvent) {
+		started.Do(func() {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.WriteHeader(http.StatusOK)
+		})
+		data, merr := json.Marshal(ev)
+		if merr != nil {
+			return
+		}
+		w.Write([]byte("event: " + ev.Type + "\ndata: "))
+		w.Write(data)
+		w.Write([]byte("\n\n"))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+)
+
+// ExecuteApprovalsHandler surfaces parked commands for review:
+// GET /execute-command/approvals lists them, GET /execute-command/approvals/{id}
+// returns one, POST .../{id}/approve releases it to the job queue, and
+// POST .../{id}/reject discards it. The rules themselves live at
+// /execute-command/approval-policy (GET reads, PUT replaces).
+type ExecuteApprovalsHandler struct {
+	Runner *command.Runner
+}
+
+func (h *ExecuteApprovalsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/execute-command/approvals")
+	rest = strings.TrimPrefix(rest, "/")
+
+	if rest == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"approvals": h.Runner.Approvals()})
+		return
+	}
+
+	if id, ok := strings.CutSuffix(rest, "/approve"); ok {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		job, err := h.Runner.Approve(id)
+		if err != nil {
+			status := http.StatusConflict
+			if strings.Contains(err.Error(), "unknown approval") {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": id, "approved": true, "jobId": job.ID})
+		return
+	}
+	if id, ok := strings.CutSuffix(rest, "/reject"); ok {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if err := h.Runner.Reject(id); err != nil {
+			status := http.StatusConflict
+			if strings.Contains(err.Error(), "unknown approval") {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": id, "rejected": true})
+		return
+	}
+
+	if strings.Contains(rest, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	a, ok := h.Runner.Approval(rest)
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown approval: "+rest)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+// ApprovalPolicyHandler configures which commands require approval.
+type ApprovalPolicyHandler struct {
+	Runner *command.Runner
+}
+
+func (h *ApprovalPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.Runner.ApprovalConfig())
+	case http.MethodPut:
+		var cfg command.ApprovalConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		h.Runner.SetApprovalConfig(cfg)
+		writeJSON(w, http.StatusOK, h.Runner.ApprovalConfig())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
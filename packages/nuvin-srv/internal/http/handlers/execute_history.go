@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+)
+
+// ExecuteHistoryHandler exposes the command audit history:
+// GET /execute-command/history?q=&limit= lists and searches invocations,
+// GET /execute-command/history/{id} returns one entry in full.
+type ExecuteHistoryHandler struct {
+	Runner *command.Runner
+}
+
+func (h *ExecuteHistoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/execute-command/history")
+	id = strings.TrimPrefix(id, "/")
+	if strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if id != "" {
+		entry, ok := h.Runner.HistoryEntry(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown history entry: "+id)
+			return
+		}
+		writeJSON(w, http.StatusOK, entry)
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"history": h.Runner.History(r.URL.Query().Get("q"), limit),
+	})
+}
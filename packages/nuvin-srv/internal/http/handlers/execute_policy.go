@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+)
+
+// ExecutePolicyHandler manages the command policy engine:
+// GET /execute-command/policy?origin= returns the effective policy,
+// PUT /execute-command/policy installs one, and
+// GET /execute-command/decisions exposes the audit log.
+type ExecutePolicyHandler struct {
+	Engine *command.PolicyEngine
+}
+
+type setPolicyRequest struct {
+	// Origin scopes the policy; empty sets the default policy.
+	Origin string         `json:"origin,omitempty"`
+	Policy command.Policy `json:"policy"`
+}
+
+func (h *ExecutePolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/execute-command/decisions" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"decisions": h.Engine.Decisions()})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		origin := r.URL.Query().Get("origin")
+		writeJSON(w, http.StatusOK, map[string]any{
+			"origin": origin,
+			"policy": h.Engine.PolicyFor(origin),
+		})
+	case http.MethodPut:
+		var req setPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		h.Engine.SetPolicy(req.Origin, req.Policy)
+		writeJSON(w, http.StatusOK, map[string]any{"origin": req.Origin, "policy": req.Policy})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+)
+
+// ExecuteQueueHandler exposes the execution scheduler at
+// /execute-command/queue: GET returns running/waiting counts and the active
+// caps, PUT replaces the caps.
+type ExecuteQueueHandler struct {
+	Runner *command.Runner
+}
+
+func (h *ExecuteQueueHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.Runner.SchedulerState())
+	case http.MethodPut:
+		var cfg command.SchedulerConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		h.Runner.SetSchedulerConfig(cfg)
+		writeJSON(w, http.StatusOK, h.Runner.SchedulerState())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
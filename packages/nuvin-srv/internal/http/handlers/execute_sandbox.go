@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+)
+
+// ExecuteSandboxHandler configures the workspace sandbox at
+// /execute-command/sandbox: GET returns the active confinement, PUT sets the
+// workspace root and isolation backend (empty root removes confinement).
+type ExecuteSandboxHandler struct {
+	Runner *command.Runner
+}
+
+type sandboxRequest struct {
+	Root    string `json:"root"`
+	Backend string `json:"backend,omitempty"`
+}
+
+func (h *ExecuteSandboxHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"sandbox": h.Runner.SandboxConfig()})
+	case http.MethodPut:
+		var req sandboxRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if err := h.Runner.SetSandbox(req.Root, req.Backend); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"sandbox": h.Runner.SandboxConfig()})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
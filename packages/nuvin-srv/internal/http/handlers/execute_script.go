@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+	"github.com/marschhuynh/nuvin-srv/internal/http/middleware"
+)
+
+// ExecuteScriptHandler runs a staged multi-line script at
+// POST /execute-script and returns the buffered result.
+type ExecuteScriptHandler struct {
+	Runner *command.Runner
+}
+
+func (h *ExecuteScriptHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req command.ScriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	req.Origin = middleware.Subject(r)
+
+	result, err := h.Runner.ExecuteScript(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
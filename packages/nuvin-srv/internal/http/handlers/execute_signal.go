@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+)
+
+// signalExecution handles POST {"signal": "SIGTERM"} against a running job or
+// streamed command ID. Shared by the jobs and execute-command handlers.
+func signalExecution(runner *command.Runner, w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var body struct {
+		Signal string `json:"signal"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := runner.Signal(id, body.Signal); err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "no running execution") {
+			status = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "rejected by policy") {
+			status = http.StatusForbidden
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "signal": body.Signal, "sent": true})
+}
+
+// SignalPolicyHandler configures which signals Signal may deliver, at
+// /execute-command/signal-policy (GET reads, PUT replaces).
+type SignalPolicyHandler struct {
+	Runner *command.Runner
+}
+
+func (h *SignalPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.Runner.SignalPolicy())
+	case http.MethodPut:
+		var p command.SignalPolicy
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		h.Runner.SetSignalPolicy(p)
+		writeJSON(w, http.StatusOK, h.Runner.SignalPolicy())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+)
+
+// ExecuteStdinHandler feeds input to a running streamed command at
+// POST /execute-command/{id}/stdin (raw body is written verbatim) and closes
+// its stdin on DELETE. The command ID comes from the stream's "start" event.
+type ExecuteStdinHandler struct {
+	Runner *command.Runner
+}
+
+func (h *ExecuteStdinHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/execute-command/")
+	if id, ok := strings.CutSuffix(rest, "/signal"); ok && id != "" && !strings.Contains(id, "/") {
+		signalExecution(h.Runner, w, r, id)
+		return
+	}
+	id, ok := strings.CutSuffix(rest, "/stdin")
+	if !ok || id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "reading request body: "+err.Error())
+			return
+		}
+		if err := h.Runner.WriteStdin(id, data); err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": id, "written": len(data)})
+	case http.MethodDelete:
+		if err := h.Runner.CloseStdin(id); err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": id, "closed": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
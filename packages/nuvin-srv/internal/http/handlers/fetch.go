@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// streamingContentTypes are upstream media types passed through chunk by
+// chunk instead of being buffered into a FetchResponse.
+var streamingContentTypes = map[string]bool{
+	"text/event-stream":       true,
+	"application/x-ndjson":    true,
+	"application/jsonl":       true,
+	"application/stream+json": true,
+}
+
+// FetchHandler exposes the FetchProxy over HTTP at POST /fetch.
+type FetchHandler struct {
+	Proxy *fetchproxy.FetchProxy
+}
+
+func (h *FetchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req fetchproxy.FetchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.URL == "" {
+		writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+
+	resp, err := h.Proxy.Do(req)
+	if err != nil {
+		var rateErr *fetchproxy.RateLimitError
+		if errors.As(err, &rateErr) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(rateErr.RetryAfter.Seconds())+1))
+			writeErrorCode(w, http.StatusTooManyRequests, err.Error(), "host_rate_limited")
+			return
+		}
+		var circuitErr *fetchproxy.CircuitOpenError
+		if errors.As(err, &circuitErr) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(circuitErr.RetryAfter.Seconds())+1))
+			writeErrorCode(w, http.StatusServiceUnavailable, err.Error(), "circuit_open")
+			return
+		}
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	if shouldStream(req, resp) {
+		streamThrough(w, resp, nil)
+		return
+	}
+
+	out, err := h.Proxy.ReadResponse(req, resp)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// shouldStream reports whether the response should be passed through as a
+// live stream: either the client forced it or the upstream speaks a known
+// streaming protocol (SSE, NDJSON).
+func shouldStream(req fetchproxy.FetchRequest, resp *http.Response) bool {
+	if req.Stream {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return streamingContentTypes[mediaType]
+}
+
+// streamThrough copies the upstream response to the client, flushing after
+// every chunk so tokens appear as the upstream emits them. A non-nil tap
+// also receives every chunk (for usage accounting).
+func streamThrough(w http.ResponseWriter, resp *http.Response, tap io.Writer) {
+	copyEndToEndHeaders(w.Header(), resp.Header)
+	w.WriteHeader(resp.StatusCode)
+
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if tap != nil {
+				_, _ = tap.Write(buf[:n])
+			}
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// FetchAbortHandler cancels an in-flight fetch at POST /fetch/abort/{requestId}.
+type FetchAbortHandler struct {
+	Proxy *fetchproxy.FetchProxy
+}
+
+func (h *FetchAbortHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	requestID := strings.TrimPrefix(r.URL.Path, "/fetch/abort/")
+	if requestID == "" || strings.Contains(requestID, "/") {
+		writeError(w, http.StatusBadRequest, "request ID is required")
+		return
+	}
+	if !h.Proxy.Abort(requestID) {
+		writeError(w, http.StatusNotFound, "no in-flight fetch with requestId "+requestID)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"requestId": requestID, "aborted": true})
+}
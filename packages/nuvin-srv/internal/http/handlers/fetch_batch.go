@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// defaultBatchConcurrency bounds parallel upstream requests when the client
+// does not specify a limit.
+const defaultBatchConcurrency = 4
+
+// BatchFetchRequest is the payload for POST /fetch/batch.
+type BatchFetchRequest struct {
+	Requests []fetchproxy.FetchRequest `json:"requests"`
+	// Concurrency caps how many requests run in parallel (default 4).
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// BatchFetchResult holds the outcome for one request, at the same index as
+// its request in the batch.
+type BatchFetchResult struct {
+	Response *fetchproxy.FetchResponse `json:"response,omitempty"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+// FetchBatchHandler executes a batch of fetches concurrently and returns the
+// results in request order.
+type FetchBatchHandler struct {
+	Proxy *fetchproxy.FetchProxy
+}
+
+func (h *FetchBatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var batch BatchFetchRequest
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if len(batch.Requests) == 0 {
+		writeError(w, http.StatusBadRequest, "requests array is empty")
+		return
+	}
+	concurrency := batch.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make([]BatchFetchResult, len(batch.Requests))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, req := range batch.Requests {
+		wg.Add(1)
+		go func(i int, req fetchproxy.FetchRequest) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resp, err := h.Proxy.Fetch(req)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Response = resp
+		}(i, req)
+	}
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, map[string]any{"results": results})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+func TestFetchBatchReturnsResultsInOrder(t *testing.T) {
+	var inFlight, maxInFlight int64
+	var mu sync.Mutex
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cur := atomic.AddInt64(&inFlight, 1)
+		mu.Lock()
+		if cur > maxInFlight {
+			maxInFlight = cur
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt64(&inFlight, -1)
+		_, _ = w.Write([]byte("resp:" + r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	batch := BatchFetchRequest{Concurrency: 2}
+	for i := 0; i < 6; i++ {
+		batch.Requests = append(batch.Requests, fetchproxy.FetchRequest{
+			URL: fmt.Sprintf("%s/%d", upstream.URL, i),
+		})
+	}
+	payload, _ := json.Marshal(batch)
+
+	h := &FetchBatchHandler{Proxy: fetchproxy.NewWithClient(upstream.Client())}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fetch/batch", bytes.NewReader(payload)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", rec.Code, rec.Body.String())
+	}
+
+	var out struct {
+		Results []BatchFetchResult `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Results) != 6 {
+		t.Fatalf("got %d results, want 6", len(out.Results))
+	}
+	for i, res := range out.Results {
+		if res.Error != "" {
+			t.Errorf("result %d error: %s", i, res.Error)
+			continue
+		}
+		if want := fmt.Sprintf("resp:/%d", i); res.Response.Body != want {
+			t.Errorf("result %d body = %q, want %q (order broken)", i, res.Response.Body, want)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("max in-flight upstream requests = %d, want <= 2", maxInFlight)
+	}
+}
+
+func TestFetchBatchRejectsEmpty(t *testing.T) {
+	h := &FetchBatchHandler{Proxy: fetchproxy.New()}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fetch/batch", bytes.NewReader([]byte(`{"requests":[]}`))))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// FetchCookiesHandler lets clients inspect (GET) and clear (DELETE) a fetch
+// cookie session at /fetch/cookies/{session}.
+type FetchCookiesHandler struct {
+	Proxy *fetchproxy.FetchProxy
+}
+
+func (h *FetchCookiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	session := strings.TrimPrefix(r.URL.Path, "/fetch/cookies/")
+	if session == "" || strings.Contains(session, "/") {
+		writeError(w, http.StatusBadRequest, "cookie session ID is required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"session": session,
+			"cookies": h.Proxy.SessionCookies(session),
+		})
+	case http.MethodDelete:
+		h.Proxy.ClearCookieSession(session)
+		writeJSON(w, http.StatusOK, map[string]any{"session": session, "cleared": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/capture"
+)
+
+// FetchHARHandler exports recorded traffic as a HAR 1.2 file at
+// GET /fetch/har, ready to drop into browser devtools.
+type FetchHARHandler struct {
+	Store *capture.Store
+}
+
+func (h *FetchHARHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="nuvin-traffic.har"`)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(h.Store.ExportHAR())
+}
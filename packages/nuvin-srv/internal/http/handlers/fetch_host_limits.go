@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+	"github.com/marschhuynh/nuvin-srv/internal/ratelimit"
+)
+
+// FetchHostLimitsHandler configures per-host fetch rate limits at
+// /fetch/host-limits: GET returns the limit table keyed by hostname, PUT
+// replaces it (hosts absent from the new table lose their limit).
+type FetchHostLimitsHandler struct {
+	Proxy *fetchproxy.FetchProxy
+}
+
+func (h *FetchHostLimitsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"limits": h.Proxy.HostLimits()})
+	case http.MethodPut:
+		var req struct {
+			Limits map[string]ratelimit.HostLimit `json:"limits"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		h.Proxy.SetHostLimits(req.Limits)
+		writeJSON(w, http.StatusOK, map[string]any{"limits": h.Proxy.HostLimits()})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// FetchPolicyHandler configures the fetch proxy's SSRF access policy at
+// /fetch/policy: GET returns the active policy, PUT replaces it. The policy
+// is enforced in the dialer, so it covers redirects and every fetch path.
+type FetchPolicyHandler struct {
+	Proxy *fetchproxy.FetchProxy
+}
+
+func (h *FetchPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"policy": h.Proxy.AccessPolicy()})
+	case http.MethodPut:
+		var req struct {
+			Policy fetchproxy.AccessPolicy `json:"policy"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if err := h.Proxy.SetAccessPolicy(req.Policy); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"policy": h.Proxy.AccessPolicy()})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/capture"
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// FetchRecordingsHandler lists recordings (GET /fetch/recordings), fetches
+// one (GET /fetch/recordings/{id}), and clears the store (DELETE
+// /fetch/recordings).
+type FetchRecordingsHandler struct {
+	Store *capture.Store
+}
+
+func (h *FetchRecordingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/fetch/recordings"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && id == "":
+		writeJSON(w, http.StatusOK, map[string]any{"recordings": h.Store.List()})
+	case r.Method == http.MethodGet:
+		rec, ok := h.Store.Get(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "no recording with id "+id)
+			return
+		}
+		writeJSON(w, http.StatusOK, rec)
+	case r.Method == http.MethodDelete && id == "":
+		h.Store.Clear()
+		writeJSON(w, http.StatusOK, map[string]any{"cleared": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// FetchReplayHandler replays a recording at POST /fetch/replay/{id}. By
+// default the recorded request is re-executed against the upstream; with
+// ?stored=true the recorded response is returned without touching the
+// network, for offline debugging.
+type FetchReplayHandler struct {
+	Store *capture.Store
+	Proxy *fetchproxy.FetchProxy
+}
+
+func (h *FetchReplayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/fetch/replay/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusBadRequest, "recording ID is required")
+		return
+	}
+	rec, ok := h.Store.Get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "no recording with id "+id)
+		return
+	}
+
+	if r.URL.Query().Get("stored") == "true" {
+		if rec.Response == nil {
+			writeError(w, http.StatusConflict, "recording has no stored response: "+rec.Error)
+			return
+		}
+		writeJSON(w, http.StatusOK, rec.Response)
+		return
+	}
+
+	resp, err := h.Proxy.Fetch(rec.Request)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
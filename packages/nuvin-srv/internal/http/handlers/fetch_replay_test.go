@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/capture"
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+func TestFetchCaptureAndReplay(t *testing.T) {
+	var calls int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte("response-body"))
+	}))
+	defer upstream.Close()
+
+	store := capture.NewStore(0)
+	proxy := fetchproxy.NewWithClient(upstream.Client())
+	proxy.SetCapture(store.RecordFetch)
+
+	if _, err := proxy.Fetch(fetchproxy.FetchRequest{URL: upstream.URL}); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	recs := store.List()
+	if len(recs) != 1 {
+		t.Fatalf("recorded %d fetches, want 1", len(recs))
+	}
+	rec := recs[0]
+	if rec.Request.URL != upstream.URL || rec.Response == nil || rec.Response.Body != "response-body" {
+		t.Errorf("recording incomplete: %+v", rec)
+	}
+
+	// Replay re-executes against the upstream.
+	replay := &FetchReplayHandler{Store: store, Proxy: proxy}
+	recorder := httptest.NewRecorder()
+	replay.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/fetch/replay/"+rec.ID, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("replay status = %d (%s)", recorder.Code, recorder.Body.String())
+	}
+	if calls != 2 {
+		t.Errorf("upstream called %d times, want 2 after replay", calls)
+	}
+
+	// Stored mode returns the recorded response without hitting upstream.
+	recorder = httptest.NewRecorder()
+	replay.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/fetch/replay/"+rec.ID+"?stored=true", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("stored replay status = %d", recorder.Code)
+	}
+	var resp fetchproxy.FetchResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "response-body" {
+		t.Errorf("stored replay body = %q", resp.Body)
+	}
+	if calls != 2 {
+		t.Errorf("stored replay hit the upstream (%d calls)", calls)
+	}
+
+	// Unknown IDs 404.
+	recorder = httptest.NewRecorder()
+	replay.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/fetch/replay/rec-missing", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("missing recording status = %d, want 404", recorder.Code)
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// FetchSecretsHandler manages the per-origin credential vault at
+// /fetch/secrets: GET lists origins (never values), POST saves a credential,
+// DELETE removes one. Origins contain slashes, so the target origin travels
+// in the JSON body rather than the path.
+type FetchSecretsHandler struct {
+	Proxy *fetchproxy.FetchProxy
+}
+
+type secretRequest struct {
+	Origin string `json:"origin"`
+	Header string `json:"header,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+func (h *FetchSecretsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"origins": h.Proxy.SecretOrigins()})
+	case http.MethodPost, http.MethodDelete:
+		var req secretRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if req.Origin == "" {
+			writeError(w, http.StatusBadRequest, "origin is required")
+			return
+		}
+		if r.Method == http.MethodPost && req.Value == "" {
+			writeError(w, http.StatusBadRequest, "value is required")
+			return
+		}
+		if r.Method == http.MethodDelete {
+			req.Value = ""
+		}
+		h.Proxy.SetOriginSecret(req.Origin, req.Header, req.Value)
+		writeJSON(w, http.StatusOK, map[string]any{"origin": req.Origin, "stored": r.Method == http.MethodPost})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
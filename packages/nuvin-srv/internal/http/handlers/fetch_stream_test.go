@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+func streamingFetch(t *testing.T, upstreamContentType string, force bool) []string {
+	t.Helper()
+	lines := []string{`{"token":"a"}`, `{"token":"b"}`, `{"token":"c"}`}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", upstreamContentType)
+		flusher := w.(http.Flusher)
+		for _, line := range lines {
+			_, _ = w.Write([]byte(line + "\n"))
+			flusher.Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+	}))
+	defer upstream.Close()
+
+	h := &FetchHandler{Proxy: fetchproxy.NewWithClient(upstream.Client())}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	payload, _ := json.Marshal(fetchproxy.FetchRequest{URL: upstream.URL, Stream: force})
+	resp, err := http.Post(srv.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var got []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		got = append(got, scanner.Text())
+	}
+	return got
+}
+
+func TestFetchStreamsNDJSON(t *testing.T) {
+	got := streamingFetch(t, "application/x-ndjson", false)
+	if len(got) != 3 || got[0] != `{"token":"a"}` {
+		t.Errorf("streamed lines = %v, want the raw NDJSON lines", got)
+	}
+}
+
+func TestFetchStreamForced(t *testing.T) {
+	// An unrecognized content type still streams when the client forces it.
+	got := streamingFetch(t, "application/octet-stream", true)
+	if len(got) != 3 {
+		t.Errorf("streamed %d lines, want 3", len(got))
+	}
+}
+
+func TestFetchBuffersPlainJSON(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"done":true}`))
+	}))
+	defer upstream.Close()
+
+	h := &FetchHandler{Proxy: fetchproxy.NewWithClient(upstream.Client())}
+	payload, _ := json.Marshal(fetchproxy.FetchRequest{URL: upstream.URL})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fetch", bytes.NewReader(payload)))
+
+	var out fetchproxy.FetchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("non-streaming response is not a FetchResponse: %v", err)
+	}
+	if out.Body != `{"done":true}` {
+		t.Errorf("body = %q", out.Body)
+	}
+}
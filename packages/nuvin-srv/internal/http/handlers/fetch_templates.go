@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+	"github.com/marschhuynh/nuvin-srv/internal/reqtemplate"
+)
+
+// FetchTemplatesHandler manages named request templates at /fetch/templates
+// (GET lists, POST saves) and /fetch/templates/{name} (GET, DELETE).
+type FetchTemplatesHandler struct {
+	Store *reqtemplate.Store
+}
+
+func (h *FetchTemplatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/fetch/templates")
+	name = strings.TrimPrefix(name, "/")
+	if strings.Contains(name, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"templates": h.Store.List()})
+		case http.MethodPost:
+			var tpl reqtemplate.Template
+			if err := json.NewDecoder(r.Body).Decode(&tpl); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			if err := h.Store.Save(tpl); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, tpl)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		tpl, ok := h.Store.Get(name)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown template: "+name)
+			return
+		}
+		writeJSON(w, http.StatusOK, tpl)
+	case http.MethodDelete:
+		h.Store.Delete(name)
+		writeJSON(w, http.StatusOK, map[string]any{"name": name, "deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// RunTemplateHandler renders a stored template with client-supplied variables
+// and executes the result through the FetchProxy at POST /fetch/run-template.
+type RunTemplateHandler struct {
+	Store *reqtemplate.Store
+	Proxy *fetchproxy.FetchProxy
+}
+
+type runTemplateRequest struct {
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+}
+
+func (h *RunTemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var run runTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&run); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	tpl, ok := h.Store.Get(run.Name)
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown template: "+run.Name)
+		return
+	}
+	req, err := reqtemplate.Render(tpl, run.Variables)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.Proxy.Fetch(req)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
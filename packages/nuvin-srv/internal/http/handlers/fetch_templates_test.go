@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+	"github.com/marschhuynh/nuvin-srv/internal/reqtemplate"
+)
+
+func TestRunTemplateRendersAndExecutes(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("path=" + r.URL.Path + " auth=" + r.Header.Get("Authorization")))
+	}))
+	defer upstream.Close()
+
+	store := reqtemplate.NewStore()
+	if err := store.Save(reqtemplate.Template{
+		Name: "get-user",
+		Request: fetchproxy.FetchRequest{
+			URL:     upstream.URL + "/users/{{id}}",
+			Headers: map[string]string{"Authorization": "Bearer {{token}}"},
+		},
+	}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	h := &RunTemplateHandler{Store: store, Proxy: fetchproxy.New()}
+	body := `{"name":"get-user","variables":{"id":"42","token":"tok"}}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fetch/run-template", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var resp fetchproxy.FetchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Body != "path=/users/42 auth=Bearer tok" {
+		t.Errorf("upstream saw %q", resp.Body)
+	}
+}
+
+func TestRunTemplateRejectsMissingVariables(t *testing.T) {
+	store := reqtemplate.NewStore()
+	store.Save(reqtemplate.Template{Name: "t", Request: fetchproxy.FetchRequest{URL: "https://x/{{id}}"}})
+	h := &RunTemplateHandler{Store: store, Proxy: fetchproxy.New()}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fetch/run-template", strings.NewReader(`{"name":"t"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fetch/run-template", strings.NewReader(`{"name":"nope"}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown template status = %d, want 404", rec.Code)
+	}
+}
+
+func TestFetchTemplatesCRUDOverHTTP(t *testing.T) {
+	store := reqtemplate.NewStore()
+	h := &FetchTemplatesHandler{Store: store}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fetch/templates",
+		strings.NewReader(`{"name":"a","request":{"url":"https://a"}}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fetch/templates/a", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "https://a") {
+		t.Fatalf("get status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/fetch/templates/a", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fetch/templates/a", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("get after delete status = %d, want 404", rec.Code)
+	}
+}
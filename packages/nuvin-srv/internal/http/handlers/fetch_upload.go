@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// FetchUploadHandler stages large request bodies chunk by chunk:
+// POST /fetch/upload/start creates an upload, POST /fetch/upload/{id}
+// appends the raw request body as a chunk, DELETE /fetch/upload/{id}
+// discards it. The staged body is sent by a fetch with uploadId set.
+type FetchUploadHandler struct {
+	Proxy *fetchproxy.FetchProxy
+}
+
+func (h *FetchUploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/fetch/upload/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusBadRequest, "upload ID is required")
+		return
+	}
+
+	if id == "start" {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		uploadID, err := h.Proxy.StartUpload()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"uploadId": uploadID})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		size, err := h.Proxy.AppendUpload(id, r.Body)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"uploadId": id, "size": size})
+	case http.MethodDelete:
+		h.Proxy.DiscardUpload(id)
+		writeJSON(w, http.StatusOK, map[string]any{"uploadId": id, "discarded": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/gitops"
+)
+
+// GitHandler exposes the git operations suite under /git/{op}. Every
+// operation is a POST with a JSON body naming the repository path, so the UI
+// never assembles raw shell commands for VCS work.
+type GitHandler struct {
+	Service *gitops.Service
+}
+
+type gitRequest struct {
+	Repo    string `json:"repo"`
+	Staged  bool   `json:"staged,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message,omitempty"`
+	AddAll  bool   `json:"addAll,omitempty"`
+	Patch   string `json:"patch,omitempty"`
+}
+
+func (h *GitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	op := strings.TrimPrefix(r.URL.Path, "/git/")
+	var req gitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	switch op {
+	case "status":
+		st, err := h.Service.Status(req.Repo)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, st)
+	case "diff":
+		diff, err := h.Service.Diff(req.Repo, req.Staged, req.Path)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"diff": diff})
+	case "log":
+		entries, err := h.Service.Log(req.Repo, req.Limit)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"commits": entries})
+	case "branch":
+		if req.Name != "" {
+			if err := h.Service.CreateBranch(req.Repo, req.Name); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		branches, err := h.Service.Branches(req.Repo)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, branches)
+	case "commit":
+		hash, err := h.Service.Commit(req.Repo, req.Message, req.AddAll)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"hash": hash})
+	case "apply-patch":
+		if err := h.Service.ApplyPatch(req.Repo, req.Patch); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"applied": true})
+	default:
+		writeError(w, http.StatusNotFound, "unknown git operation: "+op)
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+)
+
+// CopilotModelsHandler serves the normalized Copilot model catalog at GET
+// /github/copilot/models; add ?refresh to bypass the cache.
+type CopilotModelsHandler struct {
+	Catalog *github.ModelCatalog
+}
+
+func (h *CopilotModelsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	models, err := h.Catalog.Models(r.Context(), r.URL.Query().Has("refresh"))
+	if err != nil {
+		if !h.Catalog.Tokens.HasGitHubToken() {
+			writeErrorCode(w, http.StatusConflict, err.Error(), "github_token_missing")
+			return
+		}
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"models": models})
+}
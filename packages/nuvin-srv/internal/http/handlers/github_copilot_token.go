@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+)
+
+// CopilotTokenHandler manages the Copilot token exchange. PUT
+// /github/copilot-token stores the GitHub OAuth token; GET
+// /github/copilot-token/current returns a Copilot bearer token the manager
+// keeps fresh, so clients never deal with the ~30-minute expiry themselves.
+type CopilotTokenHandler struct {
+	Manager *github.TokenManager
+}
+
+func (h *CopilotTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/github/copilot-token")
+	rest = strings.Trim(rest, "/")
+	switch {
+	case rest == "" && r.Method == http.MethodPut:
+		h.setToken(w, r)
+	case rest == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"configured": h.Manager.HasGitHubToken()})
+	case rest == "current" && r.Method == http.MethodGet:
+		h.current(w, r)
+	case rest == "" || rest == "current":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *CopilotTokenHandler) setToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GitHubToken string `json:"githubToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := h.Manager.SetGitHubToken(req.GitHubToken); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"configured": true})
+}
+
+func (h *CopilotTokenHandler) current(w http.ResponseWriter, r *http.Request) {
+	tok, err := h.Manager.Current(r.Context())
+	if err != nil {
+		if !h.Manager.HasGitHubToken() {
+			writeErrorCode(w, http.StatusConflict, err.Error(), "github_token_missing")
+			return
+		}
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, tok)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+)
+
+func TestCopilotTokenHandlerLifecycle(t *testing.T) {
+	exchange := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"token":"cop_live","expires_at":%d}`, time.Now().Add(30*time.Minute).Unix())
+	}))
+	defer exchange.Close()
+
+	mgr := github.NewTokenManager()
+	mgr.SetExchangeURL(exchange.URL)
+	h := &CopilotTokenHandler{Manager: mgr}
+
+	// Asking for a token before configuring the OAuth token is a conflict.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/github/copilot-token/current", nil))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/github/copilot-token",
+		strings.NewReader(`{"githubToken":"gho_abc"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("store status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/github/copilot-token/current", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("current status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var tok struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &tok); err != nil {
+		t.Fatal(err)
+	}
+	if tok.Token != "cop_live" || !tok.ExpiresAt.After(time.Now()) {
+		t.Errorf("token = %+v", tok)
+	}
+
+	// The status probe never leaks the raw OAuth token.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/github/copilot-token", nil))
+	if rec.Body.String() != "{\"configured\":true}\n" || strings.Contains(rec.Body.String(), "gho_abc") {
+		t.Errorf("status body = %q", rec.Body.String())
+	}
+}
+
+func TestCopilotTokenHandlerRejectsEmptyToken(t *testing.T) {
+	h := &CopilotTokenHandler{Manager: github.NewTokenManager()}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/github/copilot-token",
+		strings.NewReader(`{}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+)
+
+// longPollTimeout bounds how long a ?wait request may hang before the
+// current (still pending) snapshot is returned.
+const longPollTimeout = 25 * time.Second
+
+// DeviceFlowHandler runs GitHub device-flow sessions entirely server-side.
+// POST /github/device-flow/session starts one and returns the user code;
+// GET /github/device-flow/session/{id} reads it back (add ?wait to
+// long-poll until it resolves), and GET .../{id}/events streams status
+// changes over SSE. The device code and the polling loop never leave the
+// server.
+type DeviceFlowHandler struct {
+	Flow *github.DeviceFlow
+}
+
+func (h *DeviceFlowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/github/device-flow/session"), "/")
+	switch {
+	case rest == "" && r.Method == http.MethodPost:
+		h.start(w, r)
+	case rest == "":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	case strings.HasSuffix(rest, "/events") && r.Method == http.MethodGet:
+		h.events(w, r, strings.TrimSuffix(rest, "/events"))
+	case !strings.Contains(rest, "/") && r.Method == http.MethodGet:
+		h.get(w, r, rest)
+	case !strings.Contains(rest, "/"):
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *DeviceFlowHandler) start(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ClientID string `json:"clientId"`
+		Scope    string `json:"scope"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	sess, err := h.Flow.Start(r.Context(), req.ClientID, req.Scope)
+	if err != nil {
+		if req.ClientID == "" {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, sess)
+}
+
+func (h *DeviceFlowHandler) get(w http.ResponseWriter, r *http.Request, id string) {
+	var sess github.Session
+	var ok bool
+	if r.URL.Query().Has("wait") {
+		ctx, cancel := context.WithTimeout(r.Context(), longPollTimeout)
+		defer cancel()
+		sess, ok = h.Flow.Wait(ctx, id)
+	} else {
+		sess, ok = h.Flow.Session(id)
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown session")
+		return
+	}
+	writeJSON(w, http.StatusOK, sess)
+}
+
+func (h *DeviceFlowHandler) events(w http.ResponseWriter, r *http.Request, id string) {
+	done, ok := h.Flow.Done(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown session")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	emit := func() {
+		sess, _ := h.Flow.Session(id)
+		data, _ := json.Marshal(sess)
+		fmt.Fprintf(w, "event: status\ndata: %s\n\n", data)
+		flusher.Flush()
+	}
+	emit()
+	select {
+	case <-done:
+		emit()
+	case <-r.Context().Done():
+	}
+}
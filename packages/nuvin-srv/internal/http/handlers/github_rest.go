@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+)
+
+// GitHubAPIHandler exposes thin GitHub REST conveniences using the stored
+// OAuth token: GET /github/repos, /github/issues?repo=owner/name, and
+// /github/prs?repo=owner/name, with page/perPage pagination and
+// rate-limit-aware errors.
+type GitHubAPIHandler struct {
+	REST *github.REST
+}
+
+func (h *GitHubAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("perPage"))
+
+	var items any
+	var pg github.Page
+	var err error
+	switch r.URL.Path {
+	case "/github/repos":
+		items, pg, err = h.REST.Repos(r.Context(), page, perPage)
+	case "/github/issues":
+		items, pg, err = h.REST.Issues(r.Context(), q.Get("repo"), q.Get("state"), page, perPage)
+	case "/github/prs":
+		items, pg, err = h.REST.PRs(r.Context(), q.Get("repo"), q.Get("state"), page, perPage)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		var rl *github.RateLimitError
+		if errors.As(err, &rl) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(rl.ResetAt).Seconds())+1))
+			writeErrorCode(w, http.StatusTooManyRequests, err.Error(), "github_rate_limited")
+			return
+		}
+		if !h.REST.Tokens.HasGitHubToken() {
+			writeErrorCode(w, http.StatusConflict, err.Error(), "github_token_missing")
+			return
+		}
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "page": pg.Page, "nextPage": pg.NextPage})
+}
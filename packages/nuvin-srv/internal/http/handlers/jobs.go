@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+)
+
+// JobsHandler exposes background command jobs: GET /jobs lists them,
+// GET /jobs/{id} returns status and output, POST /jobs/{id}/cancel stops one.
+type JobsHandler struct {
+	Runner *command.Runner
+}
+
+func (h *JobsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/jobs")
+	rest = strings.TrimPrefix(rest, "/")
+
+	if rest == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"jobs": h.Runner.Jobs()})
+		return
+	}
+
+	if id, ok := strings.CutSuffix(rest, "/cancel"); ok {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if !h.Runner.CancelJob(id) {
+			writeError(w, http.StatusConflict, "job is not cancellable: "+id)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": id, "cancelled": true})
+		return
+	}
+
+	if id, ok := strings.CutSuffix(rest, "/signal"); ok {
+		signalExecution(h.Runner, w, r, id)
+		return
+	}
+
+	if strings.Contains(rest, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	job, ok := h.Runner.Job(rest)
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown job: "+rest)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
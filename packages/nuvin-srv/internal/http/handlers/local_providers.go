@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/discovery"
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+// LocalProvidersHandler scans for local inference servers at GET
+// /providers/local and lists what it found. Each detected server gets a
+// proxy route named after its kind ("ollama", "lmstudio", "llamacpp") so
+// its models are immediately reachable under /proxy/{kind}/; routes the
+// operator already configured are left untouched.
+type LocalProvidersHandler struct {
+	Scanner *discovery.Scanner
+	Proxy   *proxy.Service
+}
+
+func (h *LocalProvidersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	servers := h.Scanner.Scan()
+	for _, srv := range servers {
+		if _, exists := h.Proxy.Route(srv.Kind); exists {
+			continue
+		}
+		// Best effort: a server that vanished between probe and registration
+		// just stays unrouted until the next scan.
+		_ = h.Proxy.SetRoute(proxy.Route{Name: srv.Kind, Upstream: srv.URL})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"servers": servers})
+}
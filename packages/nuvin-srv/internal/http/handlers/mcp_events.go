@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPEventsSSEHandler streams MCP process events as Server-Sent Events at
+// GET /api/mcp/events (all processes) or /api/mcp/events/{id} (one process).
+// Each event carries its sequence number as the SSE id, so a reconnecting
+// client sends Last-Event-ID (or ?after=N) and resumes from the retained
+// replay buffer instead of silently losing output. The optional ?buffer=N
+// query sizes the subscriber ring.
+type MCPEventsSSEHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPEventsSSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	procID := strings.TrimPrefix(r.URL.Path, "/api/mcp/events")
+	procID = strings.TrimPrefix(procID, "/")
+	if strings.Contains(procID, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	capacity, _ := strconv.Atoi(r.URL.Query().Get("buffer"))
+	after, _ := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+	if after == 0 {
+		after, _ = strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	sub := h.Manager.SubscribeFrom(procID, capacity, after)
+	defer h.Manager.Unsubscribe(sub)
+
+	// Close the subscriber when the client goes away so Next unblocks.
+	go func() {
+		<-r.Context().Done()
+		sub.Close()
+	}()
+
+	for {
+		ev, ok := sub.Next()
+		if !ok {
+			return
+		}
+		data, err := json.Marshal(ev)
+		if err != nil {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Type, data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPGroupsHandler manages named server groups under /api/mcp/groups: GET
+// lists them, POST saves one, GET/DELETE /api/mcp/groups/{name} inspects or
+// removes one, and POST .../{name}/start and .../{name}/stop run the bulk
+// operation with per-server results.
+type MCPGroupsHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPGroupsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/mcp/groups")
+	rest = strings.TrimPrefix(rest, "/")
+
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"groups": h.Manager.Groups()})
+		case http.MethodPost:
+			var g mcp.Group
+			if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			if err := h.Manager.SaveGroup(g); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, g)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if name, ok := strings.CutSuffix(rest, "/start"); ok {
+		h.bulk(w, r, name, h.Manager.StartGroup)
+		return
+	}
+	if name, ok := strings.CutSuffix(rest, "/stop"); ok {
+		h.bulk(w, r, name, h.Manager.StopGroup)
+		return
+	}
+
+	if strings.Contains(rest, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		g, ok := h.Manager.Group(rest)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown mcp group: "+rest)
+			return
+		}
+		writeJSON(w, http.StatusOK, g)
+	case http.MethodDelete:
+		h.Manager.DeleteGroup(rest)
+		writeJSON(w, http.StatusOK, map[string]any{"name": rest, "deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *MCPGroupsHandler) bulk(w http.ResponseWriter, r *http.Request, name string, op func(string) ([]mcp.GroupResult, error)) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	results, err := op(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"name": name, "results": results})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPHealthHandler exposes the ping checker: GET/PUT /api/mcp/health reads or
+// replaces the config, POST /api/mcp/health/check runs one sweep immediately.
+type MCPHealthHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPHealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/mcp/health/check" {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.Manager.CheckHealth()
+		writeJSON(w, http.StatusOK, map[string]any{"checked": true})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.Manager.HealthConfig())
+	case http.MethodPut:
+		var cfg mcp.HealthConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		h.Manager.SetHealthConfig(cfg)
+		writeJSON(w, http.StatusOK, h.Manager.HealthConfig())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPHTTPHandler manages Streamable HTTP MCP sessions under /api/mcp/http:
+// POST connects (running the handshake), GET lists sessions, and DELETE
+// /api/mcp/http/{id} disconnects one. Calls against these sessions go through
+// the shared /api/mcp/stdio/call endpoint using the "mcph-" connection ID.
+type MCPHTTPHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/mcp/http")
+	rest = strings.TrimPrefix(rest, "/")
+
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"connections": h.Manager.HTTPConnections()})
+		case http.MethodPost:
+			var req mcp.ConnectHTTPRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+			defer cancel()
+			conn, err := h.Manager.ConnectHTTP(ctx, req)
+			if err != nil {
+				writeError(w, http.StatusBadGateway, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, conn)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if strings.Contains(rest, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err := h.Manager.DisconnectHTTP(rest); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": rest, "disconnected": true})
+}
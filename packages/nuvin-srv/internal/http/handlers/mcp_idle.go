@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPIdleHandler exposes the idle reaper: GET/PUT /api/mcp/idle reads or
+// replaces the config, POST /api/mcp/idle/reap runs one sweep immediately.
+type MCPIdleHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPIdleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/mcp/idle/reap" {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		reaped := h.Manager.ReapIdle()
+		if reaped == nil {
+			reaped = []string{}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"reaped": reaped})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.Manager.IdleConfig())
+	case http.MethodPut:
+		var cfg mcp.IdleConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		h.Manager.SetIdleConfig(cfg)
+		writeJSON(w, http.StatusOK, h.Manager.IdleConfig())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
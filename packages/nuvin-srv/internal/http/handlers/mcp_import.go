@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPImportHandler ingests standard mcpServers config files at
+// POST /api/mcp/import, registering each server definition and optionally
+// starting them ({"config": <mcp.json contents>, "start": true}).
+type MCPImportHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPImportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var body struct {
+		Config json.RawMessage `json:"config"`
+		Start  bool            `json:"start,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	result, err := h.Manager.ImportServers(body.Config, body.Start)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
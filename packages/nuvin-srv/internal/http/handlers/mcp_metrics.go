@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPMetricsHandler reports per-process resource usage: GET /api/mcp/metrics
+// returns one snapshot, GET /api/mcp/metrics/stream pushes snapshots over SSE
+// every ?intervalMs (default 2000) for the live dashboard.
+type MCPMetricsHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if r.URL.Path != "/api/mcp/metrics/stream" {
+		writeJSON(w, http.StatusOK, map[string]any{"metrics": h.Manager.Metrics()})
+		return
+	}
+
+	interval := 2 * time.Second
+	if ms, err := strconv.Atoi(r.URL.Query().Get("intervalMs")); err == nil && ms > 0 {
+		interval = time.Duration(ms) * time.Millisecond
+	}
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		data, err := json.Marshal(h.Manager.Metrics())
+		if err != nil {
+			return
+		}
+		w.Write([]byte("event: metrics\ndata: "))
+		w.Write(data)
+		w.Write([]byte("\n\n"))
+		if flusher != nil {
+			flusher.Flush()
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
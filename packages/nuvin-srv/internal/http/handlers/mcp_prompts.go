@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPPromptsHandler serves the consolidated prompt catalog at GET
+// /api/mcp/prompts and proxies POST /api/mcp/prompts/get
+// ({"server", "name", "arguments"}) so the UI can render prompt pickers
+// without speaking the protocol itself.
+type MCPPromptsHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPPromptsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/mcp/prompts/get" {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req struct {
+			Server    string            `json:"server"`
+			Name      string            `json:"name"`
+			Arguments map[string]string `json:"arguments,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+		defer cancel()
+		result, err := h.Manager.GetPrompt(ctx, req.Server, req.Name, req.Arguments)
+		if err != nil {
+			status := http.StatusBadGateway
+			if strings.Contains(err.Error(), "unknown mcp process") {
+				status = http.StatusNotFound
+			} else if strings.Contains(err.Error(), "required") {
+				status = http.StatusBadRequest
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	writeJSON(w, http.StatusOK, h.Manager.ListPrompts(ctx))
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPRegistryHandler exposes the server registry: GET/PUT /api/mcp/registry
+// reads or replaces the config, GET /api/mcp/registry/servers lists the
+// installable entries, and POST /api/mcp/registry/install ({"name", "start"})
+// installs one and registers it as a stdio server.
+type MCPRegistryHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPRegistryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/api/mcp/registry/servers":
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		entries, err := h.Manager.FetchRegistry(r.Context())
+		if err != nil {
+			status := http.StatusBadGateway
+			if strings.Contains(err.Error(), "no registry configured") {
+				status = http.StatusBadRequest
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"servers": entries})
+
+	case r.URL.Path == "/api/mcp/registry/install":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req struct {
+			Name  string `json:"name"`
+			Start bool   `json:"start,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		result, err := h.Manager.InstallFromRegistry(r.Context(), req.Name, req.Start)
+		if err != nil {
+			status := http.StatusBadGateway
+			if strings.Contains(err.Error(), "unknown registry server") {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+
+	default:
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, h.Manager.RegistryConfig())
+		case http.MethodPut:
+			var cfg mcp.RegistryConfig
+			if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			h.Manager.SetRegistryConfig(cfg)
+			writeJSON(w, http.StatusOK, h.Manager.RegistryConfig())
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPResourcesHandler manages resource watches at /api/mcp/resources: GET
+// lists active subscriptions, POST /subscribe and /unsubscribe pass the
+// request through to the server ({"id": <process>, "uri": <resource>}).
+type MCPResourcesHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPResourcesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/mcp/resources")
+	rest = strings.TrimPrefix(rest, "/")
+
+	if rest == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"subscriptions": h.Manager.ResourceSubscriptions()})
+		return
+	}
+	if rest != "subscribe" && rest != "unsubscribe" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req struct {
+		ID  string `json:"id"`
+		URI string `json:"uri"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	var err error
+	if rest == "subscribe" {
+		err = h.Manager.SubscribeResource(ctx, req.ID, req.URI)
+	} else {
+		err = h.Manager.UnsubscribeResource(ctx, req.ID, req.URI)
+	}
+	if err != nil {
+		status := http.StatusBadGateway
+		if strings.Contains(err.Error(), "unknown mcp process") {
+			status = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "required") {
+			status = http.StatusBadRequest
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": req.ID, "uri": req.URI})
+}
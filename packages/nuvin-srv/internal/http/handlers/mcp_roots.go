@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+	"github.com/marschhuynh/nuvin-srv/internal/workspace"
+)
+
+// MCPRootsHandler manages the workspace roots advertised to MCP servers at
+// /api/mcp/roots: GET returns them, PUT replaces them (entries may carry a
+// local "path" instead of a URI, and {"fromWorkspace": true} syncs from the
+// configured workspace root). Changes notify running servers.
+type MCPRootsHandler struct {
+	Manager   *mcp.Manager
+	Workspace *workspace.Service
+}
+
+type rootEntry struct {
+	URI  string `json:"uri,omitempty"`
+	Path string `json:"path,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func (h *MCPRootsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"roots": h.Manager.Roots()})
+	case http.MethodPut:
+		var req struct {
+			Roots         []rootEntry `json:"roots,omitempty"`
+			FromWorkspace bool        `json:"fromWorkspace,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		var roots []mcp.Root
+		if req.FromWorkspace {
+			root := h.Workspace.Root()
+			if root == "" {
+				writeError(w, http.StatusBadRequest, "no workspace root configured")
+				return
+			}
+			roots = append(roots, mcp.FileRoot(root, filepath.Base(root)))
+		}
+		for _, entry := range req.Roots {
+			switch {
+			case entry.URI != "":
+				roots = append(roots, mcp.Root{URI: entry.URI, Name: entry.Name})
+			case entry.Path != "":
+				roots = append(roots, mcp.FileRoot(entry.Path, entry.Name))
+			default:
+				writeError(w, http.StatusBadRequest, "root entries need a uri or path")
+				return
+			}
+		}
+		h.Manager.SetRoots(roots)
+		writeJSON(w, http.StatusOK, map[string]any{"roots": h.Manager.Roots()})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
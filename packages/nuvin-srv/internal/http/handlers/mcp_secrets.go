@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+// MCPSecretsHandler manages named secrets for MCP server environments at
+// /api/mcp/secrets: GET lists names only, PUT stores a value (empty value
+// removes it). Raw values are never returned.
+type MCPSecretsHandler struct {
+	Store *secrets.Store
+}
+
+func (h *MCPSecretsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"names": h.Store.Names()})
+	case http.MethodPut:
+		var req struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if err := h.Store.Set(req.Name, req.Value); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"names": h.Store.Names()})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPServersHandler manages persisted MCP server definitions under
+// /api/mcp/servers: GET lists them with live status and restart counts, POST
+// saves one, GET/DELETE /api/mcp/servers/{name} inspects or removes one, and
+// POST .../{name}/start and .../{name}/stop control the supervised instance.
+type MCPServersHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPServersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/mcp/servers")
+	rest = strings.TrimPrefix(rest, "/")
+
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"servers": h.Manager.Servers()})
+		case http.MethodPost:
+			var def mcp.ServerDef
+			if err := json.NewDecoder(r.Body).Decode(&def); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			if err := h.Manager.SaveDef(def); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, def)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if name, ok := strings.CutSuffix(rest, "/start"); ok {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		p, err := h.Manager.StartServer(name)
+		if err != nil {
+			status := http.StatusBadRequest
+			if strings.Contains(err.Error(), "unknown mcp server") {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, p.Info())
+		return
+	}
+	if name, ok := strings.CutSuffix(rest, "/stop"); ok {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if err := h.Manager.StopServer(name); err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"name": name, "stopped": true})
+		return
+	}
+
+	if strings.Contains(rest, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		def, ok := h.Manager.Def(rest)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown mcp server: "+rest)
+			return
+		}
+		writeJSON(w, http.StatusOK, def)
+	case http.MethodDelete:
+		h.Manager.DeleteDef(rest)
+		writeJSON(w, http.StatusOK, map[string]any{"name": rest, "deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/http/middleware"
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPStdioHandler manages MCP stdio servers under /api/mcp/stdio:
+// POST starts one, GET lists them, GET/DELETE /api/mcp/stdio/{id} inspects or
+// stops one, POST /api/mcp/stdio/call sends a JSON-RPC request and returns the
+// correlated response, and POST /api/mcp/stdio/send writes a single framed
+// message without waiting for a reply.
+type MCPStdioHandler struct {
+	Manager *mcp.Manager
+}
+
+type mcpCallRequest struct {
+	ID        string          `json:"id"`
+	Method    string          `json:"method"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	TimeoutMs int             `json:"timeoutMs,omitempty"`
+}
+
+// ownedProc resolves a stdio process the subject may interact with; processes
+// owned by other subjects are reported as unknown rather than forbidden so
+// their existence does not leak.
+func (h *MCPStdioHandler) ownedProc(id, subject string) (*mcp.Process, bool) {
+	p, ok := h.Manager.Get(id)
+	if !ok || !p.AccessibleBy(subject) {
+		return nil, false
+	}
+	return p, true
+}
+
+func (h *MCPStdioHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/mcp/stdio")
+	rest = strings.TrimPrefix(rest, "/")
+	subject := middleware.Subject(r)
+
+	switch {
+	case rest == "":
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"processes": h.Manager.ListAccessible(subject)})
+		case http.MethodPost:
+			var req mcp.StartRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			req.Owner = subject
+			p, err := h.Manager.Start(req)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, p.Info())
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case rest == "call":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req mcpCallRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if p, ok := h.Manager.Get(req.ID); ok && !p.AccessibleBy(subject) {
+			writeError(w, http.StatusNotFound, "unknown mcp process: "+req.ID)
+			return
+		}
+		timeout := 30 * time.Second
+		if req.TimeoutMs > 0 {
+			timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		resp, err := h.Manager.Call(ctx, req.ID, req.Method, req.Params)
+		if err != nil {
+			status := http.StatusBadGateway
+			if strings.Contains(err.Error(), "unknown mcp process") {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case rest == "respond":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req struct {
+			CorrelationID string          `json:"correlationId"`
+			Result        json.RawMessage `json:"result,omitempty"`
+			Error         *mcp.RPCError   `json:"error,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		procID, ok := h.Manager.RelayProcess(req.CorrelationID)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown correlation id: "+req.CorrelationID)
+			return
+		}
+		if _, ok := h.ownedProc(procID, subject); !ok {
+			writeError(w, http.StatusNotFound, "unknown correlation id: "+req.CorrelationID)
+			return
+		}
+		if err := h.Manager.Respond(req.CorrelationID, req.Result, req.Error); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"correlationId": req.CorrelationID, "responded": true})
+	case rest == "quota":
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"maxPerOwner": h.Manager.ProcessQuota()})
+		case http.MethodPut:
+			var req struct {
+				MaxPerOwner int `json:"maxPerOwner"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			h.Manager.SetProcessQuota(req.MaxPerOwner)
+			writeJSON(w, http.StatusOK, map[string]any{"maxPerOwner": req.MaxPerOwner})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case rest == "send":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req struct {
+			ID      string          `json:"id"`
+			Message json.RawMessage `json:"message"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if _, ok := h.ownedProc(req.ID, subject); !ok {
+			writeError(w, http.StatusNotFound, "unknown mcp process: "+req.ID)
+			return
+		}
+		if err := h.Manager.Send(req.ID, req.Message); err != nil {
+			status := http.StatusBadRequest
+			if strings.Contains(err.Error(), "unknown mcp process") {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": req.ID, "sent": true})
+	case strings.HasSuffix(rest, "/pin"):
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id := strings.TrimSuffix(rest, "/pin")
+		if _, ok := h.ownedProc(id, subject); !ok {
+			writeError(w, http.StatusNotFound, "unknown mcp process: "+id)
+			return
+		}
+		var req struct {
+			Pinned *bool `json:"pinned"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		pinned := true
+		if req.Pinned != nil {
+			pinned = *req.Pinned
+		}
+		if err := h.Manager.PinProcess(id, pinned); err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"id": id, "pinned": pinned})
+	case !strings.Contains(rest, "/"):
+		switch r.Method {
+		case http.MethodGet:
+			p, ok := h.ownedProc(rest, subject)
+			if !ok {
+				writeError(w, http.StatusNotFound, "unknown mcp process: "+rest)
+				return
+			}
+			writeJSON(w, http.StatusOK, p.Info())
+		case http.MethodDelete:
+			if _, ok := h.ownedProc(rest, subject); !ok {
+				writeError(w, http.StatusNotFound, "unknown mcp process: "+rest)
+				return
+			}
+			if err := h.Manager.Stop(rest); err != nil {
+				writeError(w, http.StatusNotFound, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"id": rest, "stopped": true})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPToolCallHandler proxies tool invocations at POST /api/mcp/tools/call
+// ({"server", "tool", "arguments", "timeoutMs"}), routing the call to the
+// right server with a per-call timeout and returning structured content
+// blocks.
+type MCPToolCallHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPToolCallHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req struct {
+		Server    string          `json:"server,omitempty"`
+		Tool      string          `json:"tool"`
+		Arguments json.RawMessage `json:"arguments,omitempty"`
+		TimeoutMs int             `json:"timeoutMs,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	timeout := 30 * time.Second
+	if req.TimeoutMs > 0 {
+		timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	result, err := h.Manager.CallTool(ctx, req.Server, req.Tool, req.Arguments)
+	if err != nil {
+		status := http.StatusBadGateway
+		switch {
+		case strings.Contains(err.Error(), "unknown mcp process"), strings.Contains(err.Error(), "unknown tool"):
+			status = http.StatusNotFound
+		case strings.Contains(err.Error(), "required"), strings.Contains(err.Error(), "specify one"):
+			status = http.StatusBadRequest
+		case strings.Contains(err.Error(), "deadline exceeded"):
+			status = http.StatusGatewayTimeout
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
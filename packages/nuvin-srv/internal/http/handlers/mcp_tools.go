@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPToolsHandler serves the consolidated tool catalog at GET /api/mcp/tools,
+// running the initialize + tools/list handshake against every running server
+// so the UI never speaks the protocol itself.
+type MCPToolsHandler struct {
+	Manager *mcp.Manager
+}
+
+func (h *MCPToolsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	writeJSON(w, http.StatusOK, h.Manager.ListTools(ctx))
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+)
+
+// MCPEventsWSHandler streams MCP process events over WebSocket at
+// GET /api/mcp/ws (all processes) or /api/mcp/ws/{id} (one process). Each
+// connection gets its own ring buffer, so several UI views can watch the same
+// process and a slow one only drops its own oldest events. The optional
+// ?buffer=N query sizes the ring.
+type MCPEventsWSHandler struct {
+	Manager  *mcp.Manager
+	Upgrader websocket.Upgrader
+}
+
+// NewMCPEventsWSHandler returns a handler with the local-bridge upgrader.
+func NewMCPEventsWSHandler(m *mcp.Manager) *MCPEventsWSHandler {
+	return &MCPEventsWSHandler{
+		Manager: m,
+		Upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			CheckOrigin:     func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+func (h *MCPEventsWSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	procID := strings.TrimPrefix(r.URL.Path, "/api/mcp/ws")
+	procID = strings.TrimPrefix(procID, "/")
+	if strings.Contains(procID, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	capacity, _ := strconv.Atoi(r.URL.Query().Get("buffer"))
+
+	conn, err := h.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := h.Manager.Subscribe(procID, capacity)
+	defer h.Manager.Unsubscribe(sub)
+
+	// Reader: its only job is noticing the client went away.
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				sub.Close()
+				return
+			}
+		}
+	}()
+
+	for {
+		ev, ok := sub.Next()
+		if !ok {
+			return
+		}
+		if err := conn.WriteJSON(ev); err != nil {
+			return
+		}
+	}
+}
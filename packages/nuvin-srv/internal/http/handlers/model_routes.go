@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+// ModelRoutesHandler reads and replaces the model routing table behind the
+// /v1 facade at GET/PUT /model-routes. Rules map a model name or prefix
+// pattern ("claude-*") to a proxy route; defaultRoute catches the rest.
+type ModelRoutesHandler struct {
+	Service *proxy.Service
+}
+
+func (h *ModelRoutesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		rules, def := h.Service.ModelRules()
+		writeJSON(w, http.StatusOK, map[string]any{"rules": rules, "defaultRoute": def})
+	case http.MethodPut:
+		var req struct {
+			Rules        []proxy.ModelRule `json:"rules"`
+			DefaultRoute string            `json:"defaultRoute"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if err := h.Service.SetModelRules(req.Rules, req.DefaultRoute); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		rules, def := h.Service.ModelRules()
+		writeJSON(w, http.StatusOK, map[string]any{"rules": rules, "defaultRoute": def})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
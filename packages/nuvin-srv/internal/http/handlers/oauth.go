@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/oauth"
+)
+
+// OAuthHandler exposes the provider-pluggable OAuth subsystem. GET/PUT
+// /oauth/providers manages configs; POST /oauth/{provider}/begin returns
+// the authorization URL, GET /oauth/{provider}/callback is the redirect
+// target completing the exchange, and DELETE /oauth/{provider} disconnects
+// the account. Tokens never leave the server.
+type OAuthHandler struct {
+	Manager *oauth.Manager
+}
+
+func (h *OAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/oauth"), "/")
+	switch {
+	case rest == "providers" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"providers": h.Manager.Providers()})
+	case rest == "providers" && r.Method == http.MethodPut:
+		h.register(w, r)
+	case rest == "providers":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	case strings.HasSuffix(rest, "/begin") && r.Method == http.MethodPost:
+		h.begin(w, strings.TrimSuffix(rest, "/begin"))
+	case strings.HasSuffix(rest, "/callback") && r.Method == http.MethodGet:
+		h.callback(w, r)
+	case rest != "" && !strings.Contains(rest, "/") && r.Method == http.MethodDelete:
+		h.Manager.Disconnect(rest)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *OAuthHandler) register(w http.ResponseWriter, r *http.Request) {
+	var p oauth.Provider
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := h.Manager.Register(p); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"providers": h.Manager.Providers()})
+}
+
+func (h *OAuthHandler) begin(w http.ResponseWriter, provider string) {
+	req, err := h.Manager.Begin(provider)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, req)
+}
+
+func (h *OAuthHandler) callback(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	code := r.URL.Query().Get("code")
+	if state == "" || code == "" {
+		writeError(w, http.StatusBadRequest, "state and code are required")
+		return
+	}
+	provider, err := h.Manager.Complete(r.Context(), state, code)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"provider": provider, "connected": true})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+// OpenAIV1Handler exposes an OpenAI-compatible surface under /v1/* so any
+// OpenAI SDK can use nuvin-srv as its base URL. The model name in the
+// request body picks the provider route (see Service.RouteForModel); the
+// request is then forwarded through the regular proxy path, so failover,
+// transforms, and usage accounting all apply.
+type OpenAIV1Handler struct {
+	Service *proxy.Service
+	Proxy   *ProxyHandler
+}
+
+func (h *OpenAIV1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	model, body, err := extractModel(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading request body: "+err.Error())
+		return
+	}
+	routeName, ok := h.Service.RouteForModel(model)
+	if !ok {
+		if model == "" {
+			writeError(w, http.StatusNotFound, "no default provider route configured for /v1")
+		} else {
+			writeError(w, http.StatusNotFound, "no provider route configured for model "+model)
+		}
+		return
+	}
+	if _, exists := h.Service.Route(routeName); !exists {
+		writeError(w, http.StatusBadGateway, "model routes to unknown proxy route "+routeName)
+		return
+	}
+
+	// Re-enter the proxy handler under the resolved route, keeping the /v1
+	// path so the upstream sees the standard OpenAI layout.
+	fwd := new(http.Request)
+	*fwd = *r
+	u := *r.URL
+	u.Path = "/proxy/" + routeName + r.URL.Path
+	fwd.URL = &u
+	if body != nil {
+		fwd.Body = io.NopCloser(bytes.NewReader(body))
+		fwd.ContentLength = int64(len(body))
+	}
+	h.Proxy.ServeHTTP(w, fwd)
+}
+
+// extractModel pulls the "model" field out of a JSON request body, returning
+// the buffered body so it can be replayed. Bodyless requests (GET /v1/models)
+// and non-JSON bodies yield an empty model.
+func extractModel(r *http.Request) (model string, body []byte, err error) {
+	if r.Body == nil || r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return "", nil, nil
+	}
+	body, err = io.ReadAll(r.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if !strings.Contains(r.Header.Get("Content-Type"), "json") && r.Header.Get("Content-Type") != "" {
+		return "", body, nil
+	}
+	var payload struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", body, nil
+	}
+	return payload.Model, body, nil
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+func newV1Handler(t *testing.T, svc *proxy.Service) *OpenAIV1Handler {
+	t.Helper()
+	return &OpenAIV1Handler{Service: svc, Proxy: NewProxyHandler(svc)}
+}
+
+func TestOpenAIV1RoutesByModel(t *testing.T) {
+	var gotPath, gotBody string
+	openai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+		w.Write([]byte(`{"id":"cmpl-1"}`))
+	}))
+	defer openai.Close()
+	claude := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"msg-1"}`))
+	}))
+	defer claude.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "openai", Upstream: openai.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetRoute(proxy.Route{Name: "claude", Upstream: claude.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetModelRules([]proxy.ModelRule{
+		{Model: "gpt-*", Route: "openai"},
+		{Model: "claude-*", Route: "claude"},
+	}, "openai"); err != nil {
+		t.Fatal(err)
+	}
+	h := newV1Handler(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions",
+		strings.NewReader(`{"model":"gpt-4o","messages":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if gotPath != "/v1/chat/completions" {
+		t.Errorf("upstream path = %q, want /v1/chat/completions", gotPath)
+	}
+	if !strings.Contains(gotBody, `"gpt-4o"`) {
+		t.Errorf("upstream body = %q, want the original request body", gotBody)
+	}
+
+	// A claude model lands on the claude route.
+	req = httptest.NewRequest(http.MethodPost, "/v1/chat/completions",
+		strings.NewReader(`{"model":"claude-3-5-sonnet"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil || out.ID != "msg-1" {
+		t.Errorf("claude response = %s, want id msg-1", rec.Body.String())
+	}
+}
+
+func TestOpenAIV1FallsBackToDefaultRoute(t *testing.T) {
+	var gotPath string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer upstream.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "openai", Upstream: upstream.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetModelRules(nil, "openai"); err != nil {
+		t.Fatal(err)
+	}
+	h := newV1Handler(t, svc)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if gotPath != "/v1/models" {
+		t.Errorf("upstream path = %q, want /v1/models", gotPath)
+	}
+}
+
+func TestOpenAIV1RejectsUnmappedModel(t *testing.T) {
+	svc := proxy.NewService()
+	h := newV1Handler(t, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions",
+		strings.NewReader(`{"model":"mystery"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/providers"
+)
+
+// ProvidersHandler manages the provider registry: GET /providers lists
+// entries (keys never included), PUT /providers/{name} upserts one with an
+// optional apiKey, DELETE removes it, and POST /providers/{name}/validate
+// pings the upstream with the stored credentials.
+type ProvidersHandler struct {
+	Registry *providers.Registry
+}
+
+func (h *ProvidersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/providers"), "/")
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"providers": h.Registry.List()})
+	case rest == "":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	case strings.HasSuffix(rest, "/validate") && r.Method == http.MethodPost:
+		h.validate(w, r, strings.TrimSuffix(rest, "/validate"))
+	case strings.Contains(rest, "/"):
+		writeError(w, http.StatusNotFound, "not found")
+	case r.Method == http.MethodGet:
+		p, ok := h.Registry.Get(rest)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown provider: "+rest)
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	case r.Method == http.MethodPut:
+		h.put(w, r, rest)
+	case r.Method == http.MethodDelete:
+		h.Registry.Remove(rest)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *ProvidersHandler) put(w http.ResponseWriter, r *http.Request, name string) {
+	var req struct {
+		providers.Provider
+		APIKey string `json:"apiKey"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	req.Provider.Name = name
+	if err := h.Registry.Set(req.Provider, req.APIKey); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	p, _ := h.Registry.Get(name)
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (h *ProvidersHandler) validate(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := h.Registry.Get(name); !ok {
+		writeError(w, http.StatusNotFound, "unknown provider: "+name)
+		return
+	}
+	if err := h.Registry.Validate(r.Context(), name); err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{"valid": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"valid": true})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/providers"
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+func TestProvidersHandlerCRUD(t *testing.T) {
+	h := &ProvidersHandler{Registry: providers.NewRegistry(secrets.NewStore(), nil)}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/providers/openai",
+		strings.NewReader(`{"kind":"openai","defaultModel":"gpt-4o","apiKey":"sk-secret"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("put status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if body := rec.Body.String(); strings.Contains(body, "sk-secret") {
+		t.Errorf("response leaked the api key: %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/providers", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"hasKey":true`) {
+		t.Fatalf("list = %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/providers/openai", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/providers/openai", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("get after delete = %d", rec.Code)
+	}
+}
+
+func TestProvidersHandlerRejectsUnknownKind(t *testing.T) {
+	h := &ProvidersHandler{Registry: providers.NewRegistry(secrets.NewStore(), nil)}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/providers/x",
+		strings.NewReader(`{"kind":"mystery"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d", rec.Code)
+	}
+}
+
+func TestProxyHandlerInjectsProviderCredentials(t *testing.T) {
+	var sawAuth string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawAuth = r.Header.Get("Authorization")
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	reg := providers.NewRegistry(secrets.NewStore(), nil)
+	if err := reg.Set(providers.Provider{Name: "openai", Kind: providers.KindOpenAI}, "sk-live"); err != nil {
+		t.Fatal(err)
+	}
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "openai", Upstream: upstream.URL, Provider: "openai"}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+	h.Providers = reg
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if sawAuth != "Bearer sk-live" {
+		t.Errorf("upstream saw Authorization %q", sawAuth)
+	}
+}
+
+func TestProxyHandlerProviderWithoutKey(t *testing.T) {
+	reg := providers.NewRegistry(secrets.NewStore(), nil)
+	reg.Set(providers.Provider{Name: "openai", Kind: providers.KindOpenAI}, "")
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "openai", Upstream: "http://127.0.0.1:9", Provider: "openai"}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+	h.Providers = reg
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil))
+	if rec.Code != http.StatusBadGateway || !strings.Contains(rec.Body.String(), "provider_auth") {
+		t.Fatalf("response = %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,576 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math/rand"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+	"github.com/marschhuynh/nuvin-srv/internal/http/middleware"
+	"github.com/marschhuynh/nuvin-srv/internal/outbound"
+	"github.com/marschhuynh/nuvin-srv/internal/providers"
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+	"github.com/marschhuynh/nuvin-srv/internal/translate"
+	"github.com/marschhuynh/nuvin-srv/internal/usage"
+)
+
+// ProxyHandler forwards requests under /proxy/{route}/... to the upstream
+// configured for that route.
+type ProxyHandler struct {
+	Service *proxy.Service
+	Client  *http.Client
+	// Usage, when set, receives an accounting record (model, token counts,
+	// latency, status) for every proxied request.
+	Usage *usage.Tracker
+	// Providers resolves Route.Provider references to auth headers injected
+	// into forwarded requests.
+	Providers *providers.Registry
+
+	// mirrorSample draws the [0,1) sample deciding whether a request is
+	// mirrored; tests pin it.
+	mirrorSample func() float64
+
+	mu           sync.Mutex
+	proxyClients map[string]*http.Client
+}
+
+// NewProxyHandler returns a ProxyHandler with a default upstream client.
+func NewProxyHandler(service *proxy.Service) *ProxyHandler {
+	return &ProxyHandler{
+		Service:      service,
+		Client:       &http.Client{Timeout: 120 * time.Second},
+		mirrorSample: rand.Float64,
+		proxyClients: make(map[string]*http.Client),
+	}
+}
+
+// clientFor returns the HTTP client for a route, building and caching one
+// per outbound proxy/TLS configuration so keep-alive pools are shared
+// across requests.
+func (h *ProxyHandler) clientFor(route proxy.Route) (*http.Client, error) {
+	if route.OutboundProxy == "" && route.TLS == nil {
+		return h.Client, nil
+	}
+	key := route.OutboundProxy + "\x00" + route.TLS.Key()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if c, ok := h.proxyClients[key]; ok {
+		return c, nil
+	}
+	base, ok := h.Client.Transport.(*http.Transport)
+	if !ok || base == nil {
+		base = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	t, err := outbound.Apply(base, route.OutboundProxy)
+	if err != nil {
+		return nil, err
+	}
+	if route.TLS != nil {
+		tlsCfg, err := route.TLS.Config()
+		if err != nil {
+			return nil, err
+		}
+		if t == base {
+			t = base.Clone()
+		}
+		t.TLSClientConfig = tlsCfg
+	}
+	c := &http.Client{Timeout: h.Client.Timeout, Transport: t}
+	h.proxyClients[key] = c
+	return c, nil
+}
+
+func (h *ProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/proxy/")
+	if rest == "" || rest == r.URL.Path {
+		writeError(w, http.StatusNotFound, "proxy route not specified")
+		return
+	}
+	route, tail, ok := h.Service.Match(rest)
+	if !ok {
+		writeError(w, http.StatusNotFound, "no proxy route matches: "+rest)
+		return
+	}
+	start := time.Now()
+
+	// Route-level response cache: GETs on cached routes are served from and
+	// stored into it, keyed by path+query with Vary awareness.
+	cacheResource := rest
+	if r.URL.RawQuery != "" {
+		cacheResource += "?" + r.URL.RawQuery
+	}
+	cacheable := r.Method == http.MethodGet && route.Cache != nil
+	if cacheable && !cacheBypass(r) {
+		if cached, ok := h.Service.CacheLookup(route.Name, cacheResource, r.Header); ok {
+			copyEndToEndHeaders(w.Header(), cached.Header)
+			w.Header().Set("X-Nuvin-Cache", "hit")
+			w.WriteHeader(cached.Status)
+			_, _ = w.Write(cached.Body)
+			h.record(r, route, cached.Status, start, usage.TokenCounts{})
+			return
+		}
+	}
+
+	tail = route.Rewrite.Apply(tail)
+	suffix := ""
+	if tail != "" {
+		suffix = "/" + tail
+	}
+	if r.URL.RawQuery != "" {
+		suffix += "?" + r.URL.RawQuery
+	}
+
+	client, err := h.clientFor(route)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "configuring outbound proxy: "+err.Error())
+		return
+	}
+
+	// Failover across backends and body transforms both need a replayable
+	// body; plain single-upstream routes keep streaming the request through.
+	var body io.Reader = r.Body
+	var bodyBytes []byte
+
+	// Routes for upstreams speaking another protocol translate OpenAI-format
+	// chat completions on the way in and convert responses back below.
+	var xlate *translate.Stream
+	translated := false
+	xlateModel := ""
+	// Azure OpenAI routes need the body's model to pick a deployment path.
+	azureAdapt := route.Provider != "" && h.Providers != nil &&
+		h.Providers.WantsAdaptation(route.Provider) && r.Method == http.MethodPost
+	if translate.Translates(route.Protocol) && r.Method == http.MethodPost && strings.HasSuffix(tail, "chat/completions") {
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "reading request body: "+err.Error())
+			return
+		}
+		out, path, stream, model, err := translate.Request(route.Protocol, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "translating request: "+err.Error())
+			return
+		}
+		bodyBytes, suffix, translated, xlateModel = out, "/"+path, true, model
+		if stream {
+			xlate = translate.NewStream(route.Protocol, model)
+		}
+	} else if h.Service.BackendCount(route.Name) > 1 || route.Transform.WantsRequest() || route.Mirror != nil || azureAdapt {
+		bodyBytes, err = io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "reading request body: "+err.Error())
+			return
+		}
+		bodyBytes = route.Transform.ApplyRequest(bodyBytes)
+	}
+
+	if azureAdapt && bodyBytes != nil {
+		newTail, err := h.Providers.AdaptRequest(route.Provider, tail, bodyBytes)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		suffix = "/" + newTail
+		if r.URL.RawQuery != "" {
+			suffix += "&" + r.URL.RawQuery
+		}
+	}
+
+	// Registered providers carry their own credentials; resolve them once so
+	// every failover attempt (and nothing client-visible) gets them.
+	var providerHeaders map[string]string
+	if route.Provider != "" && h.Providers != nil {
+		providerHeaders, err = h.Providers.AuthHeaders(r.Context(), route.Provider)
+		if err != nil {
+			writeErrorCode(w, http.StatusBadGateway, "provider credentials: "+err.Error(), "provider_auth")
+			return
+		}
+	}
+
+	if route.Mirror != nil && h.mirrorSample()*100 < mirrorPercent(route.Mirror) {
+		go h.mirror(route, r.Method, suffix, r.Header.Clone(), bodyBytes)
+	}
+
+	var resp *http.Response
+	var lastErr error
+	var circuitWait time.Duration
+	tried := make(map[string]bool)
+	for {
+		base, ok := h.Service.PickUpstream(route.Name, tried)
+		if !ok {
+			switch {
+			case lastErr != nil:
+				status, code, msg := classifyUpstreamError(lastErr)
+				writeErrorCode(w, status, msg, code)
+				h.record(r, route, status, start, usage.TokenCounts{})
+			case circuitWait > 0:
+				w.Header().Set("Retry-After", strconv.Itoa(int(circuitWait.Seconds())+1))
+				writeErrorCode(w, http.StatusServiceUnavailable, "circuit open for route "+route.Name, "circuit_open")
+				h.record(r, route, http.StatusServiceUnavailable, start, usage.TokenCounts{})
+			default:
+				writeError(w, http.StatusBadGateway, "no upstream available for route "+route.Name)
+				h.record(r, route, http.StatusBadGateway, start, usage.TokenCounts{})
+			}
+			return
+		}
+		tried[base] = true
+		if route.GRPC {
+			scheme := "http"
+			if strings.HasPrefix(base, "https:") {
+				scheme = "https"
+			}
+			client = h.grpcClientFor(scheme)
+		}
+		host := upstreamHost(base)
+		if allowed, retryAfter := h.Service.AllowHost(host); !allowed {
+			// Circuit open for this backend; try the next one.
+			if circuitWait == 0 || retryAfter < circuitWait {
+				circuitWait = retryAfter
+			}
+			continue
+		}
+		if bodyBytes != nil {
+			body = bytes.NewReader(bodyBytes)
+		}
+
+		upstreamReq, err := http.NewRequestWithContext(r.Context(), r.Method, strings.TrimSuffix(base, "/")+suffix, body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid upstream request: "+err.Error())
+			return
+		}
+		copyProxyHeaders(upstreamReq, r, route)
+		for k, v := range providerHeaders {
+			upstreamReq.Header.Set(k, v)
+		}
+
+		resp, err = client.Do(upstreamReq)
+		if err != nil {
+			h.Service.ReportUpstream(route.Name, base, false)
+			h.Service.ReportHost(host, false)
+			lastErr = err
+			if bodyBytes == nil {
+				// The body may be partially consumed; failing over would
+				// replay a truncated request.
+				status, code, msg := classifyUpstreamError(err)
+				writeErrorCode(w, status, msg, code)
+				h.record(r, route, status, start, usage.TokenCounts{})
+				return
+			}
+			continue
+		}
+		h.Service.ReportUpstream(route.Name, base, true)
+		h.Service.ReportHost(host, true)
+		break
+	}
+	defer resp.Body.Close()
+
+	if route.GRPC {
+		h.serveGRPC(w, r, resp, route, start)
+		return
+	}
+
+	if translated {
+		if xlate != nil && isStreamingUpstream(resp) && resp.StatusCode < 400 {
+			h.streamTranslated(w, r, resp, route, start, xlate)
+			return
+		}
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "reading upstream response: "+err.Error())
+			return
+		}
+		if resp.StatusCode >= 400 {
+			// Provider errors pass through untranslated so callers see the
+			// upstream's own message.
+			copyEndToEndHeaders(w.Header(), resp.Header)
+			w.WriteHeader(resp.StatusCode)
+			_, _ = w.Write(raw)
+			h.record(r, route, resp.StatusCode, start, usage.TokenCounts{Model: xlateModel})
+			return
+		}
+		out, err := translate.Response(route.Protocol, raw, xlateModel)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "translating response: "+err.Error())
+			return
+		}
+		h.record(r, route, resp.StatusCode, start, usage.ParseResponse(out, false))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(out)
+		return
+	}
+
+	if azureAdapt && resp.StatusCode >= 400 {
+		// Azure reports errors in its own JSON shape — including on
+		// streaming requests, which fail with a plain JSON body rather than
+		// an event stream. Normalize it to the OpenAI error format.
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "reading upstream response: "+err.Error())
+			return
+		}
+		out := providers.NormalizeAzureError(raw)
+		copyEndToEndHeaders(w.Header(), resp.Header)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Del("Content-Length")
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(out)
+		h.record(r, route, resp.StatusCode, start, usage.TokenCounts{})
+		return
+	}
+
+	if isStreamingUpstream(resp) {
+		tap := &usageTap{}
+		streamThrough(w, resp, tap)
+		h.record(r, route, resp.StatusCode, start, usage.ParseResponse(tap.Bytes(), true))
+		return
+	}
+
+	if route.Transform.WantsResponse() && isJSONContent(resp.Header.Get("Content-Type")) {
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "reading upstream response: "+err.Error())
+			return
+		}
+		// Account before stripping, in case the transform removes usage.
+		h.record(r, route, resp.StatusCode, start, usage.ParseResponse(raw, false))
+		raw = route.Transform.ApplyResponse(raw)
+		copyEndToEndHeaders(w.Header(), resp.Header)
+		w.Header().Del("Content-Length")
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(raw)
+		return
+	}
+
+	if cacheable && resp.StatusCode == http.StatusOK {
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "reading upstream response: "+err.Error())
+			return
+		}
+		h.Service.CacheStore(route.Name, cacheResource, r.Header,
+			proxy.CachedResponse{Status: resp.StatusCode, Header: resp.Header.Clone(), Body: raw})
+		copyEndToEndHeaders(w.Header(), resp.Header)
+		w.Header().Set("X-Nuvin-Cache", "miss")
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(raw)
+		h.record(r, route, resp.StatusCode, start, usage.ParseResponse(raw, false))
+		return
+	}
+
+	copyEndToEndHeaders(w.Header(), resp.Header)
+	w.WriteHeader(resp.StatusCode)
+	tap := &usageTap{}
+	_, _ = io.Copy(w, io.TeeReader(resp.Body, tap))
+	h.record(r, route, resp.StatusCode, start, usage.ParseResponse(tap.Bytes(), false))
+}
+
+// cacheBypass reports whether the client explicitly asked to skip the
+// route cache for this request (the response is still stored).
+func cacheBypass(r *http.Request) bool {
+	if strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "no-cache") {
+		return true
+	}
+	return strings.EqualFold(r.Header.Get("X-Nuvin-Cache"), "bypass")
+}
+
+// mirrorPercent applies the Mirror default: an unset percentage mirrors
+// everything.
+func mirrorPercent(m *proxy.Mirror) float64 {
+	if m.Percent <= 0 {
+		return 100
+	}
+	return m.Percent
+}
+
+// mirror sends one shadow copy of a request to the route's mirror target,
+// discarding the response. It runs detached from the client request so a
+// slow mirror never delays the real answer.
+func (h *ProxyHandler) mirror(route proxy.Route, method, suffix string, header http.Header, body []byte) {
+	req, err := http.NewRequest(method, strings.TrimSuffix(route.Mirror.Upstream, "/")+suffix, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	hop := fetchproxy.HopByHop(header)
+	for k, vs := range header {
+		if hop[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("X-Nuvin-Mirror", "1")
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// streamTranslated relays a provider's SSE stream to the client as OpenAI
+// chat completion chunks, flushing each one and closing with [DONE].
+func (h *ProxyHandler) streamTranslated(w http.ResponseWriter, r *http.Request, resp *http.Response, route proxy.Route, start time.Time, xlate *translate.Stream) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(resp.StatusCode)
+	flusher, _ := w.(http.Flusher)
+
+	br := bufio.NewReader(resp.Body)
+	for {
+		line, err := br.ReadBytes('\n')
+		if data, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:")); ok {
+			for _, chunk := range xlate.Chunk(bytes.TrimSpace(data)) {
+				if _, werr := w.Write(append(append([]byte("data: "), chunk...), '\n', '\n')); werr != nil {
+					return
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	model, promptTokens, completionTokens := xlate.Usage()
+	h.record(r, route, resp.StatusCode, start, usage.TokenCounts{Model: model, Prompt: promptTokens, Completion: completionTokens})
+}
+
+// record feeds the usage tracker when one is attached.
+func (h *ProxyHandler) record(r *http.Request, route proxy.Route, status int, start time.Time, counts usage.TokenCounts) {
+	if h.Usage == nil {
+		return
+	}
+	h.Usage.Record(usage.Record{
+		Subject:          middleware.Subject(r),
+		Route:            route.Name,
+		Model:            counts.Model,
+		PromptTokens:     counts.Prompt,
+		CompletionTokens: counts.Completion,
+		Status:           status,
+		DurationMs:       time.Since(start).Milliseconds(),
+	})
+}
+
+// usageTap buffers the head and tail of a response body for token
+// accounting without holding the whole stream: the model arrives in the
+// first chunk, token usage in the last.
+type usageTap struct {
+	head []byte
+	tail []byte
+}
+
+const usageTapCap = 64 * 1024
+
+func (t *usageTap) Write(p []byte) (int, error) {
+	if len(t.head) < usageTapCap {
+		n := usageTapCap - len(t.head)
+		if n > len(p) {
+			n = len(p)
+		}
+		t.head = append(t.head, p[:n]...)
+	}
+	t.tail = append(t.tail, p...)
+	if len(t.tail) > usageTapCap {
+		t.tail = t.tail[len(t.tail)-usageTapCap:]
+	}
+	return len(p), nil
+}
+
+func (t *usageTap) Bytes() []byte {
+	if len(t.head) < usageTapCap {
+		// Everything fit in the head; the tail would only duplicate it.
+		return t.head
+	}
+	return append(append([]byte(nil), t.head...), append([]byte("\n"), t.tail...)...)
+}
+
+// upstreamHost extracts the host the circuit breaker tracks from an
+// upstream base URL; an unparseable URL falls back to the URL itself.
+func upstreamHost(base string) string {
+	u, err := url.Parse(base)
+	if err != nil || u.Hostname() == "" {
+		return base
+	}
+	return u.Hostname()
+}
+
+// isJSONContent reports whether a Content-Type carries a JSON body.
+func isJSONContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// copyEndToEndHeaders copies src into dst minus hop-by-hop headers
+// (RFC 7230 §6.1), including any nominated by src's Connection header.
+func copyEndToEndHeaders(dst, src http.Header) {
+	hop := fetchproxy.HopByHop(src)
+	for k, vs := range src {
+		if hop[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		for _, v := range vs {
+			dst.Add(k, v)
+		}
+	}
+}
+
+// copyProxyHeaders applies the route's header rules to the upstream request:
+// hop-by-hop and stripped inbound headers are dropped, route headers
+// override, and an optional host rewrite replaces the upstream URL's host.
+func copyProxyHeaders(upstreamReq, r *http.Request, route proxy.Route) {
+	stripped := make(map[string]bool, len(route.StripHeaders))
+	for _, k := range route.StripHeaders {
+		stripped[http.CanonicalHeaderKey(k)] = true
+	}
+	hop := fetchproxy.HopByHop(r.Header)
+	for k, vs := range r.Header {
+		if stripped[http.CanonicalHeaderKey(k)] || hop[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		for _, v := range vs {
+			upstreamReq.Header.Add(k, v)
+		}
+	}
+	for k, v := range route.Headers {
+		upstreamReq.Header.Set(k, v)
+	}
+	upstreamReq.Host = ""
+	if route.Rewrite != nil && route.Rewrite.Host != "" {
+		upstreamReq.Host = route.Rewrite.Host
+	}
+}
+
+// isStreamingUpstream reports whether the proxied response must be flushed
+// chunk by chunk: SSE/NDJSON media types, or a chunked body with no declared
+// length, where buffering would hold tokens back until the stream ends.
+func isStreamingUpstream(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err == nil && streamingContentTypes[mediaType] {
+		return true
+	}
+	for _, te := range resp.TransferEncoding {
+		if te == "chunked" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/providers"
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+func newAzureProxy(t *testing.T, upstreamURL string) *ProxyHandler {
+	t.Helper()
+	reg := providers.NewRegistry(secrets.NewStore(), nil)
+	err := reg.Set(providers.Provider{
+		Name: "azure", Kind: providers.KindAzureOpenAI,
+		Azure: &providers.AzureConfig{
+			APIVersion:  "2024-06-01",
+			Deployments: map[string]string{"gpt-4o": "gpt4o-prod"},
+		},
+	}, "az-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "azure", Upstream: upstreamURL, Provider: "azure"}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+	h.Providers = reg
+	return h
+}
+
+func TestProxyHandlerAzureDeploymentMapping(t *testing.T) {
+	var sawPath, sawKey string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawPath = r.URL.Path + "?" + r.URL.RawQuery
+		sawKey = r.Header.Get("Api-Key")
+		w.Write([]byte(`{"id":"cmpl"}`))
+	}))
+	defer upstream.Close()
+
+	h := newAzureProxy(t, upstream.URL)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/proxy/azure/v1/chat/completions",
+		strings.NewReader(`{"model":"gpt-4o","messages":[]}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if sawPath != "/openai/deployments/gpt4o-prod/chat/completions?api-version=2024-06-01" {
+		t.Errorf("upstream path = %q", sawPath)
+	}
+	if sawKey != "az-key" {
+		t.Errorf("Api-Key = %q", sawKey)
+	}
+}
+
+func TestProxyHandlerAzureErrorNormalized(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"DeploymentNotFound","message":"no such deployment","status":404}}`))
+	}))
+	defer upstream.Close()
+
+	h := newAzureProxy(t, upstream.URL)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/proxy/azure/v1/chat/completions",
+		strings.NewReader(`{"model":"gpt-4o","stream":true}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"type":"azure_openai_error"`) {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+}
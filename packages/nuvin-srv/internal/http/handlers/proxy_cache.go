@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+// ProxyCacheHandler inspects and purges the per-route response caches:
+// GET /proxy-cache reports entry counts per route, DELETE /proxy-cache
+// purges every route, DELETE /proxy-cache/{name} purges one.
+type ProxyCacheHandler struct {
+	Service *proxy.Service
+}
+
+func (h *ProxyCacheHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/proxy-cache"), "/")
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"routes": h.Service.CacheStats()})
+	case http.MethodDelete:
+		h.Service.PurgeCache(name)
+		writeJSON(w, http.StatusOK, map[string]any{"purged": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
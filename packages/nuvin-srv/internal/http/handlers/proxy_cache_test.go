@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+func cachedRouteService(t *testing.T, upstream string) *proxy.Service {
+	t.Helper()
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{
+		Name:     "openai",
+		Upstream: upstream,
+		Cache:    &proxy.CacheConfig{TTLSeconds: 60},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func TestProxyHandlerServesCachedGETs(t *testing.T) {
+	hits := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"id":"gpt-4o"}]}`))
+	}))
+	defer upstream.Close()
+
+	h := NewProxyHandler(cachedRouteService(t, upstream.URL))
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d", i+1, rec.Code)
+		}
+		want := "miss"
+		if i > 0 {
+			want = "hit"
+		}
+		if got := rec.Header().Get("X-Nuvin-Cache"); got != want {
+			t.Errorf("request %d: X-Nuvin-Cache = %q, want %q", i+1, got, want)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("upstream hit %d times, want 1", hits)
+	}
+}
+
+func TestProxyHandlerCacheBypassAndPurge(t *testing.T) {
+	hits := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer upstream.Close()
+
+	svc := cachedRouteService(t, upstream.URL)
+	h := NewProxyHandler(svc)
+
+	warm := func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil))
+	}
+	warm()
+
+	// Cache-Control: no-cache skips the cached copy.
+	req := httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil)
+	req.Header.Set("Cache-Control", "no-cache")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if hits != 2 {
+		t.Fatalf("upstream hit %d times after bypass, want 2", hits)
+	}
+
+	// Purging drops the entry so the next plain GET goes upstream again.
+	purge := &ProxyCacheHandler{Service: svc}
+	rec := httptest.NewRecorder()
+	purge.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/proxy-cache/openai", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("purge status = %d", rec.Code)
+	}
+	warm()
+	if hits != 3 {
+		t.Errorf("upstream hit %d times after purge, want 3", hits)
+	}
+}
+
+func TestProxyHandlerCacheHonorsVary(t *testing.T) {
+	hits := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Vary", "X-Org")
+		w.Write([]byte(`{"org":"` + r.Header.Get("X-Org") + `"}`))
+	}))
+	defer upstream.Close()
+
+	h := NewProxyHandler(cachedRouteService(t, upstream.URL))
+	get := func(org string) string {
+		req := httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil)
+		req.Header.Set("X-Org", org)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	if got := get("a"); got != `{"org":"a"}` {
+		t.Fatalf("first response = %q", got)
+	}
+	// Different X-Org must not reuse org a's variant.
+	if got := get("b"); got != `{"org":"b"}` {
+		t.Errorf("variant response = %q, want org b", got)
+	}
+	// Same X-Org is served from cache.
+	if got := get("b"); got != `{"org":"b"}` {
+		t.Errorf("cached variant = %q, want org b", got)
+	}
+	if hits != 2 {
+		t.Errorf("upstream hit %d times, want 2", hits)
+	}
+}
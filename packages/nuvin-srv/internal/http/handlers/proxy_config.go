@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+// ProxyConfigHandler manages the proxy route table: GET /proxy-config lists
+// routes, POST adds or replaces one, and GET/PUT/DELETE /proxy-config/{name}
+// read, update, or remove a single route. Route names may span path segments
+// ("v1/chat/completions").
+type ProxyConfigHandler struct {
+	Service *proxy.Service
+}
+
+func (h *ProxyConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/proxy-config"), "/")
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"routes": h.Service.Routes()})
+		case http.MethodPost:
+			h.setRoute(w, r, "")
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		route, ok := h.Service.Route(name)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown proxy route: "+name)
+			return
+		}
+		writeJSON(w, http.StatusOK, route)
+	case http.MethodPut:
+		h.setRoute(w, r, name)
+	case http.MethodDelete:
+		if _, ok := h.Service.Route(name); !ok {
+			writeError(w, http.StatusNotFound, "unknown proxy route: "+name)
+			return
+		}
+		h.Service.RemoveRoute(name)
+		writeJSON(w, http.StatusOK, map[string]any{"deleted": name})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *ProxyConfigHandler) setRoute(w http.ResponseWriter, r *http.Request, name string) {
+	var route proxy.Route
+	if err := json.NewDecoder(r.Body).Decode(&route); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if name != "" {
+		if route.Name != "" && route.Name != name {
+			writeError(w, http.StatusBadRequest, "route name in body does not match path")
+			return
+		}
+		route.Name = name
+	}
+	if err := h.Service.SetRoute(route); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	saved, _ := h.Service.Route(route.Name)
+	writeJSON(w, http.StatusOK, saved)
+}
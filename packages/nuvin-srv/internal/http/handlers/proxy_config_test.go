@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+func TestProxyConfigCRUD(t *testing.T) {
+	h := &ProxyConfigHandler{Service: proxy.NewService()}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/proxy-config",
+		strings.NewReader(`{"name":"openai","upstream":"https://api.openai.com"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create: status %d (%s)", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/proxy-config/openai",
+		strings.NewReader(`{"upstream":"https://other.example.com"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status %d (%s)", rec.Code, rec.Body.String())
+	}
+	route, ok := h.Service.Route("openai")
+	if !ok || !strings.Contains(route.Upstream, "other.example.com") {
+		t.Fatalf("route after PUT = %+v", route)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/proxy-config/openai", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete: status %d (%s)", rec.Code, rec.Body.String())
+	}
+	if _, ok := h.Service.Route("openai"); ok {
+		t.Fatal("route survived DELETE")
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/proxy-config/openai", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("delete missing: status %d", rec.Code)
+	}
+}
+
+func TestProxyConfigPersistsAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+
+	svc := proxy.NewService()
+	if err := svc.SetConfigPath(path); err != nil {
+		t.Fatalf("SetConfigPath: %v", err)
+	}
+	if err := svc.SetRoute(proxy.Route{Name: "openai", Upstream: "https://api.openai.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := proxy.NewService()
+	if err := reloaded.SetConfigPath(path); err != nil {
+		t.Fatalf("SetConfigPath after restart: %v", err)
+	}
+	if _, ok := reloaded.Route("openai"); !ok {
+		t.Fatal("route not loaded from config file")
+	}
+
+	reloaded.RemoveRoute("openai")
+	again := proxy.NewService()
+	if err := again.SetConfigPath(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := again.Route("openai"); ok {
+		t.Fatal("removed route came back after reload")
+	}
+}
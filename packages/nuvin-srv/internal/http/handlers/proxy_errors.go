@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// Reason codes for upstream failures, returned in the error response `code`
+// field so callers can distinguish failure classes programmatically.
+const (
+	ReasonDNS               = "dns_error"
+	ReasonConnectionRefused = "connection_refused"
+	ReasonTLS               = "tls_error"
+	ReasonTimeout           = "timeout"
+	ReasonUpstream          = "upstream_error"
+)
+
+// classifyUpstreamError maps a client.Do error to an HTTP status, a stable
+// reason code, and a human-readable message. Timeouts get 504; DNS, connect,
+// and TLS failures get 502.
+func classifyUpstreamError(err error) (status int, code string, message string) {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return http.StatusGatewayTimeout, ReasonTimeout, "DNS lookup timed out: " + dnsErr.Error()
+		}
+		return http.StatusBadGateway, ReasonDNS, "DNS lookup failed: " + dnsErr.Error()
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return http.StatusBadGateway, ReasonConnectionRefused, "upstream refused the connection: " + err.Error()
+	}
+
+	var recordErr tls.RecordHeaderError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &recordErr) || errors.As(err, &certErr) ||
+		errors.As(err, &unknownAuthErr) || errors.As(err, &hostnameErr) {
+		return http.StatusBadGateway, ReasonTLS, "TLS handshake failed: " + err.Error()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, ReasonTimeout, "upstream request timed out: " + err.Error()
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return http.StatusGatewayTimeout, ReasonTimeout, "upstream request timed out: " + err.Error()
+	}
+
+	return http.StatusBadGateway, ReasonUpstream, "proxy request failed: " + err.Error()
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+	"github.com/marschhuynh/nuvin-srv/internal/usage"
+)
+
+// grpcClientFor returns the HTTP/2 client used for gRPC routes: cleartext
+// upstreams get a forced-h2c transport (gRPC never runs over HTTP/1.1),
+// https upstreams negotiate h2 via ALPN. Clients are shared and have no
+// timeout because gRPC streams are long-lived.
+func (h *ProxyHandler) grpcClientFor(scheme string) *http.Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := "\x00grpc:" + scheme
+	if c, ok := h.proxyClients[key]; ok {
+		return c
+	}
+	t := &http2.Transport{}
+	if scheme != "https" {
+		t.AllowHTTP = true
+		t.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	}
+	c := &http.Client{Transport: t}
+	h.proxyClients[key] = c
+	return c
+}
+
+// isGRPCContent reports whether a Content-Type is native gRPC framing
+// (application/grpc or a +proto/+json subtype, but not grpc-web).
+func isGRPCContent(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/grpc") &&
+		!strings.HasPrefix(contentType, "application/grpc-web")
+}
+
+// grpcHTTPStatus maps a gRPC status code to the closest HTTP status.
+func grpcHTTPStatus(code int) int {
+	switch code {
+	case 3, 9, 11: // InvalidArgument, FailedPrecondition, OutOfRange
+		return http.StatusBadRequest
+	case 4: // DeadlineExceeded
+		return http.StatusGatewayTimeout
+	case 5: // NotFound
+		return http.StatusNotFound
+	case 6, 10: // AlreadyExists, Aborted
+		return http.StatusConflict
+	case 7: // PermissionDenied
+		return http.StatusForbidden
+	case 8: // ResourceExhausted
+		return http.StatusTooManyRequests
+	case 12: // Unimplemented
+		return http.StatusNotImplemented
+	case 14: // Unavailable
+		return http.StatusServiceUnavailable
+	case 16: // Unauthenticated
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// serveGRPC relays a gRPC upstream response. Native gRPC clients get the
+// response as-is — status 200, flushed frames, and the grpc-status trailer
+// relayed via the trailer mechanism. For everyone else (grpc-web, plain
+// HTTP callers) a trailers-only error response is translated into a real
+// HTTP status with the decoded grpc-message, which is far more debuggable
+// than a 200 with an invisible trailer.
+func (h *ProxyHandler) serveGRPC(w http.ResponseWriter, r *http.Request, resp *http.Response, route proxy.Route, start time.Time) {
+	if code, err := strconv.Atoi(resp.Header.Get("Grpc-Status")); err == nil && code != 0 &&
+		!isGRPCContent(r.Header.Get("Content-Type")) {
+		msg, _ := url.PathUnescape(resp.Header.Get("Grpc-Message"))
+		if msg == "" {
+			msg = "upstream returned grpc-status " + strconv.Itoa(code)
+		}
+		status := grpcHTTPStatus(code)
+		writeErrorCode(w, status, msg, "grpc_error")
+		h.record(r, route, status, start, usage.TokenCounts{})
+		return
+	}
+
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	// resp.Trailer is populated once the body is consumed; TrailerPrefix
+	// keys written now go out as HTTP/2 trailers.
+	for k, vs := range resp.Trailer {
+		for _, v := range vs {
+			w.Header().Add(http.TrailerPrefix+k, v)
+		}
+	}
+	status := resp.StatusCode
+	if code, err := strconv.Atoi(resp.Trailer.Get("Grpc-Status")); err == nil && code != 0 {
+		// The HTTP status is already on the wire; record the mapped one so
+		// usage stats count gRPC errors as errors.
+		status = grpcHTTPStatus(code)
+	}
+	h.record(r, route, status, start, usage.TokenCounts{})
+}
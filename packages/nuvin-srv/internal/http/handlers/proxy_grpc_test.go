@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+func TestGRPCHTTPStatusMapping(t *testing.T) {
+	cases := map[int]int{
+		3:  http.StatusBadRequest,
+		4:  http.StatusGatewayTimeout,
+		5:  http.StatusNotFound,
+		7:  http.StatusForbidden,
+		8:  http.StatusTooManyRequests,
+		12: http.StatusNotImplemented,
+		13: http.StatusInternalServerError,
+		14: http.StatusServiceUnavailable,
+		16: http.StatusUnauthorized,
+	}
+	for code, want := range cases {
+		if got := grpcHTTPStatus(code); got != want {
+			t.Errorf("grpcHTTPStatus(%d) = %d, want %d", code, got, want)
+		}
+	}
+}
+
+func TestServeGRPCTranslatesTrailersOnlyError(t *testing.T) {
+	h := NewProxyHandler(proxy.NewService())
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Grpc-Status":  {"5"},
+			"Grpc-Message": {"model%20not%20found"},
+		},
+		Body: io.NopCloser(strings.NewReader("")),
+	}
+	// A grpc-web caller should see a real HTTP error, not 200 + trailer.
+	req := httptest.NewRequest(http.MethodPost, "/proxy/infer/pkg.Svc/Predict", nil)
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	rec := httptest.NewRecorder()
+	h.serveGRPC(rec, req, resp, proxy.Route{Name: "infer"}, time.Now())
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "model not found") {
+		t.Errorf("body = %q, want decoded grpc-message", rec.Body.String())
+	}
+}
+
+func TestServeGRPCRelaysNativeResponses(t *testing.T) {
+	h := NewProxyHandler(proxy.NewService())
+	trailer := http.Header{"Grpc-Status": {"0"}}
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/grpc"}},
+		Body:       io.NopCloser(strings.NewReader("\x00\x00\x00\x00\x05hello")),
+		Trailer:    trailer,
+	}
+	// Native gRPC callers must get 200 and the frames untouched even for
+	// errors; trailers carry the status.
+	req := httptest.NewRequest(http.MethodPost, "/proxy/infer/pkg.Svc/Predict", nil)
+	req.Header.Set("Content-Type", "application/grpc")
+	rec := httptest.NewRecorder()
+	h.serveGRPC(rec, req, resp, proxy.Route{Name: "infer"}, time.Now())
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/grpc" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.HasSuffix(rec.Body.String(), "hello") {
+		t.Errorf("body = %q, want relayed frame", rec.Body.String())
+	}
+	if got := rec.Header().Get(http.TrailerPrefix + "Grpc-Status"); got != "0" {
+		t.Errorf("trailer Grpc-Status = %q, want 0", got)
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+func TestProxyHandlerMirrorsTraffic(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"from":"primary"}`))
+	}))
+	defer primary.Close()
+
+	type mirrored struct {
+		path, body, flag string
+	}
+	got := make(chan mirrored, 1)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- mirrored{r.URL.Path, string(body), r.Header.Get("X-Nuvin-Mirror")}
+		w.WriteHeader(http.StatusInternalServerError) // mirror failures must not surface
+	}))
+	defer shadow.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{
+		Name:     "openai",
+		Upstream: primary.URL,
+		Mirror:   &proxy.Mirror{Upstream: shadow.URL},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+	h.mirrorSample = func() float64 { return 0.99 } // still below the 100% default
+
+	req := httptest.NewRequest(http.MethodPost, "/proxy/openai/v1/chat/completions",
+		strings.NewReader(`{"model":"gpt-4o"}`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"from":"primary"}` {
+		t.Fatalf("primary response = %d %q", rec.Code, rec.Body.String())
+	}
+	m := <-got
+	if m.path != "/v1/chat/completions" || m.body != `{"model":"gpt-4o"}` || m.flag != "1" {
+		t.Errorf("mirrored request = %+v", m)
+	}
+}
+
+func TestProxyHandlerMirrorRespectsPercent(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer primary.Close()
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request mirrored despite sample above the percentage")
+	}))
+	defer shadow.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{
+		Name:     "openai",
+		Upstream: primary.URL,
+		Mirror:   &proxy.Mirror{Upstream: shadow.URL, Percent: 10},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+	h.mirrorSample = func() float64 { return 0.5 } // 50th percentile, above 10%
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+}
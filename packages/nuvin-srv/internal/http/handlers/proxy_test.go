@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+func proxyRequest(t *testing.T, h *ProxyHandler, path string) (*httptest.ResponseRecorder, errorResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var body errorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v (%s)", err, rec.Body.String())
+	}
+	return rec, body
+}
+
+func TestProxyHandlerClassifiesDNSFailure(t *testing.T) {
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "bad", Upstream: "http://host.invalid"}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	rec, body := proxyRequest(t, h, "/proxy/bad/v1")
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+	if body.Code != ReasonDNS {
+		t.Errorf("code = %q, want %q (error: %s)", body.Code, ReasonDNS, body.Error)
+	}
+}
+
+func TestProxyHandlerClassifiesConnectionRefused(t *testing.T) {
+	// Grab a port that is free, then close the listener so dialing it is
+	// refused.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "down", Upstream: "http://" + addr}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	rec, body := proxyRequest(t, h, "/proxy/down/v1")
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+	if body.Code != ReasonConnectionRefused {
+		t.Errorf("code = %q, want %q (error: %s)", body.Code, ReasonConnectionRefused, body.Error)
+	}
+}
+
+func TestProxyHandlerClassifiesTimeout(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "slow", Upstream: slow.URL}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+	h.Client = &http.Client{Timeout: 50 * time.Millisecond}
+
+	rec, body := proxyRequest(t, h, "/proxy/slow/v1")
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want 504", rec.Code)
+	}
+	if body.Code != ReasonTimeout {
+		t.Errorf("code = %q, want %q (error: %s)", body.Code, ReasonTimeout, body.Error)
+	}
+}
+
+func TestProxyHandlerPrefixAndWildcardRoutes(t *testing.T) {
+	var gotPath string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	svc := proxy.NewService()
+	for _, name := range []string{"v1/chat/completions", "v1/*", "*"} {
+		if err := svc.SetRoute(proxy.Route{Name: name, Upstream: upstream.URL}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewProxyHandler(svc)
+
+	// Exact multi-segment match wins and forwards no tail.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/v1/chat/completions", nil))
+	if rec.Code != http.StatusOK || gotPath != "/" {
+		t.Fatalf("exact: status %d, upstream path %q", rec.Code, gotPath)
+	}
+
+	// The v1/* wildcard beats the catch-all and strips its prefix.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/v1/models", nil))
+	if rec.Code != http.StatusOK || gotPath != "/models" {
+		t.Fatalf("wildcard: status %d, upstream path %q", rec.Code, gotPath)
+	}
+
+	// Everything else falls through to the catch-all with the full tail.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/v2/other", nil))
+	if rec.Code != http.StatusOK || gotPath != "/v2/other" {
+		t.Fatalf("catch-all: status %d, upstream path %q", rec.Code, gotPath)
+	}
+
+	if err := svc.SetRoute(proxy.Route{Name: "bad/*/route", Upstream: upstream.URL}); err == nil {
+		t.Fatal("mid-path wildcard accepted")
+	}
+}
+
+func TestProxyHandlerFailsOverToHealthyBackend(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := "http://" + l.Addr().String()
+	l.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer good.Close()
+
+	svc := proxy.NewService()
+	err = svc.SetRoute(proxy.Route{
+		Name:     "llm",
+		Strategy: proxy.StrategyPriority,
+		Upstreams: []proxy.Upstream{
+			{URL: dead, Priority: 0},
+			{URL: good.URL, Priority: 1},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/llm/v1/models", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 after failover (%s)", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProxyHandlerRoundRobinsBackends(t *testing.T) {
+	hits := make([]int, 2)
+	var upstreams []proxy.Upstream
+	for i := range hits {
+		i := i
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[i]++
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer srv.Close()
+		upstreams = append(upstreams, proxy.Upstream{URL: srv.URL})
+	}
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "llm", Upstreams: upstreams}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	for i := 0; i < 4; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/llm/v1", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, rec.Code)
+		}
+	}
+	if hits[0] != 2 || hits[1] != 2 {
+		t.Fatalf("hits = %v, want an even 2/2 split", hits)
+	}
+}
+
+func TestProxyHandlerHeaderAndPathRewrites(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("upstream path = %q, want /v1/chat/completions", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer route-key" {
+			t.Errorf("Authorization = %q, want the route key", got)
+		}
+		if got := r.Header.Get("Cookie"); got != "" {
+			t.Errorf("Cookie forwarded: %q", got)
+		}
+		if r.Host != "api.example.com" {
+			t.Errorf("Host = %q, want api.example.com", r.Host)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	svc := proxy.NewService()
+	err := svc.SetRoute(proxy.Route{
+		Name:         "chat/*",
+		Upstream:     upstream.URL,
+		Headers:      map[string]string{"Authorization": "Bearer route-key"},
+		StripHeaders: []string{"cookie", "authorization"},
+		Rewrite:      &proxy.Rewrite{Host: "api.example.com", AddPrefix: "v1/chat"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/proxy/chat/completions", nil)
+	req.Header.Set("Authorization", "Bearer browser-token")
+	req.Header.Set("Cookie", "session=secret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProxyHandlerTransformsBodies(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("upstream body: %v", err)
+		}
+		if body["model"] != "gpt-4o-mini" {
+			t.Errorf("model = %v, want mapped gpt-4o-mini", body["model"])
+		}
+		if body["temperature"] != 0.2 {
+			t.Errorf("temperature = %v, want injected default 0.2", body["temperature"])
+		}
+		if body["max_tokens"] != float64(100) || body["maxTokens"] != nil {
+			t.Errorf("maxTokens not renamed: %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[],"usage":{"total_tokens":9},"system_fingerprint":"fp"}`))
+	}))
+	defer upstream.Close()
+
+	svc := proxy.NewService()
+	err := svc.SetRoute(proxy.Route{
+		Name:     "llm",
+		Upstream: upstream.URL,
+		Transform: &proxy.Transform{
+			RenameFields:        map[string]string{"maxTokens": "max_tokens"},
+			RequestDefaults:     map[string]any{"temperature": 0.2},
+			ModelMap:            map[string]string{"default": "gpt-4o-mini"},
+			StripResponseFields: []string{"system_fingerprint"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/proxy/llm/v1/chat/completions",
+		strings.NewReader(`{"model":"default","maxTokens":100}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", rec.Code, rec.Body.String())
+	}
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := out["system_fingerprint"]; ok {
+		t.Errorf("stripped field survived: %v", out)
+	}
+	if _, ok := out["usage"]; !ok {
+		t.Errorf("unlisted field removed: %v", out)
+	}
+}
+
+func TestProxyHandlerStreamsSSE(t *testing.T) {
+	proceed := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		_, _ = w.Write([]byte("data: a\n\n"))
+		flusher.Flush()
+		// Only continue once the client has seen the first event; if the
+		// proxy buffered, the read below would deadlock instead.
+		<-proceed
+		_, _ = w.Write([]byte("data: b\n\n"))
+	}))
+	defer upstream.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "llm", Upstream: upstream.URL}); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewProxyHandler(svc))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/proxy/llm/v1/chat/completions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "data: a\n" {
+		t.Fatalf("first event = %q, %v", line, err)
+	}
+	close(proceed)
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatalf("reading rest of stream: %v", err)
+	}
+	line, err = reader.ReadString('\n')
+	if err != nil || line != "data: b\n" {
+		t.Fatalf("second event = %q, %v", line, err)
+	}
+}
+
+func TestProxyHandlerForwardsToUpstream(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("upstream path = %q, want /v1/models", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer route-key" {
+			t.Errorf("route header not injected, got %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer upstream.Close()
+
+	svc := proxy.NewService()
+	err := svc.SetRoute(proxy.Route{
+		Name:     "openai",
+		Upstream: upstream.URL,
+		Headers:  map[string]string{"Authorization": "Bearer route-key"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body.String())
+	}
+}
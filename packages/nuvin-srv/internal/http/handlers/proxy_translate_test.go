@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+)
+
+func TestProxyHandlerTranslatesAnthropicRoute(t *testing.T) {
+	var gotPath, gotBody string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"msg_1","model":"claude-3-5-sonnet","content":[{"type":"text","text":"hello"}],"stop_reason":"end_turn","usage":{"input_tokens":3,"output_tokens":2}}`))
+	}))
+	defer upstream.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "claude", Upstream: upstream.URL, Protocol: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/proxy/claude/v1/chat/completions",
+		strings.NewReader(`{"model":"claude-3-5-sonnet","messages":[{"role":"user","content":"hi"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if gotPath != "/v1/messages" {
+		t.Errorf("upstream path = %q, want /v1/messages", gotPath)
+	}
+	if !strings.Contains(gotBody, `"max_tokens"`) || !strings.Contains(gotBody, `"messages"`) {
+		t.Errorf("upstream body = %q, want Messages API shape", gotBody)
+	}
+	var out struct {
+		Object  string `json:"object"`
+		Choices []struct {
+			Message      struct{ Content string } `json:"message"`
+			FinishReason string                   `json:"finish_reason"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Object != "chat.completion" || out.Choices[0].Message.Content != "hello" || out.Choices[0].FinishReason != "stop" {
+		t.Errorf("response = %s", rec.Body.String())
+	}
+}
+
+func TestProxyHandlerTranslatesAnthropicStream(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"message_start","message":{"id":"msg_1","model":"claude-3-5-sonnet","usage":{"input_tokens":3}}}`,
+			`{"type":"content_block_delta","delta":{"type":"text_delta","text":"hi"}}`,
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":1}}`,
+		}
+		for _, ev := range events {
+			io.WriteString(w, "event: x\ndata: "+ev+"\n\n")
+		}
+	}))
+	defer upstream.Close()
+
+	svc := proxy.NewService()
+	if err := svc.SetRoute(proxy.Route{Name: "claude", Upstream: upstream.URL, Protocol: "anthropic"}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/proxy/claude/v1/chat/completions",
+		strings.NewReader(`{"model":"claude-3-5-sonnet","messages":[{"role":"user","content":"hi"}],"stream":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	out := rec.Body.String()
+	if !strings.Contains(out, `"object":"chat.completion.chunk"`) {
+		t.Errorf("stream = %q, want OpenAI chunks", out)
+	}
+	if !strings.Contains(out, `"content":"hi"`) {
+		t.Errorf("stream = %q, want the text delta", out)
+	}
+	if !strings.HasSuffix(out, "data: [DONE]\n\n") {
+		t.Errorf("stream = %q, want trailing [DONE]", out)
+	}
+}
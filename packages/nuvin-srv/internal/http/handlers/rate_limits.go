@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/ratelimit"
+)
+
+// RateLimitsHandler reads and replaces the request rate-limit rules at
+// GET/PUT /rate-limits. Rules are keyed by scope ("proxy/openai", "fetch",
+// or "" for every request) and bucket per subject.
+type RateLimitsHandler struct {
+	Limiter *ratelimit.RequestLimiter
+}
+
+func (h *RateLimitsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"rules": h.Limiter.Rules()})
+	case http.MethodPut:
+		var req struct {
+			Rules map[string]ratelimit.Rule `json:"rules"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		h.Limiter.SetRules(req.Rules)
+		writeJSON(w, http.StatusOK, map[string]any{"rules": h.Limiter.Rules()})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
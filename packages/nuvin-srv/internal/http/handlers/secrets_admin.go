@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+// SecretsAdminHandler administers the encrypted secret store: GET
+// /secrets/audit returns the trail of secret reads, POST /secrets/rotate
+// re-encrypts the store under a new master secret. Secret values are never
+// readable over HTTP.
+type SecretsAdminHandler struct {
+	Store *secrets.Store
+}
+
+func (h *SecretsAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/secrets"), "/")
+	switch {
+	case rest == "audit" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"reads": h.Store.Audit()})
+	case rest == "rotate" && r.Method == http.MethodPost:
+		var req struct {
+			MasterSecret string `json:"masterSecret"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if err := h.Store.Rotate(req.MasterSecret); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"rotated": true})
+	case rest == "audit" || rest == "rotate":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/marschhuynh/nuvin-srv/internal/terminal"
+)
+
+// TerminalSessionsHandler manages PTY sessions: POST /terminal/sessions
+// creates one, GET lists them, DELETE /terminal/sessions/{id} kills one.
+type TerminalSessionsHandler struct {
+	Manager *terminal.Manager
+}
+
+func (h *TerminalSessionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/terminal/sessions")
+	id = strings.TrimPrefix(id, "/")
+	if strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if id == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"sessions": h.Manager.List()})
+		case http.MethodPost:
+			var opts terminal.CreateOptions
+			if r.Body != nil && r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+					writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+					return
+				}
+			}
+			s, err := h.Manager.Create(opts)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, s)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !h.Manager.Kill(id) {
+		writeError(w, http.StatusNotFound, "unknown session: "+id)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "killed": true})
+}
+
+// terminalMessage is a control frame from the client: typed input or a
+// resize. PTY output flows the other way as binary frames.
+type terminalMessage struct {
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+}
+
+// TerminalWSHandler bridges a PTY session over WebSocket at
+// GET /terminal/ws/{id}.
+type TerminalWSHandler struct {
+	Manager  *terminal.Manager
+	Upgrader websocket.Upgrader
+}
+
+// NewTerminalWSHandler returns a handler with the local-bridge upgrader.
+func NewTerminalWSHandler(m *terminal.Manager) *TerminalWSHandler {
+	return &TerminalWSHandler{
+		Manager: m,
+		Upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			CheckOrigin:     func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+func (h *TerminalWSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/terminal/ws/")
+	session, ok := h.Manager.Get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown session: "+id)
+		return
+	}
+
+	conn, err := h.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// PTY -> client. Exits when the session ends or the first write after
+	// disconnect fails; it must not block shutdown of the request handler.
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := session.Read(buf)
+			if n > 0 {
+				if werr := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				_ = conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, "session ended"))
+				return
+			}
+		}
+	}()
+
+	// Client -> PTY.
+	for {
+		msgType, msg, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		if msgType == websocket.BinaryMessage {
+			session.Write(msg)
+			continue
+		}
+		var ctrl terminalMessage
+		if err := json.Unmarshal(msg, &ctrl); err != nil {
+			continue
+		}
+		switch ctrl.Type {
+		case "input":
+			session.Write([]byte(ctrl.Data))
+		case "resize":
+			session.Resize(ctrl.Cols, ctrl.Rows)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+	"github.com/marschhuynh/nuvin-srv/internal/oauth"
+	"github.com/marschhuynh/nuvin-srv/internal/providers"
+)
+
+// TokenStatus is one row of the token health dashboard.
+type TokenStatus struct {
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	Connected  bool       `json:"connected"`
+	Valid      *bool      `json:"valid,omitempty"`
+	Scope      string     `json:"scope,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// TokenStatusHandler serves GET /auth/tokens/status: every stored
+// credential — the GitHub OAuth token, connected OAuth accounts, and
+// provider API keys — with validity probed by lightweight API calls, so
+// the UI can flag a broken integration before a chat fails mid-stream.
+type TokenStatusHandler struct {
+	GitHub    *github.TokenManager
+	OAuth     *oauth.Manager
+	Providers *providers.Registry
+}
+
+func (h *TokenStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	tokens := []TokenStatus{}
+	tokens = append(tokens, h.githubStatus(r))
+	tokens = append(tokens, h.oauthStatuses()...)
+	tokens = append(tokens, h.providerStatuses(r)...)
+	writeJSON(w, http.StatusOK, map[string]any{"tokens": tokens})
+}
+
+func (h *TokenStatusHandler) githubStatus(r *http.Request) TokenStatus {
+	st := TokenStatus{Name: "github", Kind: "github-oauth", Connected: h.GitHub.HasGitHubToken()}
+	if last := h.GitHub.LastOK(); !last.IsZero() {
+		st.LastUsedAt = &last
+	}
+	if !st.Connected {
+		return st
+	}
+	valid := h.GitHub.Probe(r.Context()) == nil
+	st.Valid = &valid
+	if !valid {
+		st.Error = "github rejected the stored token"
+	} else if last := h.GitHub.LastOK(); !last.IsZero() {
+		st.LastUsedAt = &last
+	}
+	if cop, ok := h.GitHub.CachedCopilotToken(); ok {
+		st.ExpiresAt = &cop.ExpiresAt
+	}
+	return st
+}
+
+func (h *TokenStatusHandler) oauthStatuses() []TokenStatus {
+	var out []TokenStatus
+	for _, p := range h.OAuth.Providers() {
+		name, _ := p["name"].(string)
+		connected, _ := p["connected"].(bool)
+		st := TokenStatus{Name: name, Kind: "oauth", Connected: connected}
+		if tok, ok := h.OAuth.Token(name); ok {
+			st.Scope = tok.Scope
+			if !tok.ExpiresAt.IsZero() {
+				st.ExpiresAt = &tok.ExpiresAt
+			}
+			obtained := tok.ObtainedAt
+			st.LastUsedAt = &obtained
+			// Expired tokens with a refresh token still count as valid; the
+			// next use refreshes them.
+			valid := tok.ExpiresAt.IsZero() || time.Now().Before(tok.ExpiresAt) || tok.RefreshToken != ""
+			st.Valid = &valid
+			if !valid {
+				st.Error = "token expired with no refresh token"
+			}
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+func (h *TokenStatusHandler) providerStatuses(r *http.Request) []TokenStatus {
+	var out []TokenStatus
+	for _, p := range h.Providers.List() {
+		st := TokenStatus{Name: p.Name, Kind: "provider/" + p.Kind, Connected: p.HasKey}
+		if p.Kind == providers.KindOllama {
+			st.Connected = true
+		}
+		if st.Connected {
+			valid := true
+			if err := h.Providers.Validate(r.Context(), p.Name); err != nil {
+				valid, st.Error = false, err.Error()
+			}
+			st.Valid = &valid
+		}
+		out = append(out, st)
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+	"github.com/marschhuynh/nuvin-srv/internal/oauth"
+	"github.com/marschhuynh/nuvin-srv/internal/providers"
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+func TestTokenStatusDashboard(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/user" && r.Header.Get("Authorization") == "token gho_good":
+			w.Write([]byte(`{"login":"me"}`))
+		case r.URL.Path == "/v1/models" && r.Header.Get("Authorization") == "Bearer sk-good":
+			w.Write([]byte(`{"data":[]}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer api.Close()
+
+	gh := github.NewTokenManager()
+	gh.SetAPIBase(api.URL)
+	gh.SetGitHubToken("gho_good")
+
+	reg := providers.NewRegistry(secrets.NewStore(), nil)
+	if err := reg.Set(providers.Provider{Name: "openai", Kind: providers.KindOpenAI, BaseURL: api.URL}, "sk-good"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Set(providers.Provider{Name: "broken", Kind: providers.KindOpenAI, BaseURL: api.URL}, "sk-bad"); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &TokenStatusHandler{GitHub: gh, OAuth: oauth.NewManager(), Providers: reg}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/tokens/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var body struct {
+		Tokens []TokenStatus `json:"tokens"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]TokenStatus{}
+	for _, tok := range body.Tokens {
+		byName[tok.Name] = tok
+	}
+	if st := byName["github"]; !st.Connected || st.Valid == nil || !*st.Valid || st.LastUsedAt == nil {
+		t.Errorf("github status = %+v", st)
+	}
+	if st := byName["openai"]; st.Valid == nil || !*st.Valid {
+		t.Errorf("openai status = %+v", st)
+	}
+	if st := byName["broken"]; st.Valid == nil || *st.Valid || st.Error == "" {
+		t.Errorf("broken status = %+v", st)
+	}
+}
+
+func TestTokenStatusUnconfigured(t *testing.T) {
+	h := &TokenStatusHandler{
+		GitHub:    github.NewTokenManager(),
+		OAuth:     oauth.NewManager(),
+		Providers: providers.NewRegistry(secrets.NewStore(), nil),
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/tokens/status", nil))
+	var body struct {
+		Tokens []TokenStatus `json:"tokens"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Tokens) != 1 || body.Tokens[0].Name != "github" || body.Tokens[0].Connected {
+		t.Errorf("tokens = %+v", body.Tokens)
+	}
+}
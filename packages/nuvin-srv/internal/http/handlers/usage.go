@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/usage"
+)
+
+// UsageHandler reports proxied LLM usage: GET /usage?by=day|model|subject
+// returns aggregated buckets (day is the default), GET /usage/records the
+// recent raw accounting entries. GET/PUT /usage/quota reads and replaces
+// the per-subject quota the proxy routes enforce.
+type UsageHandler struct {
+	Tracker *usage.Tracker
+}
+
+// quotaView is the wire shape of a quota; the period travels in
+// milliseconds like every other duration in the API.
+type quotaView struct {
+	Limit    int   `json:"limit"`
+	PeriodMs int64 `json:"periodMs"`
+}
+
+func (h *UsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/usage/quota" {
+		h.serveQuota(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if r.URL.Path == "/usage/records" {
+		writeJSON(w, http.StatusOK, map[string]any{"records": h.Tracker.Records()})
+		return
+	}
+	by := r.URL.Query().Get("by")
+	if by == "" {
+		by = "day"
+	}
+	buckets, err := h.Tracker.Summarize(by)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"by": by, "buckets": buckets})
+}
+
+func (h *UsageHandler) serveQuota(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		q := h.Tracker.Quota()
+		writeJSON(w, http.StatusOK, map[string]any{"quota": quotaView{Limit: q.Limit, PeriodMs: q.Period.Milliseconds()}})
+	case http.MethodPut:
+		var req struct {
+			Quota quotaView `json:"quota"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		q := usage.Quota{Limit: req.Quota.Limit, Period: time.Duration(req.Quota.PeriodMs) * time.Millisecond}
+		if err := h.Tracker.SetQuota(q); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"quota": req.Quota})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/workspace"
+)
+
+// WorkspaceHandler exposes confined file operations under /workspace/{op}
+// (read, write, list, stat, move, delete — all POST with JSON bodies) plus
+// GET/PUT /workspace/root for the root itself.
+type WorkspaceHandler struct {
+	Service *workspace.Service
+}
+
+type workspaceRequest struct {
+	Path      string `json:"path"`
+	To        string `json:"to,omitempty"`
+	Content   string `json:"content,omitempty"`
+	Encoding  string `json:"encoding,omitempty"`
+	MaxBytes  int64  `json:"maxBytes,omitempty"`
+	Recursive bool   `json:"recursive,omitempty"`
+}
+
+func (h *WorkspaceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	op := strings.TrimPrefix(r.URL.Path, "/workspace/")
+
+	if op == "root" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"root": h.Service.Root()})
+		case http.MethodPut:
+			var body struct {
+				Root string `json:"root"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+			if err := h.Service.SetRoot(body.Root); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"root": h.Service.Root()})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if op == "search" {
+		var req workspace.SearchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		res, err := h.Service.Search(req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	var req workspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	fail := func(err error) {
+		status := http.StatusBadRequest
+		if os.IsNotExist(err) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err.Error())
+	}
+
+	switch op {
+	case "read":
+		res, err := h.Service.Read(req.Path, req.MaxBytes)
+		if err != nil {
+			fail(err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	case "write":
+		info, err := h.Service.Write(req.Path, req.Content, req.Encoding)
+		if err != nil {
+			fail(err)
+			return
+		}
+		writeJSON(w, http.StatusOK, info)
+	case "list":
+		entries, err := h.Service.List(req.Path)
+		if err != nil {
+			fail(err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"entries": entries})
+	case "stat":
+		info, err := h.Service.Stat(req.Path)
+		if err != nil {
+			fail(err)
+			return
+		}
+		writeJSON(w, http.StatusOK, info)
+	case "move":
+		if err := h.Service.Move(req.Path, req.To); err != nil {
+			fail(err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"path": req.To, "moved": true})
+	case "delete":
+		if err := h.Service.Delete(req.Path, req.Recursive); err != nil {
+			fail(err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"path": req.Path, "deleted": true})
+	default:
+		writeError(w, http.StatusNotFound, "unknown workspace operation: "+op)
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// WSProxyHandler bridges a client WebSocket to an upstream ws:// or wss://
+// endpoint at GET /ws-proxy?target=<url>. Extra headers for the upstream
+// handshake are passed as repeated header=Name:Value query parameters, so
+// the frontend can inject Authorization without exposing tokens in JS-
+// visible WebSocket subprotocols.
+type WSProxyHandler struct {
+	Upgrader websocket.Upgrader
+	Dialer   *websocket.Dialer
+}
+
+// NewWSProxyHandler returns a handler with default upgrader and dialer.
+func NewWSProxyHandler() *WSProxyHandler {
+	return &WSProxyHandler{
+		Upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			// The frontend runs on a app/file origin; same-origin checks
+			// don't apply to the local bridge.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		Dialer: websocket.DefaultDialer,
+	}
+}
+
+func (h *WSProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		writeError(w, http.StatusBadRequest, "target query parameter is required")
+		return
+	}
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "ws" && targetURL.Scheme != "wss") {
+		writeError(w, http.StatusBadRequest, "target must be a ws:// or wss:// URL")
+		return
+	}
+
+	header := http.Header{}
+	for _, hv := range r.URL.Query()["header"] {
+		name, value, ok := strings.Cut(hv, ":")
+		if !ok {
+			writeError(w, http.StatusBadRequest, "header parameter must be Name:Value, got "+hv)
+			return
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	upstream, resp, err := h.Dialer.Dial(targetURL.String(), header)
+	if err != nil {
+		status := http.StatusBadGateway
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		writeError(w, status, "dialing upstream websocket: "+err.Error())
+		return
+	}
+	defer upstream.Close()
+
+	client, err := h.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already wrote the HTTP error.
+		return
+	}
+	defer client.Close()
+
+	errc := make(chan error, 2)
+	go bridgeFrames(client, upstream, errc)
+	go bridgeFrames(upstream, client, errc)
+	<-errc
+}
+
+// bridgeFrames copies messages from src to dst preserving frame types until
+// either side fails or closes.
+func bridgeFrames(dst, src *websocket.Conn, errc chan<- error) {
+	for {
+		msgType, msg, err := src.ReadMessage()
+		if err != nil {
+			if closeErr, ok := err.(*websocket.CloseError); ok {
+				_ = dst.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(closeErr.Code, closeErr.Text))
+			}
+			errc <- err
+			return
+		}
+		if err := dst.WriteMessage(msgType, msg); err != nil {
+			errc <- err
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWSProxyBridgesFramesAndInjectsHeaders(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var gotAuth string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msgType, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(msgType, append([]byte("echo: "), msg...)); err != nil {
+				return
+			}
+		}
+	}))
+	defer upstream.Close()
+
+	proxySrv := httptest.NewServer(NewWSProxyHandler())
+	defer proxySrv.Close()
+
+	wsTarget := "ws" + strings.TrimPrefix(upstream.URL, "http")
+	proxyURL := "ws" + strings.TrimPrefix(proxySrv.URL, "http") +
+		"/?target=" + url.QueryEscape(wsTarget) +
+		"&header=" + url.QueryEscape("Authorization: Bearer ws-token")
+
+	client, _, err := websocket.DefaultDialer.Dial(proxyURL, nil)
+	if err != nil {
+		t.Fatalf("dialing proxy: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.WriteMessage(websocket.TextMessage, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	msgType, msg, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("reading echoed frame: %v", err)
+	}
+	if msgType != websocket.TextMessage || string(msg) != "echo: hello" {
+		t.Errorf("got (%d, %q), want text echo: hello", msgType, msg)
+	}
+	if gotAuth != "Bearer ws-token" {
+		t.Errorf("upstream Authorization = %q, want injected header", gotAuth)
+	}
+}
+
+func TestWSProxyRejectsBadTarget(t *testing.T) {
+	h := NewWSProxyHandler()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ws-proxy?target=http://not-ws", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// requestIDHeader carries the correlation ID between client, server, and
+// upstream.
+const requestIDHeader = "X-Request-Id"
+
+// RequestID returns the request's correlation ID, if one was assigned.
+func RequestID(r *http.Request) string {
+	return r.Header.Get(requestIDHeader)
+}
+
+// newRequestID generates a 16-byte random hex ID.
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// AccessLog assigns every request an X-Request-Id (reusing one the client
+// sent), echoes it on the response, and logs one structured line per
+// request with method, path, subject, status, duration, and bytes in/out.
+// The ID sits on r.Header, so proxied upstream requests carry it onward.
+func AccessLog(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+			r.Header.Set(requestIDHeader, id)
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		body := &countingBody{rc: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+		lw := &loggingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(lw, r)
+
+		logger.Info("request",
+			"requestId", id,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"subject", Subject(r),
+			"status", lw.status,
+			"durationMs", time.Since(start).Milliseconds(),
+			"bytesIn", body.n,
+			"bytesOut", lw.n,
+		)
+	})
+}
+
+// countingBody counts request body bytes as the handler reads them.
+type countingBody struct {
+	rc io.ReadCloser
+	n  int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.rc.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error { return b.rc.Close() }
+
+// loggingResponseWriter records the status and body size while forwarding
+// everything — including Flush, which the streaming handlers depend on.
+type loggingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	n      int64
+}
+
+func (w *loggingResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *loggingResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func (w *loggingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
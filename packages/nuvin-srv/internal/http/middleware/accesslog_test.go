@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessLogAssignsIDAndLogsFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	h := AccessLog(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created!"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/proxy/openai/v1/chat/completions",
+		strings.NewReader("0123456789"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	id := rec.Header().Get("X-Request-Id")
+	if len(id) != 32 {
+		t.Fatalf("X-Request-Id = %q, want generated 32-char hex", id)
+	}
+	var entry struct {
+		Msg        string `json:"msg"`
+		RequestID  string `json:"requestId"`
+		Method     string `json:"method"`
+		Path       string `json:"path"`
+		Status     int    `json:"status"`
+		BytesIn    int64  `json:"bytesIn"`
+		BytesOut   int64  `json:"bytesOut"`
+		DurationMs *int64 `json:"durationMs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log line %q: %v", buf.String(), err)
+	}
+	if entry.Msg != "request" || entry.RequestID != id || entry.Method != "POST" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.Path != "/proxy/openai/v1/chat/completions" || entry.Status != http.StatusCreated {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.BytesIn != 10 || entry.BytesOut != 8 || entry.DurationMs == nil {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+func TestAccessLogReusesClientRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	var upstreamSaw string
+	h := AccessLog(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The ID rides on r.Header so proxied requests propagate it.
+		upstreamSaw = RequestID(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/fetch", nil)
+	req.Header.Set("X-Request-Id", "client-chosen-id")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("X-Request-Id"); got != "client-chosen-id" {
+		t.Errorf("response id = %q, want the client's", got)
+	}
+	if upstreamSaw != "client-chosen-id" {
+		t.Errorf("handler saw id %q", upstreamSaw)
+	}
+	if !strings.Contains(buf.String(), "client-chosen-id") {
+		t.Errorf("log line %q missing the id", buf.String())
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+// ScopesHeader carries the authenticated key's scopes to downstream
+// middleware; it is server-set and never trusted from the client.
+const ScopesHeader = "X-Nuvin-Scopes"
+
+// Scopes returns the scopes the request authenticated with, empty when
+// the request carried no API key.
+func Scopes(r *http.Request) []string {
+	v := r.Header.Get(ScopesHeader)
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// APIKey authenticates requests presenting "Authorization: Bearer nvn_…"
+// keys: a valid key sets the subject to the key's name and records its
+// scopes, an invalid one is rejected, and requests without a key pass
+// through untouched (enforcement is a separate concern). Client-supplied
+// subject and scope headers are stripped either way so they can't be
+// spoofed.
+func APIKey(store *auth.KeyStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del(ScopesHeader)
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !strings.HasPrefix(token, "nvn_") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Header.Del(SubjectHeader)
+		key, ok := store.Verify(token)
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"error": "invalid api key",
+				"code":  "invalid_api_key",
+			})
+			return
+		}
+		r.Header.Set(SubjectHeader, "key:"+key.Name)
+		if len(key.Scopes) > 0 {
+			r.Header.Set(ScopesHeader, strings.Join(key.Scopes, ","))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+func TestAPIKeyAuthenticates(t *testing.T) {
+	store := auth.NewKeyStore()
+	plaintext, _, err := store.Issue("ci", []string{"proxy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sawSubject string
+	var sawScopes []string
+	h := APIKey(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawSubject = Subject(r)
+		sawScopes = Scopes(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/proxy/openai/v1/models", nil)
+	req.Header.Set("Authorization", "Bearer "+plaintext)
+	req.Header.Set(SubjectHeader, "spoofed")
+	req.Header.Set(ScopesHeader, "admin")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || sawSubject != "key:ci" {
+		t.Fatalf("code = %d, subject = %q", rec.Code, sawSubject)
+	}
+	if !reflect.DeepEqual(sawScopes, []string{"proxy"}) {
+		t.Errorf("scopes = %v", sawScopes)
+	}
+}
+
+func TestAPIKeyRejectsInvalid(t *testing.T) {
+	h := APIKey(auth.NewKeyStore(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler reached with an invalid key")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/usage", nil)
+	req.Header.Set("Authorization", "Bearer nvn_bogus")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("code = %d", rec.Code)
+	}
+}
+
+func TestAPIKeyPassThroughWithoutKey(t *testing.T) {
+	called := false
+	h := APIKey(auth.NewKeyStore(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if Scopes(r) != nil {
+			t.Errorf("scopes = %v, want stripped", Scopes(r))
+		}
+	}))
+	// Upstream bearer tokens that aren't ours must pass through untouched.
+	req := httptest.NewRequest(http.MethodGet, "/fetch", nil)
+	req.Header.Set("Authorization", "Bearer sk-openai")
+	req.Header.Set(ScopesHeader, "admin")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if !called || rec.Code != http.StatusOK {
+		t.Fatalf("called = %v, code = %d", called, rec.Code)
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitBodyRejectsOversizedContentLength(t *testing.T) {
+	h := LimitBody(10, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler ran for an oversized request")
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/proxy/openai/v1/chat/completions",
+		strings.NewReader("this body is longer than ten bytes"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", rec.Code)
+	}
+}
+
+func TestLimitBodyCapsChunkedReads(t *testing.T) {
+	var readErr error
+	h := LimitBody(10, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+	// No declared Content-Length, so the cap must bite during the read.
+	req := httptest.NewRequest(http.MethodPost, "/fetch", strings.NewReader(strings.Repeat("x", 100)))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if readErr == nil {
+		t.Error("reading past the limit did not fail")
+	}
+}
+
+func TestLimitBodyDisabledPassesThrough(t *testing.T) {
+	var got []byte
+	h := LimitBody(0, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = io.ReadAll(r.Body)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/fetch", strings.NewReader(strings.Repeat("x", 100)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if len(got) != 100 {
+		t.Errorf("read %d bytes, want 100", len(got))
+	}
+}
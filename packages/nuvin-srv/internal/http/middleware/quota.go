@@ -0,0 +1,46 @@
+// Package middleware holds HTTP middleware shared across nuvin-srv routes.
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/usage"
+)
+
+// SubjectHeader identifies the requesting subject until full authentication
+// lands; authenticated deployments overwrite it from the verified identity.
+const SubjectHeader = "X-Nuvin-Subject"
+
+// Subject returns the subject a request is attributed to, falling back to
+// "anonymous" so unauthenticated local use still shares one bucket.
+func Subject(r *http.Request) string {
+	if s := r.Header.Get(SubjectHeader); s != "" {
+		return s
+	}
+	return "anonymous"
+}
+
+// EnforceQuota rejects requests from subjects that exhausted their usage
+// quota with 429 and the window reset time. With no quota configured it is
+// a pass-through (usage is still recorded).
+func EnforceQuota(tracker *usage.Tracker, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, resetAt := tracker.Allow(Subject(r))
+		if !allowed {
+			retryAfter := int(time.Until(resetAt).Seconds()) + 1
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"error":   "usage quota exceeded",
+				"code":    "quota_exceeded",
+				"resetAt": resetAt.UTC().Format(time.RFC3339),
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
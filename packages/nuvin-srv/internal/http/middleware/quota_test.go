@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/usage"
+)
+
+func TestEnforceQuotaRejectsExhaustedSubject(t *testing.T) {
+	tracker := usage.NewTracker()
+	tracker.SetQuota(usage.Quota{Limit: 2, Period: time.Hour})
+
+	h := EnforceQuota(tracker, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/proxy/openai/v1", nil)
+		req.Header.Set(SubjectHeader, subject)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := do("alice"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i+1, rec.Code)
+		}
+	}
+
+	rec := do("alice")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("over-quota status = %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 response missing Retry-After header")
+	}
+
+	if rec := do("bob"); rec.Code != http.StatusOK {
+		t.Errorf("other subject status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/ratelimit"
+)
+
+// RateLimit enforces the request limiter for the scope derived from the URL
+// path (leading slash stripped, so /proxy/openai/... matches a "proxy/openai"
+// rule), keyed per subject. Limited requests get the standard X-RateLimit-*
+// headers; rejected ones get 429 with Retry-After.
+func RateLimit(limiter *ratelimit.RequestLimiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := limiter.Allow(strings.Trim(r.URL.Path, "/"), Subject(r))
+		if d.Limited {
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(d.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(d.Reset.Unix(), 10))
+		}
+		if !d.Allowed {
+			retryAfter := int(d.RetryAfter.Seconds()) + 1
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"error": "rate limit exceeded",
+				"code":  "rate_limited",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/ratelimit"
+)
+
+func TestRateLimitSetsHeadersAndRejects(t *testing.T) {
+	limiter := ratelimit.NewRequestLimiter()
+	limiter.SetRules(map[string]ratelimit.Rule{"proxy/openai": {RPS: 0.001, Burst: 1}})
+
+	h := RateLimit(limiter, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(subject, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(SubjectHeader, subject)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("alice", "/proxy/openai/v1/chat/completions")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d, want 200", rec.Code)
+	}
+	if rec.Header().Get("X-RateLimit-Limit") != "1" || rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("headers = limit %q remaining %q", rec.Header().Get("X-RateLimit-Limit"), rec.Header().Get("X-RateLimit-Remaining"))
+	}
+
+	rec = do("alice", "/proxy/openai/v1/chat/completions")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request status = %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 response missing Retry-After header")
+	}
+
+	// Other subjects and unmatched routes pass.
+	if rec := do("bob", "/proxy/openai/v1/chat/completions"); rec.Code != http.StatusOK {
+		t.Errorf("other subject status = %d, want 200", rec.Code)
+	}
+	rec = do("alice", "/proxy/other/v1")
+	if rec.Code != http.StatusOK {
+		t.Errorf("unmatched route status = %d, want 200", rec.Code)
+	}
+	if rec.Header().Get("X-RateLimit-Limit") != "" {
+		t.Error("unmatched route carries rate limit headers")
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+// RequireAuth rejects unauthenticated requests when enforcement is on.
+// It runs after APIKey, so a request still carrying a "nvn_" bearer token
+// here has already been verified; anything else must present a session
+// access token obtained from /auth/login via the local token handshake.
+// With enforcement off (the dev escape hatch) requests pass through
+// unchanged.
+func RequireAuth(a *auth.Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a == nil || !a.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.URL.Path == "/auth/login" || r.URL.Path == "/auth/refresh" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok && strings.HasPrefix(token, "nvn_") {
+			// Validated (and subject set) by the APIKey middleware.
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Header.Del(SubjectHeader)
+		if ok {
+			if subject, valid := a.Verify(token); valid {
+				r.Header.Set(SubjectHeader, subject)
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"error": "authentication required",
+			"code":  "auth_required",
+		})
+	})
+}
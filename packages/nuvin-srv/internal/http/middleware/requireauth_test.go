@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+func TestRequireAuthDisabledPassesThrough(t *testing.T) {
+	a := auth.NewAuthenticator()
+	h := RequireAuth(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ran"))
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/execute-command", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 with auth disabled", rec.Code)
+	}
+}
+
+func TestRequireAuthEnforced(t *testing.T) {
+	a := auth.NewAuthenticator()
+	a.Enable(true)
+	local, err := a.LocalToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := a.Login(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotSubject string
+	h := RequireAuth(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSubject = r.Header.Get(SubjectHeader)
+	}))
+
+	// No credentials: rejected.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/execute-command", nil))
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "auth_required") {
+		t.Fatalf("anonymous: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	// A spoofed subject header doesn't help.
+	req := httptest.NewRequest(http.MethodPost, "/execute-command", nil)
+	req.Header.Set(SubjectHeader, "key:admin")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("spoofed subject: status = %d, want 401", rec.Code)
+	}
+
+	// A session token from Login is accepted and sets the subject.
+	req = httptest.NewRequest(http.MethodPost, "/execute-command", nil)
+	req.Header.Set("Authorization", "Bearer "+sess.AccessToken)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("session token: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if gotSubject != "local" {
+		t.Errorf("subject = %q, want local", gotSubject)
+	}
+
+	// API keys were already verified upstream and pass through.
+	req = httptest.NewRequest(http.MethodPost, "/execute-command", nil)
+	req.Header.Set("Authorization", "Bearer nvn_whatever")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("api key: status = %d, want 200", rec.Code)
+	}
+
+	// The login endpoint stays reachable for the handshake.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/login", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login: status = %d, want 200", rec.Code)
+	}
+}
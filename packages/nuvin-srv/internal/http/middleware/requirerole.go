@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+// RequireRole rejects requests whose subject doesn't hold at least the
+// required role with 403. Role lookup goes through the subject the request
+// authenticated as, so API keys and UI sessions share one table.
+func RequireRole(roles *auth.Roles, required string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := roles.Role(Subject(r))
+		if !auth.RoleAllows(role, required) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"error": "role " + role + " may not access this endpoint",
+				"code":  "forbidden",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
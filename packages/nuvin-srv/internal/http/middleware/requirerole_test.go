@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+)
+
+func TestRequireRole(t *testing.T) {
+	roles := auth.NewRoles()
+	roles.SetDefault(auth.RoleViewer)
+	roles.Assign("alice", auth.RoleAdmin)
+	roles.Assign("key:ci", auth.RoleOperator)
+
+	h := RequireRole(roles, auth.RoleAdmin, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ran"))
+	}))
+
+	cases := []struct {
+		subject string
+		want    int
+	}{
+		{"alice", http.StatusOK},
+		{"key:ci", http.StatusForbidden},
+		{"", http.StatusForbidden}, // anonymous falls back to the default role
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/execute-command", nil)
+		if c.subject != "" {
+			req.Header.Set(SubjectHeader, c.subject)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != c.want {
+			t.Errorf("subject %q: status = %d, want %d", c.subject, rec.Code, c.want)
+		}
+		if c.want == http.StatusForbidden && !strings.Contains(rec.Body.String(), "forbidden") {
+			t.Errorf("subject %q: body = %s", c.subject, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package http wires the nuvin-srv HTTP surface together.
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/marschhuynh/nuvin-srv/internal/auth"
+	"github.com/marschhuynh/nuvin-srv/internal/capture"
+	"github.com/marschhuynh/nuvin-srv/internal/command"
+	"github.com/marschhuynh/nuvin-srv/internal/discovery"
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+	"github.com/marschhuynh/nuvin-srv/internal/gitops"
+	"github.com/marschhuynh/nuvin-srv/internal/http/handlers"
+	"github.com/marschhuynh/nuvin-srv/internal/http/middleware"
+	"github.com/marschhuynh/nuvin-srv/internal/mcp"
+	"github.com/marschhuynh/nuvin-srv/internal/oauth"
+	"github.com/marschhuynh/nuvin-srv/internal/providers"
+	"github.com/marschhuynh/nuvin-srv/internal/proxy"
+	"github.com/marschhuynh/nuvin-srv/internal/ratelimit"
+	"github.com/marschhuynh/nuvin-srv/internal/reqtemplate"
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+	"github.com/marschhuynh/nuvin-srv/internal/terminal"
+	"github.com/marschhuynh/nuvin-srv/internal/usage"
+	"github.com/marschhuynh/nuvin-srv/internal/workspace"
+)
+
+// Limits protects the server from misbehaving clients. MaxBodyBytes caps
+// request body size (0 = unlimited); the timeouts go straight onto the
+// http.Server. WriteTimeout defaults to zero because streaming responses
+// (SSE, terminals) legitimately stay open for a long time.
+type Limits struct {
+	MaxBodyBytes      int64
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+}
+
+// DefaultLimits keeps slow clients from pinning connections while leaving
+// streaming untouched.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxBodyBytes:      64 << 20,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+}
+
+// Server holds the shared services and the route mux.
+type Server struct {
+	FetchProxy *fetchproxy.FetchProxy
+	Proxy      *proxy.Service
+	Usage      *usage.Tracker
+	Capture    *capture.Store
+	Templates  *reqtemplate.Store
+	Runner     *command.Runner
+	Terminals  *terminal.Manager
+	Git        *gitops.Service
+	Workspace  *workspace.Service
+	MCP        *mcp.Manager
+	Secrets    *secrets.Store
+	RateLimit  *ratelimit.RequestLimiter
+	Discovery  *discovery.Scanner
+	GitHub     *github.TokenManager
+	DeviceFlow *github.DeviceFlow
+	Copilot    *github.ModelCatalog
+	GitHubAPI  *github.REST
+	Providers  *providers.Registry
+	OAuth      *oauth.Manager
+	Keys       *auth.KeyStore
+	Roles      *auth.Roles
+	Auth       *auth.Authenticator
+	Limits     Limits
+	// AccessLog receives one structured line per request; nil disables
+	// access logging (tests construct servers without one).
+	AccessLog *slog.Logger
+
+	mux *http.ServeMux
+}
+
+// NewServer constructs a Server with default services and registers routes.
+func NewServer() *Server {
+	s := &Server{
+		FetchProxy: fetchproxy.New(),
+		Proxy:      proxy.NewService(),
+		Usage:      usage.NewTracker(),
+		Capture:    capture.NewStore(0),
+		Templates:  reqtemplate.NewStore(),
+		Runner:     command.NewRunner(),
+		Terminals:  terminal.NewManager(),
+		Git:        gitops.NewService(),
+		Workspace:  workspace.NewService(),
+		MCP:        mcp.NewManager(),
+		Secrets:    secrets.NewStore(),
+		RateLimit:  ratelimit.NewRequestLimiter(),
+		Discovery:  discovery.NewScanner(),
+		GitHub:     github.NewTokenManager(),
+		OAuth:      oauth.NewManager(),
+		Keys:       auth.NewKeyStore(),
+		Roles:      auth.NewRoles(),
+		Auth:       auth.NewAuthenticator(),
+		Limits:     DefaultLimits(),
+		mux:        http.NewServeMux(),
+	}
+	s.DeviceFlow = github.NewDeviceFlow(s.GitHub)
+	s.Copilot = github.NewModelCatalog(s.GitHub)
+	s.GitHubAPI = github.NewREST(s.GitHub)
+	s.Providers = providers.NewRegistry(s.Secrets, s.GitHub)
+	s.MCP.SetSecretStore(s.Secrets)
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	// admin gates endpoints that run code or change server config; operator
+	// gates active tooling. Everything unwrapped is viewer-readable status.
+	admin := func(h http.Handler) http.Handler { return middleware.RequireRole(s.Roles, auth.RoleAdmin, h) }
+	operator := func(h http.Handler) http.Handler { return middleware.RequireRole(s.Roles, auth.RoleOperator, h) }
+
+	s.mux.Handle("/fetch", operator(middleware.RateLimit(s.RateLimit, &handlers.FetchHandler{Proxy: s.FetchProxy})))
+	s.mux.Handle("/fetch/batch", operator(&handlers.FetchBatchHandler{Proxy: s.FetchProxy}))
+	s.mux.Handle("/fetch/cookies/", &handlers.FetchCookiesHandler{Proxy: s.FetchProxy})
+	s.mux.Handle("/fetch/abort/", &handlers.FetchAbortHandler{Proxy: s.FetchProxy})
+	s.mux.Handle("/fetch/recordings", &handlers.FetchRecordingsHandler{Store: s.Capture})
+	s.mux.Handle("/fetch/recordings/", &handlers.FetchRecordingsHandler{Store: s.Capture})
+	s.mux.Handle("/fetch/replay/", operator(&handlers.FetchReplayHandler{Store: s.Capture, Proxy: s.FetchProxy}))
+	s.mux.Handle("/fetch/policy", admin(&handlers.FetchPolicyHandler{Proxy: s.FetchProxy}))
+	s.mux.Handle("/fetch/host-limits", admin(&handlers.FetchHostLimitsHandler{Proxy: s.FetchProxy}))
+	s.mux.Handle("/fetch/templates", &handlers.FetchTemplatesHandler{Store: s.Templates})
+	s.mux.Handle("/fetch/templates/", &handlers.FetchTemplatesHandler{Store: s.Templates})
+	s.mux.Handle("/fetch/run-template", operator(&handlers.RunTemplateHandler{Store: s.Templates, Proxy: s.FetchProxy}))
+	s.mux.Handle("/fetch/secrets", &handlers.FetchSecretsHandler{Proxy: s.FetchProxy})
+	s.mux.Handle("/fetch/upload/", operator(&handlers.FetchUploadHandler{Proxy: s.FetchProxy}))
+	s.mux.Handle("/fetch/har", &handlers.FetchHARHandler{Store: s.Capture})
+	proxyHandler := handlers.NewProxyHandler(s.Proxy)
+	proxyHandler.Usage = s.Usage
+	proxyHandler.Providers = s.Providers
+	s.mux.Handle("/proxy/", operator(middleware.RateLimit(s.RateLimit, middleware.EnforceQuota(s.Usage, proxyHandler))))
+	s.mux.Handle("/v1/", operator(middleware.RateLimit(s.RateLimit, middleware.EnforceQuota(s.Usage, &handlers.OpenAIV1Handler{Service: s.Proxy, Proxy: proxyHandler}))))
+	s.mux.Handle("/model-routes", &handlers.ModelRoutesHandler{Service: s.Proxy})
+	s.mux.Handle("/providers", &handlers.ProvidersHandler{Registry: s.Providers})
+	s.mux.Handle("/providers/", &handlers.ProvidersHandler{Registry: s.Providers})
+	s.mux.Handle("/providers/local", &handlers.LocalProvidersHandler{Scanner: s.Discovery, Proxy: s.Proxy})
+	s.mux.Handle("/oauth/", &handlers.OAuthHandler{Manager: s.OAuth})
+	s.mux.Handle("/auth/login", &handlers.LoginHandler{Auth: s.Auth})
+	s.mux.Handle("/auth/refresh", &handlers.RefreshHandler{Auth: s.Auth})
+	s.mux.Handle("/auth/sessions", admin(&handlers.SessionsHandler{Auth: s.Auth}))
+	s.mux.Handle("/auth/sessions/", admin(&handlers.SessionsHandler{Auth: s.Auth}))
+	s.mux.Handle("/auth/tokens/status", &handlers.TokenStatusHandler{GitHub: s.GitHub, OAuth: s.OAuth, Providers: s.Providers})
+	s.mux.Handle("/auth/api-keys", admin(&handlers.APIKeysHandler{Store: s.Keys}))
+	s.mux.Handle("/auth/api-keys/", admin(&handlers.APIKeysHandler{Store: s.Keys}))
+	s.mux.Handle("/auth/roles", admin(&handlers.RolesHandler{Roles: s.Roles}))
+	s.mux.Handle("/auth/roles/", admin(&handlers.RolesHandler{Roles: s.Roles}))
+	s.mux.Handle("/secrets/", admin(&handlers.SecretsAdminHandler{Store: s.Secrets}))
+	s.mux.Handle("/github/device-flow/session", &handlers.DeviceFlowHandler{Flow: s.DeviceFlow})
+	s.mux.Handle("/github/device-flow/session/", &handlers.DeviceFlowHandler{Flow: s.DeviceFlow})
+	s.mux.Handle("/github/repos", &handlers.GitHubAPIHandler{REST: s.GitHubAPI})
+	s.mux.Handle("/github/issues", &handlers.GitHubAPIHandler{REST: s.GitHubAPI})
+	s.mux.Handle("/github/prs", &handlers.GitHubAPIHandler{REST: s.GitHubAPI})
+	s.mux.Handle("/github/copilot/models", &handlers.CopilotModelsHandler{Catalog: s.Copilot})
+	s.mux.Handle("/github/copilot-token", &handlers.CopilotTokenHandler{Manager: s.GitHub})
+	s.mux.Handle("/github/copilot-token/", &handlers.CopilotTokenHandler{Manager: s.GitHub})
+	s.mux.Handle("/rate-limits", &handlers.RateLimitsHandler{Limiter: s.RateLimit})
+	s.mux.Handle("/usage", &handlers.UsageHandler{Tracker: s.Usage})
+	s.mux.Handle("/usage/records", &handlers.UsageHandler{Tracker: s.Usage})
+	s.mux.Handle("/usage/quota", admin(&handlers.UsageHandler{Tracker: s.Usage}))
+	s.mux.Handle("/proxy-cache", &handlers.ProxyCacheHandler{Service: s.Proxy})
+	s.mux.Handle("/proxy-cache/", &handlers.ProxyCacheHandler{Service: s.Proxy})
+	s.mux.Handle("/proxy-config", admin(&handlers.ProxyConfigHandler{Service: s.Proxy}))
+	s.mux.Handle("/proxy-config/", admin(&handlers.ProxyConfigHandler{Service: s.Proxy}))
+	s.mux.Handle("/ws-proxy", operator(handlers.NewWSProxyHandler()))
+	s.mux.Handle("/execute-command", admin(middleware.RateLimit(s.RateLimit, &handlers.ExecuteCommandHandler{Runner: s.Runner})))
+	s.mux.Handle("/execute-command/stream", admin(middleware.RateLimit(s.RateLimit, &handlers.ExecuteCommandStreamHandler{Runner: s.Runner})))
+	s.mux.Handle("/jobs", &handlers.JobsHandler{Runner: s.Runner})
+	s.mux.Handle("/jobs/", &handlers.JobsHandler{Runner: s.Runner})
+	s.mux.Handle("/execute-command/policy", admin(&handlers.ExecutePolicyHandler{Engine: s.Runner.Policy}))
+	s.mux.Handle("/execute-command/decisions", admin(&handlers.ExecutePolicyHandler{Engine: s.Runner.Policy}))
+	s.mux.Handle("/execute-command/sandbox", admin(&handlers.ExecuteSandboxHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-command/queue", admin(&handlers.ExecuteQueueHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-command/signal-policy", admin(&handlers.SignalPolicyHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-command/approvals", admin(&handlers.ExecuteApprovalsHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-command/approvals/", admin(&handlers.ExecuteApprovalsHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-command/approval-policy", admin(&handlers.ApprovalPolicyHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-command/history", admin(&handlers.ExecuteHistoryHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-command/history/", admin(&handlers.ExecuteHistoryHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-command/", admin(&handlers.ExecuteStdinHandler{Runner: s.Runner}))
+	s.mux.Handle("/execute-script", admin(&handlers.ExecuteScriptHandler{Runner: s.Runner}))
+	s.mux.Handle("/env-profiles", admin(&handlers.EnvProfilesHandler{Store: s.Runner.Profiles}))
+	s.mux.Handle("/env-profiles/", admin(&handlers.EnvProfilesHandler{Store: s.Runner.Profiles}))
+	s.mux.Handle("/git/", operator(&handlers.GitHandler{Service: s.Git}))
+	s.mux.Handle("/api/mcp/tools", &handlers.MCPToolsHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/tools/call", operator(&handlers.MCPToolCallHandler{Manager: s.MCP}))
+	s.mux.Handle("/api/mcp/prompts", &handlers.MCPPromptsHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/prompts/get", &handlers.MCPPromptsHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/servers", &handlers.MCPServersHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/servers/", &handlers.MCPServersHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/groups", &handlers.MCPGroupsHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/groups/", &handlers.MCPGroupsHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/import", &handlers.MCPImportHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/registry", &handlers.MCPRegistryHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/registry/", &handlers.MCPRegistryHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/secrets", &handlers.MCPSecretsHandler{Store: s.Secrets})
+	s.mux.Handle("/api/mcp/roots", &handlers.MCPRootsHandler{Manager: s.MCP, Workspace: s.Workspace})
+	s.mux.Handle("/api/mcp/resources", &handlers.MCPResourcesHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/resources/", &handlers.MCPResourcesHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/health", &handlers.MCPHealthHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/health/check", &handlers.MCPHealthHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/idle", &handlers.MCPIdleHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/idle/reap", &handlers.MCPIdleHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/metrics", &handlers.MCPMetricsHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/metrics/stream", &handlers.MCPMetricsHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/events", &handlers.MCPEventsSSEHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/events/", &handlers.MCPEventsSSEHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/ws", handlers.NewMCPEventsWSHandler(s.MCP))
+	s.mux.Handle("/api/mcp/ws/", handlers.NewMCPEventsWSHandler(s.MCP))
+	s.mux.Handle("/api/mcp/http", &handlers.MCPHTTPHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/http/", &handlers.MCPHTTPHandler{Manager: s.MCP})
+	s.mux.Handle("/api/mcp/stdio", admin(&handlers.MCPStdioHandler{Manager: s.MCP}))
+	s.mux.Handle("/api/mcp/stdio/", admin(&handlers.MCPStdioHandler{Manager: s.MCP}))
+	s.mux.Handle("/workspace/", operator(&handlers.WorkspaceHandler{Service: s.Workspace}))
+	s.mux.Handle("/terminal/sessions", operator(&handlers.TerminalSessionsHandler{Manager: s.Terminals}))
+	s.mux.Handle("/terminal/sessions/", operator(&handlers.TerminalSessionsHandler{Manager: s.Terminals}))
+	s.mux.Handle("/terminal/ws/", operator(handlers.NewTerminalWSHandler(s.Terminals)))
+}
+
+func (s *Server) Handler() http.Handler {
+	h := middleware.LimitBody(s.Limits.MaxBodyBytes,
+		middleware.APIKey(s.Keys, middleware.RequireAuth(s.Auth, s.mux)))
+	if s.AccessLog != nil {
+		h = middleware.AccessLog(s.AccessLog, h)
+	}
+	return h
+}
+
+// ListenAndServe starts the server on addr with the configured limits;
+// ReadHeaderTimeout in particular keeps slowloris clients from holding
+// connections open with dribbled request headers. The handler is wrapped
+// for h2c so native gRPC clients (which require HTTP/2 but get no TLS on
+// localhost) can reach gRPC proxy routes.
+func (s *Server) ListenAndServe(addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           h2c.NewHandler(s.Handler(), &http2.Server{}),
+		ReadHeaderTimeout: s.Limits.ReadHeaderTimeout,
+		ReadTimeout:       s.Limits.ReadTimeout,
+		WriteTimeout:      s.Limits.WriteTimeout,
+		IdleTimeout:       s.Limits.IdleTimeout,
+	}
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Event types published to subscribers.
+const (
+	EventStarted      = "started"
+	EventExited       = "exited"
+	EventNotification = "notification"
+	EventHealth       = "health"
+	EventRequest      = "request"
+	EventResource     = "resource"
+	EventOutput       = "output"
+)
+
+// defaultRingCapacity bounds each subscriber's buffer.
+const defaultRingCapacity = 256
+
+// historyCapacity bounds the shared replay buffer reconnecting clients
+// resume from.
+const historyCapacity = 1024
+
+// Event is one observable occurrence on a managed MCP process.
+type Event struct {
+	Seq     int64           `json:"seq"`
+	Time    time.Time       `json:"time"`
+	Process string          `json:"process"`
+	Type    string          `json:"type"`
+	Method  string          `json:"method,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Subscriber receives events through its own ring buffer: publishing never
+// blocks, and a slow consumer loses only its oldest events, never anyone
+// else's.
+type Subscriber struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    []Event
+	start  int
+	count  int
+	closed bool
+	filter string // process ID, empty means all
+}
+
+func newSubscriber(filter string, capacity int) *Subscriber {
+	if capacity <= 0 {
+		capacity = defaultRingCapacity
+	}
+	s := &Subscriber{buf: make([]Event, capacity), filter: filter}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// push appends the event, overwriting the oldest when the ring is full.
+func (s *Subscriber) push(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if s.count == len(s.buf) {
+		s.start = (s.start + 1) % len(s.buf)
+		s.count--
+	}
+	s.buf[(s.start+s.count)%len(s.buf)] = ev
+	s.count++
+	s.cond.Signal()
+}
+
+// Next blocks until an event is available; ok is false once the subscriber
+// is closed and drained.
+func (s *Subscriber) Next() (Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.count == 0 && !s.closed {
+		s.cond.Wait()
+	}
+	if s.count == 0 {
+		return Event{}, false
+	}
+	ev := s.buf[s.start]
+	s.start = (s.start + 1) % len(s.buf)
+	s.count--
+	return ev, true
+}
+
+// Close wakes any blocked Next and stops further delivery.
+func (s *Subscriber) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+// Subscribe registers a new event consumer. An empty procID watches every
+// process; capacity 0 uses the 256-event default.
+func (m *Manager) Subscribe(procID string, capacity int) *Subscriber {
+	return m.SubscribeFrom(procID, capacity, 0)
+}
+
+// SubscribeFrom registers a consumer and replays retained events with a
+// sequence number greater than afterSeq before live delivery, so clients can
+// reconnect without losing what was published while they were away.
+// afterSeq 0 skips replay.
+func (m *Manager) SubscribeFrom(procID string, capacity int, afterSeq int64) *Subscriber {
+	sub := newSubscriber(procID, capacity)
+	m.mu.Lock()
+	if afterSeq > 0 {
+		for i := 0; i < m.histCount; i++ {
+			ev := m.history[(m.histStart+i)%len(m.history)]
+			if ev.Seq > afterSeq && (sub.filter == "" || sub.filter == ev.Process) {
+				sub.push(ev)
+			}
+		}
+	}
+	if m.subs == nil {
+		m.subs = make(map[*Subscriber]struct{})
+	}
+	m.subs[sub] = struct{}{}
+	m.mu.Unlock()
+	return sub
+}
+
+// Unsubscribe removes and closes the consumer.
+func (m *Manager) Unsubscribe(sub *Subscriber) {
+	m.mu.Lock()
+	delete(m.subs, sub)
+	m.mu.Unlock()
+	sub.Close()
+}
+
+// publish fans an event out to every matching subscriber.
+func (m *Manager) publish(procID, eventType, method string, payload json.RawMessage) {
+	m.mu.Lock()
+	m.eventSeq++
+	ev := Event{
+		Seq:     m.eventSeq,
+		Time:    time.Now().UTC(),
+		Process: procID,
+		Type:    eventType,
+		Method:  method,
+		Payload: payload,
+	}
+	if m.history == nil {
+		m.history = make([]Event, historyCapacity)
+	}
+	if m.histCount == len(m.history) {
+		m.histStart = (m.histStart + 1) % len(m.history)
+		m.histCount--
+	}
+	m.history[(m.histStart+m.histCount)%len(m.history)] = ev
+	m.histCount++
+	subs := make([]*Subscriber, 0, len(m.subs))
+	for sub := range m.subs {
+		if sub.filter == "" || sub.filter == procID {
+			subs = append(subs, sub)
+		}
+	}
+	m.mu.Unlock()
+	for _, sub := range subs {
+		sub.push(ev)
+	}
+}
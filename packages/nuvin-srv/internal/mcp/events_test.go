@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func collectEvents(t *testing.T, sub *Subscriber, n int) []Event {
+	t.Helper()
+	out := make([]Event, 0, n)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for len(out) < n {
+			ev, ok := sub.Next()
+			if !ok {
+				return
+			}
+			out = append(out, ev)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("got %d events, want %d", len(out), n)
+	}
+	return out
+}
+
+func TestSubscribersSeeLifecycleAndNotifications(t *testing.T) {
+	m := NewManager()
+	subA := m.Subscribe("", 0)
+	defer m.Unsubscribe(subA)
+	subB := m.Subscribe("", 0)
+	defer m.Unsubscribe(subB)
+
+	script := `printf '{"jsonrpc":"2.0","method":"log","params":{"msg":"hi"}}\n'`
+	p, err := m.Start(StartRequest{Name: "talker", Command: "bash", Args: []string{"-c", script}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	for _, sub := range []*Subscriber{subA, subB} {
+		events := collectEvents(t, sub, 3)
+		if events[0].Type != EventStarted || events[0].Process != p.ID {
+			t.Fatalf("events[0] = %+v", events[0])
+		}
+		if events[1].Type != EventNotification || events[1].Method != "log" {
+			t.Fatalf("events[1] = %+v", events[1])
+		}
+		if events[2].Type != EventExited {
+			t.Fatalf("events[2] = %+v", events[2])
+		}
+		var exit struct {
+			ExitCode int `json:"exitCode"`
+		}
+		if err := json.Unmarshal(events[2].Payload, &exit); err != nil || exit.ExitCode != 0 {
+			t.Fatalf("exit payload = %s", events[2].Payload)
+		}
+	}
+}
+
+func TestRingBufferDropsOldestOnly(t *testing.T) {
+	sub := newSubscriber("", 2)
+	for i := 1; i <= 5; i++ {
+		sub.push(Event{Seq: int64(i)})
+	}
+	first, ok := sub.Next()
+	if !ok || first.Seq != 4 {
+		t.Fatalf("first = %+v, want seq 4 after overflow", first)
+	}
+	second, ok := sub.Next()
+	if !ok || second.Seq != 5 {
+		t.Fatalf("second = %+v", second)
+	}
+
+	sub.Close()
+	if _, ok := sub.Next(); ok {
+		t.Fatal("Next returned event after close and drain")
+	}
+}
+
+func TestSubscribeFromReplaysRetainedEvents(t *testing.T) {
+	m := NewManager()
+	for i := 0; i < 5; i++ {
+		m.publish("mcp-one", EventNotification, "log", nil)
+	}
+
+	sub := m.SubscribeFrom("", 0, 2)
+	defer m.Unsubscribe(sub)
+	events := collectEvents(t, sub, 3)
+	if events[0].Seq != 3 || events[2].Seq != 5 {
+		t.Fatalf("replayed seqs = %d..%d, want 3..5", events[0].Seq, events[2].Seq)
+	}
+
+	// Live events keep flowing after the replay.
+	m.publish("mcp-one", EventNotification, "log", nil)
+	live := collectEvents(t, sub, 1)
+	if live[0].Seq != 6 {
+		t.Fatalf("live seq = %d, want 6", live[0].Seq)
+	}
+
+	// Replay honors the process filter.
+	m.publish("mcp-two", EventNotification, "log", nil)
+	filtered := m.SubscribeFrom("mcp-two", 0, 0)
+	defer m.Unsubscribe(filtered)
+	again := m.SubscribeFrom("mcp-two", 0, 1)
+	defer m.Unsubscribe(again)
+	got := collectEvents(t, again, 1)
+	if got[0].Process != "mcp-two" || got[0].Seq != 7 {
+		t.Fatalf("filtered replay = %+v", got[0])
+	}
+}
+
+func TestSubscriberFilterByProcess(t *testing.T) {
+	m := NewManager()
+	sub := m.Subscribe("mcp-other", 0)
+	defer m.Unsubscribe(sub)
+
+	m.publish("mcp-one", EventStarted, "", nil)
+	m.publish("mcp-other", EventStarted, "", nil)
+
+	ev, ok := sub.Next()
+	if !ok || ev.Process != "mcp-other" {
+		t.Fatalf("event = %+v, want only the filtered process", ev)
+	}
+}
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Framing modes. Newline-delimited JSON is the MCP default; Content-Length
+// is the LSP-style header framing some servers use.
+const (
+	FramingNDJSON        = "ndjson"
+	FramingContentLength = "content-length"
+)
+
+// writeFrame encodes one message in the given framing mode.
+func writeFrame(w io.Writer, framing string, msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	switch framing {
+	case "", FramingNDJSON:
+		data = append(data, '\n')
+	case FramingContentLength:
+		data = append([]byte(fmt.Sprintf("Content-Length: %d\r\n\r\n", len(data))), data...)
+	default:
+		return fmt.Errorf("unsupported framing: %q", framing)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// defaultMaxLine sizes the read buffer; it is also the chunk size for
+// oversized non-frame output.
+const defaultMaxLine = 1 << 20
+
+// frameReader decodes messages from a stream, auto-detecting header framing
+// versus newline JSON per message so mixed-mode servers still parse.
+// Non-frame stdout (log noise, binary data) goes to the output hook instead
+// of being silently dropped; oversized lines are emitted in buffer-sized
+// chunks rather than stalling the reader.
+type frameReader struct {
+	r *bufio.Reader
+	// output receives non-frame stdout chunks; nil discards them.
+	output func([]byte)
+}
+
+func newFrameReader(r io.Reader) *frameReader {
+	return newFrameReaderSize(r, 0)
+}
+
+// newFrameReaderSize overrides the max line length; 0 means 1MiB.
+func newFrameReaderSize(r io.Reader, maxLine int) *frameReader {
+	if maxLine <= 0 {
+		maxLine = defaultMaxLine
+	}
+	if maxLine < 4096 {
+		maxLine = 4096
+	}
+	return &frameReader{r: bufio.NewReaderSize(r, maxLine)}
+}
+
+// ReadMessage returns the next message, routing blank lines and non-JSON
+// noise a server may print on stdout to the output hook.
+func (f *frameReader) ReadMessage() (*Message, error) {
+	for {
+		peek, err := f.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		switch peek[0] {
+		case '{':
+			line, err := f.r.ReadBytes('\n')
+			if err != nil && len(line) == 0 {
+				return nil, err
+			}
+			var msg Message
+			if jerr := json.Unmarshal(line, &msg); jerr != nil {
+				f.emit(line)
+				continue // not a frame; keep scanning
+			}
+			return &msg, nil
+		case 'C':
+			return f.readContentLength()
+		default:
+			// Anything that can't start a frame is plain output. ReadSlice
+			// hands back a full buffer for lines longer than maxLine, which
+			// chunks them naturally.
+			line, err := f.r.ReadSlice('\n')
+			if len(line) > 0 {
+				f.emit(line)
+			}
+			if err != nil && err != bufio.ErrBufferFull {
+				return nil, err
+			}
+		}
+	}
+}
+
+// emit passes one non-frame chunk to the output hook, trimming the line
+// terminator.
+func (f *frameReader) emit(chunk []byte) {
+	if f.output == nil {
+		return
+	}
+	if n := len(chunk); n > 0 && chunk[n-1] == '\n' {
+		chunk = chunk[:n-1]
+	}
+	if len(chunk) == 0 {
+		return
+	}
+	out := make([]byte, len(chunk))
+	copy(out, chunk)
+	f.output(out)
+}
+
+func (f *frameReader) readContentLength() (*Message, error) {
+	length := -1
+	for {
+		line, err := f.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(name, "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %w", err)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(f.r, body); err != nil {
+		return nil, err
+	}
+	var msg Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC body: %w", err)
+	}
+	return &msg, nil
+}
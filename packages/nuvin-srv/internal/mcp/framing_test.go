@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWriteFrameNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	msg := &Message{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: "ping"}
+	if err := writeFrame(&buf, FramingNDJSON, msg); err != nil {
+		t.Fatalf("writeFrame: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "\n") || strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("frame = %q, want single newline-terminated line", buf.String())
+	}
+
+	got, err := newFrameReader(&buf).ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if got.Method != "ping" || string(got.ID) != "1" {
+		t.Fatalf("message = %+v", got)
+	}
+}
+
+func TestWriteFrameContentLength(t *testing.T) {
+	var buf bytes.Buffer
+	msg := &Message{JSONRPC: "2.0", ID: json.RawMessage("7"), Result: json.RawMessage(`{"ok":true}`)}
+	if err := writeFrame(&buf, FramingContentLength, msg); err != nil {
+		t.Fatalf("writeFrame: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "Content-Length: ") {
+		t.Fatalf("frame = %q", buf.String())
+	}
+
+	got, err := newFrameReader(&buf).ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if string(got.ID) != "7" || !got.isResponse() {
+		t.Fatalf("message = %+v", got)
+	}
+}
+
+func TestFrameReaderSkipsNoise(t *testing.T) {
+	body := `{"jsonrpc":"2.0","id":2,"result":{"a":1}}`
+	input := "starting up...\n\n" +
+		`{"jsonrpc":"2.0","method":"log","params":{}}` + "\n" +
+		fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+	r := newFrameReader(strings.NewReader(input))
+
+	first, err := r.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if first.Method != "log" {
+		t.Fatalf("first = %+v", first)
+	}
+	second, err := r.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if string(second.ID) != "2" {
+		t.Fatalf("second = %+v", second)
+	}
+}
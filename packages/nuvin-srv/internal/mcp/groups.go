@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Group names an ordered set of server definitions started and stopped
+// together. Order matters: StartGroup launches front to back so dependencies
+// come up first, StopGroup tears down back to front.
+type Group struct {
+	Name    string   `json:"name"`
+	Servers []string `json:"servers"`
+}
+
+// GroupResult reports the outcome for one server of a bulk operation.
+type GroupResult struct {
+	Server    string `json:"server"`
+	ProcessID string `json:"processId,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SaveGroup adds or replaces a group. Every member must already be defined.
+func (m *Manager) SaveGroup(g Group) error {
+	if g.Name == "" {
+		return fmt.Errorf("group name is required")
+	}
+	if len(g.Servers) == 0 {
+		return fmt.Errorf("group %q: at least one server is required", g.Name)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range g.Servers {
+		if _, ok := m.defs[name]; !ok {
+			return fmt.Errorf("group %q: unknown mcp server: %s", g.Name, name)
+		}
+	}
+	if m.groups == nil {
+		m.groups = make(map[string]Group)
+	}
+	m.groups[g.Name] = g
+	return nil
+}
+
+// DeleteGroup removes a group; its servers keep running.
+func (m *Manager) DeleteGroup(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.groups, name)
+}
+
+// Group returns a group by name.
+func (m *Manager) Group(name string) (Group, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g, ok := m.groups[name]
+	return g, ok
+}
+
+// Groups lists every group, sorted by name.
+func (m *Manager) Groups() []Group {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Group, 0, len(m.groups))
+	for _, g := range m.groups {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// StartGroup launches the group's servers in declared order, continuing past
+// individual failures so one broken server does not block the rest. A member
+// that is already running is reported as-is, not an error.
+func (m *Manager) StartGroup(name string) ([]GroupResult, error) {
+	g, ok := m.Group(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown mcp group: %s", name)
+	}
+	results := make([]GroupResult, 0, len(g.Servers))
+	for _, server := range g.Servers {
+		res := GroupResult{Server: server}
+		if p, err := m.StartServer(server); err != nil {
+			res.Error = err.Error()
+			m.mu.Lock()
+			if id, ok := m.byDef[server]; ok {
+				if running, ok := m.procs[id]; ok {
+					if status, _, _ := running.runState(); status == StatusRunning {
+						res.ProcessID = id
+						res.Error = ""
+					}
+				}
+			}
+			m.mu.Unlock()
+		} else {
+			res.ProcessID = p.ID
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// StopGroup stops the group's servers in reverse declared order, continuing
+// past members that are not running.
+func (m *Manager) StopGroup(name string) ([]GroupResult, error) {
+	g, ok := m.Group(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown mcp group: %s", name)
+	}
+	results := make([]GroupResult, 0, len(g.Servers))
+	for i := len(g.Servers) - 1; i >= 0; i-- {
+		res := GroupResult{Server: g.Servers[i]}
+		if err := m.StopServer(g.Servers[i]); err != nil {
+			res.Error = err.Error()
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import "testing"
+
+func TestGroupValidation(t *testing.T) {
+	m := NewManager()
+	if err := m.SaveGroup(Group{Servers: []string{"a"}}); err == nil {
+		t.Fatal("unnamed group accepted")
+	}
+	if err := m.SaveGroup(Group{Name: "empty"}); err == nil {
+		t.Fatal("empty group accepted")
+	}
+	if err := m.SaveGroup(Group{Name: "ghost", Servers: []string{"missing"}}); err == nil {
+		t.Fatal("group with undefined server accepted")
+	}
+}
+
+func TestStartGroupPartialFailure(t *testing.T) {
+	m := NewManager()
+	if err := m.SaveDef(ServerDef{Name: "good", Command: "sleep", Args: []string{"30"}}); err != nil {
+		t.Fatalf("SaveDef: %v", err)
+	}
+	if err := m.SaveDef(ServerDef{Name: "bad", Command: "/nonexistent-binary"}); err != nil {
+		t.Fatalf("SaveDef: %v", err)
+	}
+	if err := m.SaveGroup(Group{Name: "coding", Servers: []string{"good", "bad"}}); err != nil {
+		t.Fatalf("SaveGroup: %v", err)
+	}
+
+	results, err := m.StartGroup("coding")
+	if err != nil {
+		t.Fatalf("StartGroup: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+	if results[0].Server != "good" || results[0].Error != "" || results[0].ProcessID == "" {
+		t.Fatalf("good result = %+v", results[0])
+	}
+	if results[1].Server != "bad" || results[1].Error == "" {
+		t.Fatalf("bad result = %+v", results[1])
+	}
+
+	// Restarting the group leaves the running member untouched.
+	again, err := m.StartGroup("coding")
+	if err != nil {
+		t.Fatalf("StartGroup again: %v", err)
+	}
+	if again[0].Error != "" || again[0].ProcessID != results[0].ProcessID {
+		t.Fatalf("rerun result = %+v", again[0])
+	}
+
+	stopped, err := m.StopGroup("coding")
+	if err != nil {
+		t.Fatalf("StopGroup: %v", err)
+	}
+	// Reverse order: "bad" first (not running, reported), then "good".
+	if stopped[0].Server != "bad" || stopped[0].Error == "" {
+		t.Fatalf("stop results = %+v", stopped)
+	}
+	if stopped[1].Server != "good" || stopped[1].Error != "" {
+		t.Fatalf("stop results = %+v", stopped)
+	}
+
+	if _, err := m.StartGroup("nope"); err == nil {
+		t.Fatal("unknown group accepted")
+	}
+}
@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Health states. A process starts with no health until the first check runs.
+const (
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// Health check defaults.
+const (
+	defaultHealthInterval = 30 * time.Second
+	defaultHealthTimeout  = 5 * time.Second
+)
+
+// HealthConfig controls the periodic ping checker. IntervalMs 0 keeps the
+// checker disabled; TimeoutMs 0 means 5 seconds.
+type HealthConfig struct {
+	IntervalMs int `json:"intervalMs"`
+	TimeoutMs  int `json:"timeoutMs,omitempty"`
+}
+
+// SetHealthConfig replaces the checker configuration, restarting the periodic
+// loop (or stopping it when the interval is 0).
+func (m *Manager) SetHealthConfig(cfg HealthConfig) {
+	m.mu.Lock()
+	if m.healthStop != nil {
+		close(m.healthStop)
+		m.healthStop = nil
+	}
+	m.healthCfg = cfg
+	if cfg.IntervalMs > 0 {
+		stop := make(chan struct{})
+		m.healthStop = stop
+		go m.healthLoop(stop, time.Duration(cfg.IntervalMs)*time.Millisecond)
+	}
+	m.mu.Unlock()
+}
+
+// HealthConfig returns the current checker configuration.
+func (m *Manager) HealthConfig() HealthConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.healthCfg
+}
+
+func (m *Manager) healthLoop(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.CheckHealth()
+		}
+	}
+}
+
+// CheckHealth pings every running stdio process and HTTP connection once and
+// records the result. A server that answers at all — even with a JSON-RPC
+// error — counts as healthy; only a transport failure or timeout marks it
+// unhealthy. Health transitions are published to subscribers.
+func (m *Manager) CheckHealth() {
+	m.mu.Lock()
+	timeout := defaultHealthTimeout
+	if m.healthCfg.TimeoutMs > 0 {
+		timeout = time.Duration(m.healthCfg.TimeoutMs) * time.Millisecond
+	}
+	ids := make([]string, 0, len(m.procs)+len(m.httpConns))
+	for id, p := range m.procs {
+		if status, _, _ := p.runState(); status == StatusRunning {
+			ids = append(ids, id)
+		}
+	}
+	for id := range m.httpConns {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		_, err := m.Call(ctx, id, "ping", nil)
+		cancel()
+		health := HealthHealthy
+		if err != nil {
+			health = HealthUnhealthy
+		}
+		m.setHealth(id, health)
+	}
+}
+
+// setHealth records the state and emits a health event when it changes.
+func (m *Manager) setHealth(id, health string) {
+	changed := false
+	if p, ok := m.Get(id); ok {
+		p.mu.Lock()
+		changed = p.Health != health
+		p.Health = health
+		p.mu.Unlock()
+	} else {
+		m.mu.Lock()
+		if conn, ok := m.httpConns[id]; ok {
+			changed = conn.Health != health
+			conn.Health = health
+		}
+		m.mu.Unlock()
+	}
+	if changed {
+		payload, _ := json.Marshal(map[string]any{"health": health})
+		m.publish(id, EventHealth, "", payload)
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCheckHealthMarksResponsiveAndUnresponsive(t *testing.T) {
+	m := NewManager()
+	m.SetHealthConfig(HealthConfig{TimeoutMs: 500})
+	echo := startEcho(t, m)
+	mute, err := m.Start(StartRequest{Name: "mute", Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(mute.ID)
+
+	m.CheckHealth()
+
+	got, _ := m.Get(echo.ID)
+	if _, health, _ := got.runState(); health != HealthHealthy {
+		t.Fatalf("echo health = %q, want healthy", health)
+	}
+	got, _ = m.Get(mute.ID)
+	if _, health, _ := got.runState(); health != HealthUnhealthy {
+		t.Fatalf("mute health = %q, want unhealthy", health)
+	}
+}
+
+func TestHealthChangePublishesEvent(t *testing.T) {
+	m := NewManager()
+	m.SetHealthConfig(HealthConfig{TimeoutMs: 500})
+	mute, err := m.Start(StartRequest{Name: "mute", Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(mute.ID)
+
+	sub := m.Subscribe(mute.ID, 0)
+	defer m.Unsubscribe(sub)
+	m.CheckHealth()
+	m.CheckHealth() // unchanged state must not emit a second event
+
+	events := make(chan Event, 4)
+	go func() {
+		for {
+			ev, ok := sub.Next()
+			if !ok {
+				return
+			}
+			events <- ev
+		}
+	}()
+
+	var health []string
+	deadline := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case ev := <-events:
+			if ev.Type != EventHealth {
+				continue
+			}
+			var payload struct {
+				Health string `json:"health"`
+			}
+			if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+				t.Fatalf("payload %s: %v", ev.Payload, err)
+			}
+			health = append(health, payload.Health)
+		case <-time.After(300 * time.Millisecond):
+			done = true
+		case <-deadline:
+			done = true
+		}
+	}
+	if len(health) != 1 || health[0] != HealthUnhealthy {
+		t.Fatalf("health events = %v, want one unhealthy transition", health)
+	}
+}
+
+func TestSetHealthConfigRunsPeriodically(t *testing.T) {
+	m := NewManager()
+	echo := startEcho(t, m)
+
+	m.SetHealthConfig(HealthConfig{IntervalMs: 50, TimeoutMs: 500})
+	defer m.SetHealthConfig(HealthConfig{})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, _ := m.Get(echo.ID)
+		if _, health, _ := got.runState(); health == HealthHealthy {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("periodic checker never marked the process healthy")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,215 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConnectHTTPRequest describes an MCP server reachable over Streamable HTTP.
+type ConnectHTTPRequest struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Headers are sent with every request (e.g. Authorization).
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// HTTPConn is one Streamable HTTP session. Connection IDs use the "mcph-"
+// prefix; Call and the tool catalog accept them interchangeably with stdio
+// process IDs.
+type HTTPConn struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"startedAt"`
+	// Health is set by the periodic ping checker ("healthy"/"unhealthy").
+	Health string `json:"health,omitempty"`
+
+	headers   map[string]string
+	client    *http.Client
+	nextID    atomic.Int64
+	mu        sync.Mutex
+	sessionID string
+}
+
+// ConnectHTTP opens a session against a Streamable HTTP MCP server and runs
+// the initialize handshake immediately, capturing the session ID the server
+// assigns.
+func (m *Manager) ConnectHTTP(ctx context.Context, req ConnectHTTPRequest) (*HTTPConn, error) {
+	if req.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	c := &HTTPConn{
+		ID:        "mcph-" + hex.EncodeToString(buf),
+		Name:      req.Name,
+		URL:       req.URL,
+		Status:    StatusRunning,
+		StartedAt: time.Now().UTC(),
+		headers:   req.Headers,
+		client:    &http.Client{Timeout: 60 * time.Second},
+	}
+
+	params, _ := json.Marshal(map[string]any{
+		"protocolVersion": protocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "nuvin-srv", "version": "dev"},
+	})
+	id := json.RawMessage(strconv.FormatInt(c.nextID.Add(1), 10))
+	resp, err := m.roundTrip(ctx, c, &Message{JSONRPC: "2.0", ID: id, Method: "initialize", Params: params})
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", req.URL, err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("connecting to %s: %s", req.URL, resp.Error.Message)
+	}
+	if _, err := m.postMessage(ctx, c, &Message{JSONRPC: "2.0", Method: "notifications/initialized"}); err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", req.URL, err)
+	}
+
+	m.mu.Lock()
+	if m.httpConns == nil {
+		m.httpConns = make(map[string]*HTTPConn)
+	}
+	m.httpConns[c.ID] = c
+	m.mu.Unlock()
+	return c, nil
+}
+
+// callHTTP sends one request over the session and returns the correlated
+// response, relaying any interleaved SSE events.
+func (m *Manager) callHTTP(ctx context.Context, c *HTTPConn, method string, params json.RawMessage) (*Message, error) {
+	if c.Status != StatusRunning {
+		return nil, fmt.Errorf("mcp connection %s is %s", c.ID, c.Status)
+	}
+	id := json.RawMessage(strconv.FormatInt(c.nextID.Add(1), 10))
+	return m.roundTrip(ctx, c, &Message{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+}
+
+// postMessage delivers a message and drains the body without expecting a
+// response (notifications).
+func (m *Manager) postMessage(ctx context.Context, c *HTTPConn, msg *Message) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	for k, v := range c.headers {
+		httpReq.Header.Set(k, v)
+	}
+	c.mu.Lock()
+	if c.sessionID != "" {
+		httpReq.Header.Set("Mcp-Session-Id", c.sessionID)
+	}
+	c.mu.Unlock()
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
+		c.mu.Lock()
+		c.sessionID = sid
+		c.mu.Unlock()
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("upstream returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return resp, nil
+}
+
+// roundTrip posts a request and decodes the response, whether it arrives as
+// plain JSON or as an SSE stream.
+func (m *Manager) roundTrip(ctx context.Context, c *HTTPConn, msg *Message) (*Message, error) {
+	resp, err := m.postMessage(ctx, c, msg)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return readSSEResponse(resp.Body, msg.ID)
+	}
+	var out Message
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return &out, nil
+}
+
+// readSSEResponse scans an event stream until the response matching wantID
+// appears; other events (server notifications) are skipped.
+func readSSEResponse(r io.Reader, wantID json.RawMessage) (*Message, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data:") {
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+		var msg Message
+		payload := data.String()
+		data.Reset()
+		if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+			continue
+		}
+		if msg.isResponse() && idKey(msg.ID) == idKey(wantID) {
+			return &msg, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("event stream ended before response arrived")
+}
+
+// HTTPConnections lists the open sessions, newest first.
+func (m *Manager) HTTPConnections() []*HTTPConn {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*HTTPConn, 0, len(m.httpConns))
+	for _, c := range m.httpConns {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+	return out
+}
+
+// DisconnectHTTP closes a session.
+func (m *Manager) DisconnectHTTP(id string) error {
+	m.mu.Lock()
+	c, ok := m.httpConns[id]
+	delete(m.httpConns, id)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown mcp connection: %s", id)
+	}
+	c.Status = StatusStopped
+	return nil
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeStreamableServer implements just enough of the Streamable HTTP
+// transport: initialize returns JSON with a session ID, tools/list answers
+// over SSE with a notification interleaved before the response.
+func fakeStreamableServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch msg.Method {
+		case "initialize":
+			w.Header().Set("Mcp-Session-Id", "sess-123")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":%q}}`, msg.ID, protocolVersion)
+		case "notifications/initialized":
+			w.WriteHeader(http.StatusAccepted)
+		case "tools/list":
+			if r.Header.Get("Mcp-Session-Id") != "sess-123" {
+				http.Error(w, "missing session", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+			fmt.Fprintf(w, "data: {\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{\"tools\":[{\"name\":\"remote\",\"inputSchema\":{}}]}}\n\n", msg.ID)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{}}`, msg.ID)
+		}
+	}))
+}
+
+func TestConnectHTTPAndCall(t *testing.T) {
+	srv := fakeStreamableServer(t)
+	defer srv.Close()
+
+	m := NewManager()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := m.ConnectHTTP(ctx, ConnectHTTPRequest{Name: "remote", URL: srv.URL})
+	if err != nil {
+		t.Fatalf("ConnectHTTP: %v", err)
+	}
+	if conn.Status != StatusRunning {
+		t.Fatalf("status = %q", conn.Status)
+	}
+
+	resp, err := m.Call(ctx, conn.ID, "tools/list", nil)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	var result struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil || len(result.Tools) != 1 || result.Tools[0].Name != "remote" {
+		t.Fatalf("result = %s, err = %v", resp.Result, err)
+	}
+}
+
+func TestHTTPConnectionInCatalogAndDisconnect(t *testing.T) {
+	srv := fakeStreamableServer(t)
+	defer srv.Close()
+
+	m := NewManager()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := m.ConnectHTTP(ctx, ConnectHTTPRequest{Name: "remote", URL: srv.URL})
+	if err != nil {
+		t.Fatalf("ConnectHTTP: %v", err)
+	}
+
+	catalog := m.ListTools(ctx)
+	if len(catalog.Tools) != 1 || catalog.Tools[0].Server != conn.ID {
+		t.Fatalf("catalog = %+v", catalog)
+	}
+
+	if err := m.DisconnectHTTP(conn.ID); err != nil {
+		t.Fatalf("DisconnectHTTP: %v", err)
+	}
+	if _, err := m.Call(ctx, conn.ID, "ping", nil); err == nil {
+		t.Fatal("call after disconnect accepted")
+	}
+	if err := m.DisconnectHTTP(conn.ID); err == nil {
+		t.Fatal("double disconnect accepted")
+	}
+}
+
+func TestConnectHTTPFailsFast(t *testing.T) {
+	m := NewManager()
+	ctx := context.Background()
+	if _, err := m.ConnectHTTP(ctx, ConnectHTTPRequest{}); err == nil {
+		t.Fatal("empty url accepted")
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	if _, err := m.ConnectHTTP(ctx, ConnectHTTPRequest{URL: srv.URL}); err == nil {
+		t.Fatal("forbidden upstream accepted")
+	}
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// defaultIdleSweep is how often the reaper scans when no interval is set.
+const defaultIdleSweep = 30 * time.Second
+
+// IdleConfig controls the idle reaper. TimeoutMs 0 keeps it disabled;
+// SweepIntervalMs 0 means 30 seconds between scans.
+type IdleConfig struct {
+	TimeoutMs       int `json:"timeoutMs"`
+	SweepIntervalMs int `json:"sweepIntervalMs,omitempty"`
+}
+
+// SetIdleConfig replaces the reaper configuration, restarting the sweep loop
+// (or stopping it when the timeout is 0).
+func (m *Manager) SetIdleConfig(cfg IdleConfig) {
+	m.mu.Lock()
+	if m.idleStop != nil {
+		close(m.idleStop)
+		m.idleStop = nil
+	}
+	m.idleCfg = cfg
+	if cfg.TimeoutMs > 0 {
+		sweep := defaultIdleSweep
+		if cfg.SweepIntervalMs > 0 {
+			sweep = time.Duration(cfg.SweepIntervalMs) * time.Millisecond
+		}
+		stop := make(chan struct{})
+		m.idleStop = stop
+		go m.idleLoop(stop, sweep)
+	}
+	m.mu.Unlock()
+}
+
+// IdleConfig returns the current reaper configuration.
+func (m *Manager) IdleConfig() IdleConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.idleCfg
+}
+
+func (m *Manager) idleLoop(stop chan struct{}, sweep time.Duration) {
+	ticker := time.NewTicker(sweep)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.ReapIdle()
+		}
+	}
+}
+
+// ReapIdle stops every unpinned stdio process with no stdin/stdout activity
+// for longer than the configured timeout and reports the stopped IDs.
+func (m *Manager) ReapIdle() []string {
+	m.mu.Lock()
+	timeout := time.Duration(m.idleCfg.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	cutoff := time.Now().Add(-timeout)
+	var idle []string
+	for id, p := range m.procs {
+		p.mu.Lock()
+		stale := p.Status == StatusRunning && !p.Pinned && p.lastActivity.Before(cutoff)
+		p.mu.Unlock()
+		if stale {
+			idle = append(idle, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, id := range idle {
+		if err := m.Stop(id); err != nil {
+			continue
+		}
+		payload, _ := json.Marshal(map[string]any{"reason": "idle-timeout"})
+		m.publish(id, EventExited, "", payload)
+	}
+	return idle
+}
+
+// PinProcess exempts a process from idle reaping (or re-arms it).
+func (m *Manager) PinProcess(id string, pinned bool) error {
+	p, ok := m.Get(id)
+	if !ok {
+		return fmt.Errorf("unknown mcp process: %s", id)
+	}
+	p.mu.Lock()
+	p.Pinned = pinned
+	p.mu.Unlock()
+	return nil
+}
+
+// touch records stdin/stdout activity for idle accounting.
+func (p *Process) touch() {
+	p.mu.Lock()
+	p.lastActivity = time.Now()
+	p.mu.Unlock()
+}
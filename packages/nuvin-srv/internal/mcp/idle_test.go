@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReapIdleStopsStaleProcesses(t *testing.T) {
+	m := NewManager()
+	m.SetIdleConfig(IdleConfig{TimeoutMs: 50})
+	defer m.SetIdleConfig(IdleConfig{})
+
+	stale, err := m.Start(StartRequest{Name: "stale", Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	pinned, err := m.Start(StartRequest{Name: "pinned", Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(pinned.ID)
+	if err := m.PinProcess(pinned.ID, true); err != nil {
+		t.Fatalf("PinProcess: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	reaped := m.ReapIdle()
+	if len(reaped) != 1 || reaped[0] != stale.ID {
+		t.Fatalf("reaped = %v, want just %s", reaped, stale.ID)
+	}
+	if _, ok := m.Get(stale.ID); ok {
+		t.Fatal("stale process still tracked")
+	}
+	if got, ok := m.Get(pinned.ID); !ok || got.Status != StatusRunning {
+		t.Fatal("pinned process was reaped")
+	}
+}
+
+func TestActiveProcessSurvivesReap(t *testing.T) {
+	m := NewManager()
+	m.SetIdleConfig(IdleConfig{TimeoutMs: 200})
+	defer m.SetIdleConfig(IdleConfig{})
+
+	p := startEcho(t, m)
+	time.Sleep(120 * time.Millisecond)
+	if err := m.Notify(p.ID, "keepalive", nil); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	time.Sleep(120 * time.Millisecond)
+
+	if reaped := m.ReapIdle(); len(reaped) != 0 {
+		t.Fatalf("reaped = %v, want none", reaped)
+	}
+	if got, _ := m.Get(p.ID); got.Status != StatusRunning {
+		t.Fatalf("status = %q", got.Status)
+	}
+}
+
+func TestPinUnknownProcess(t *testing.T) {
+	m := NewManager()
+	if err := m.PinProcess("mcp-missing", true); err == nil {
+		t.Fatal("pin of unknown process accepted")
+	}
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// importedServer is one entry of the standard mcpServers config format used
+// by Claude Desktop, Cursor, and friends.
+type importedServer struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+}
+
+// ImportError records one entry that could not be registered or started.
+type ImportError struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// ImportResult summarizes an mcp.json import.
+type ImportResult struct {
+	Imported []string      `json:"imported"`
+	Started  []string      `json:"started,omitempty"`
+	Errors   []ImportError `json:"errors,omitempty"`
+}
+
+// ImportServers registers every server from an mcpServers-style config and
+// optionally starts them. Both the wrapped form ({"mcpServers": {...}}) and a
+// bare name-to-server map are accepted.
+func (m *Manager) ImportServers(config json.RawMessage, start bool) (*ImportResult, error) {
+	var wrapped struct {
+		MCPServers map[string]importedServer `json:"mcpServers"`
+	}
+	servers := map[string]importedServer{}
+	if err := json.Unmarshal(config, &wrapped); err == nil && len(wrapped.MCPServers) > 0 {
+		servers = wrapped.MCPServers
+	} else if err := json.Unmarshal(config, &servers); err != nil {
+		return nil, fmt.Errorf("invalid mcpServers config: %w", err)
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("config contains no servers")
+	}
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &ImportResult{Imported: []string{}}
+	for _, name := range names {
+		entry := servers[name]
+		def := ServerDef{
+			Name:    name,
+			Command: entry.Command,
+			Args:    entry.Args,
+			Env:     entry.Env,
+			Cwd:     entry.Cwd,
+		}
+		if err := m.SaveDef(def); err != nil {
+			result.Errors = append(result.Errors, ImportError{Name: name, Error: err.Error()})
+			continue
+		}
+		result.Imported = append(result.Imported, name)
+		if start {
+			if _, err := m.StartServer(name); err != nil {
+				result.Errors = append(result.Errors, ImportError{Name: name, Error: err.Error()})
+				continue
+			}
+			result.Started = append(result.Started, name)
+		}
+	}
+	return result, nil
+}
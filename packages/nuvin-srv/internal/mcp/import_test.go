@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestImportServersWrappedConfig(t *testing.T) {
+	m := NewManager()
+	config := json.RawMessage(`{
+		"mcpServers": {
+			"files": {"command": "npx", "args": ["-y", "server-filesystem", "/tmp"]},
+			"time": {"command": "uvx", "args": ["mcp-server-time"], "env": {"TZ": "UTC"}}
+		}
+	}`)
+	result, err := m.ImportServers(config, false)
+	if err != nil {
+		t.Fatalf("ImportServers: %v", err)
+	}
+	if len(result.Imported) != 2 || result.Imported[0] != "files" || result.Imported[1] != "time" {
+		t.Fatalf("imported = %+v", result.Imported)
+	}
+	def, ok := m.Def("time")
+	if !ok || def.Command != "uvx" || def.Env["TZ"] != "UTC" {
+		t.Fatalf("def = %+v", def)
+	}
+}
+
+func TestImportServersBareMapAndStart(t *testing.T) {
+	m := NewManager()
+	config := json.RawMessage(`{"echo": {"command": "sleep", "args": ["30"]}}`)
+	result, err := m.ImportServers(config, true)
+	if err != nil {
+		t.Fatalf("ImportServers: %v", err)
+	}
+	if len(result.Started) != 1 || result.Started[0] != "echo" {
+		t.Fatalf("started = %+v, errors = %+v", result.Started, result.Errors)
+	}
+	defer m.StopServer("echo")
+
+	servers := m.Servers()
+	if len(servers) != 1 || servers[0].Status != StatusRunning {
+		t.Fatalf("servers = %+v", servers)
+	}
+}
+
+func TestImportServersBadEntries(t *testing.T) {
+	m := NewManager()
+	if _, err := m.ImportServers(json.RawMessage(`"not an object"`), false); err == nil {
+		t.Fatal("non-object config accepted")
+	}
+	if _, err := m.ImportServers(json.RawMessage(`{}`), false); err == nil {
+		t.Fatal("empty config accepted")
+	}
+
+	config := json.RawMessage(`{"mcpServers": {"good": {"command": "true"}, "bad": {}}}`)
+	result, err := m.ImportServers(config, false)
+	if err != nil {
+		t.Fatalf("ImportServers: %v", err)
+	}
+	if len(result.Imported) != 1 || len(result.Errors) != 1 || result.Errors[0].Name != "bad" {
+		t.Fatalf("result = %+v", result)
+	}
+}
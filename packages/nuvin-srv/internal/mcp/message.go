@@ -0,0 +1,28 @@
+// Package mcp manages Model Context Protocol server processes and speaks
+// JSON-RPC 2.0 to them over stdio.
+package mcp
+
+import "encoding/json"
+
+// Message is one JSON-RPC 2.0 message: a request (ID+Method), a notification
+// (Method only), or a response (ID+Result/Error).
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the JSON-RPC error object.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// isResponse reports whether the message answers an earlier request.
+func (m *Message) isResponse() bool {
+	return len(m.ID) > 0 && m.Method == ""
+}
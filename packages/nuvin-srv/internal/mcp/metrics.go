@@ -0,0 +1,60 @@
+package mcp
+
+import "time"
+
+// ProcMetrics is one resource snapshot of a managed stdio process.
+type ProcMetrics struct {
+	Process string `json:"process"`
+	Name    string `json:"name,omitempty"`
+	Status  string `json:"status"`
+	// CPUPercent is the share of one core used since the previous snapshot;
+	// the first sample for a process reports 0.
+	CPUPercent float64 `json:"cpuPercent"`
+	RSSBytes   int64   `json:"rssBytes"`
+	OpenFDs    int     `json:"openFds"`
+	UptimeMs   int64   `json:"uptimeMs"`
+}
+
+// Metrics samples every running stdio process. CPU usage is computed from
+// the tick delta against the previous call, so callers polling on an
+// interval get a live percentage.
+func (m *Manager) Metrics() []ProcMetrics {
+	now := time.Now()
+	out := []ProcMetrics{}
+	for _, p := range m.List() {
+		status, _, _ := p.runState()
+		if status != StatusRunning || p.cmd.Process == nil {
+			continue
+		}
+		metrics := ProcMetrics{
+			Process:  p.ID,
+			Name:     p.Name,
+			Status:   status,
+			UptimeMs: now.Sub(p.StartedAt).Milliseconds(),
+		}
+		if sample, ok := readProcSample(p.cmd.Process.Pid); ok {
+			metrics.RSSBytes = sample.rssBytes
+			metrics.OpenFDs = sample.openFDs
+			p.mu.Lock()
+			if !p.lastSampleAt.IsZero() && sample.cpuTicks >= p.lastCPUTicks {
+				elapsed := now.Sub(p.lastSampleAt).Seconds()
+				if elapsed > 0 {
+					ticks := float64(sample.cpuTicks - p.lastCPUTicks)
+					metrics.CPUPercent = ticks / clockTicksPerSecond / elapsed * 100
+				}
+			}
+			p.lastCPUTicks = sample.cpuTicks
+			p.lastSampleAt = now
+			p.mu.Unlock()
+		}
+		out = append(out, metrics)
+	}
+	return out
+}
+
+// procSample is the raw per-PID reading from the platform layer.
+type procSample struct {
+	cpuTicks uint64
+	rssBytes int64
+	openFDs  int
+}
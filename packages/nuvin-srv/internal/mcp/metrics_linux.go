@@ -0,0 +1,49 @@
+//go:build linux
+
+package mcp
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// clockTicksPerSecond is the kernel's USER_HZ; 100 on every mainstream
+// Linux configuration.
+const clockTicksPerSecond = 100
+
+// readProcSample reads CPU ticks, RSS, and open descriptor count for a PID
+// from /proc.
+func readProcSample(pid int) (procSample, bool) {
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return procSample{}, false
+	}
+	// The comm field is parenthesized and may contain spaces; fields are
+	// counted after the closing paren. utime and stime are fields 14 and 15
+	// (1-based), i.e. offsets 11 and 12 after the paren.
+	rest := string(stat)
+	if i := strings.LastIndexByte(rest, ')'); i >= 0 {
+		rest = rest[i+2:]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) < 13 {
+		return procSample{}, false
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	sample := procSample{cpuTicks: utime + stime}
+
+	if statm, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/statm"); err == nil {
+		parts := strings.Fields(string(statm))
+		if len(parts) >= 2 {
+			if pages, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+				sample.rssBytes = pages * int64(os.Getpagesize())
+			}
+		}
+	}
+	if fds, err := os.ReadDir("/proc/" + strconv.Itoa(pid) + "/fd"); err == nil {
+		sample.openFDs = len(fds)
+	}
+	return sample, true
+}
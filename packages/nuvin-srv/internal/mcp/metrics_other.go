@@ -0,0 +1,13 @@
+//go:build !linux
+
+package mcp
+
+// clockTicksPerSecond matches the Linux value; unused when sampling is
+// unavailable.
+const clockTicksPerSecond = 100
+
+// readProcSample has no /proc to read on this platform; metrics report
+// zeros besides uptime.
+func readProcSample(pid int) (procSample, bool) {
+	return procSample{}, false
+}
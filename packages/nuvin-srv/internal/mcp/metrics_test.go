@@ -0,0 +1,70 @@
+//go:build linux
+
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricsReportsResourceUsage(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "idle", Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	metrics := m.Metrics()
+	if len(metrics) != 1 {
+		t.Fatalf("metrics = %+v, want one entry", metrics)
+	}
+	got := metrics[0]
+	if got.Process != p.ID || got.RSSBytes <= 0 || got.OpenFDs <= 0 {
+		t.Fatalf("metrics = %+v, want positive rss and fds", got)
+	}
+	if got.UptimeMs < 0 {
+		t.Fatalf("uptime = %d", got.UptimeMs)
+	}
+}
+
+func TestMetricsCPUDeltaForBusyProcess(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "busy", Command: "bash", Args: []string{"-c", "while :; do :; done"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	m.Metrics() // first sample anchors the delta
+	time.Sleep(300 * time.Millisecond)
+	metrics := m.Metrics()
+	if len(metrics) != 1 {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+	if metrics[0].CPUPercent <= 0 {
+		t.Fatalf("cpuPercent = %v, want > 0 for a busy loop", metrics[0].CPUPercent)
+	}
+}
+
+func TestMetricsSkipsExitedProcesses(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Command: "true"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, _ := m.Get(p.ID)
+		if status, _, _ := got.runState(); status == StatusExited {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("process never exited")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if metrics := m.Metrics(); len(metrics) != 0 {
+		t.Fatalf("metrics = %+v, want none for exited process", metrics)
+	}
+}
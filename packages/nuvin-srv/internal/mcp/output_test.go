@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrameReaderEmitsNonFrameOutput(t *testing.T) {
+	input := "plain log line\n{\"jsonrpc\":\"2.0\",\"method\":\"note\"}\nbroken{json\n"
+	var chunks []string
+	f := newFrameReader(strings.NewReader(input))
+	f.output = func(chunk []byte) { chunks = append(chunks, string(chunk)) }
+
+	msg, err := f.ReadMessage()
+	if err != nil || msg.Method != "note" {
+		t.Fatalf("msg = %+v, err = %v", msg, err)
+	}
+	if _, err := f.ReadMessage(); err == nil {
+		t.Fatal("expected EOF")
+	}
+	if len(chunks) != 2 || chunks[0] != "plain log line" || chunks[1] != "broken{json" {
+		t.Fatalf("chunks = %q", chunks)
+	}
+}
+
+func TestFrameReaderChunksOversizedLines(t *testing.T) {
+	long := strings.Repeat("x", 10000) + "\n"
+	var chunks []string
+	f := newFrameReaderSize(strings.NewReader(long), 4096)
+	f.output = func(chunk []byte) { chunks = append(chunks, string(chunk)) }
+
+	if _, err := f.ReadMessage(); err == nil {
+		t.Fatal("expected EOF")
+	}
+	total := 0
+	for _, c := range chunks {
+		if len(c) > 4096 {
+			t.Fatalf("chunk of %d bytes exceeds the max line", len(c))
+		}
+		total += len(c)
+	}
+	if total != 10000 {
+		t.Fatalf("recovered %d bytes, want 10000", total)
+	}
+}
+
+func TestBinaryOutputTaggedBase64(t *testing.T) {
+	m := NewManager()
+	sub := m.Subscribe("", 0)
+	defer m.Unsubscribe(sub)
+
+	p, err := m.Start(StartRequest{Name: "binary", Command: "bash", Args: []string{"-c", `printf 'ab\0cd\n'; sleep 30`}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	done := make(chan Event, 1)
+	go func() {
+		for {
+			ev, ok := sub.Next()
+			if !ok {
+				return
+			}
+			if ev.Type == EventOutput {
+				done <- ev
+				return
+			}
+		}
+	}()
+	select {
+	case ev := <-done:
+		var payload struct {
+			Data     string `json:"data"`
+			Encoding string `json:"encoding"`
+		}
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+			t.Fatalf("payload %s: %v", ev.Payload, err)
+		}
+		if payload.Encoding != "base64" || payload.Data != "YWIAY2Q=" {
+			t.Fatalf("payload = %+v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("output event never arrived")
+	}
+}
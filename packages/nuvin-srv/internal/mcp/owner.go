@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AccessibleBy reports whether the subject may interact with the process.
+// Shared processes (no owner) stay visible to everyone.
+func (p *Process) AccessibleBy(subject string) bool {
+	return p.Owner == "" || p.Owner == subject
+}
+
+// SetProcessQuota caps how many running stdio processes one owner may hold;
+// 0 disables the limit. Shared (unowned) processes are never counted.
+func (m *Manager) SetProcessQuota(maxPerOwner int) {
+	m.mu.Lock()
+	m.maxPerOwner = maxPerOwner
+	m.mu.Unlock()
+}
+
+// ProcessQuota returns the per-owner process cap.
+func (m *Manager) ProcessQuota() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.maxPerOwner
+}
+
+// checkOwnerQuota rejects a start that would push the owner past the cap.
+func (m *Manager) checkOwnerQuota(owner string) error {
+	if owner == "" {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.maxPerOwner <= 0 {
+		return nil
+	}
+	running := 0
+	for _, p := range m.procs {
+		if p.Owner != owner {
+			continue
+		}
+		if status, _, _ := p.runState(); status == StatusRunning {
+			running++
+		}
+	}
+	if running >= m.maxPerOwner {
+		return fmt.Errorf("process quota exceeded: %s already has %d running", owner, running)
+	}
+	return nil
+}
+
+// ListAccessible returns snapshots of the processes the subject may see,
+// newest first.
+func (m *Manager) ListAccessible(subject string) []ProcessInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]ProcessInfo, 0, len(m.procs))
+	for _, p := range m.procs {
+		if p.AccessibleBy(subject) {
+			out = append(out, p.Info())
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+	return out
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import "testing"
+
+func TestProcessQuotaPerOwner(t *testing.T) {
+	m := NewManager()
+	m.SetProcessQuota(2)
+
+	for i := 0; i < 2; i++ {
+		p, err := m.Start(StartRequest{Command: "sleep", Args: []string{"30"}, Owner: "alice"})
+		if err != nil {
+			t.Fatalf("Start %d: %v", i, err)
+		}
+		defer m.Stop(p.ID)
+	}
+	if _, err := m.Start(StartRequest{Command: "sleep", Args: []string{"30"}, Owner: "alice"}); err == nil {
+		t.Fatal("third process for alice accepted")
+	}
+
+	// Other owners and shared processes are unaffected.
+	p, err := m.Start(StartRequest{Command: "sleep", Args: []string{"30"}, Owner: "bob"})
+	if err != nil {
+		t.Fatalf("Start for bob: %v", err)
+	}
+	defer m.Stop(p.ID)
+	shared, err := m.Start(StartRequest{Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start shared: %v", err)
+	}
+	defer m.Stop(shared.ID)
+}
+
+func TestListAccessibleFiltersByOwner(t *testing.T) {
+	m := NewManager()
+	mine, err := m.Start(StartRequest{Command: "sleep", Args: []string{"30"}, Owner: "alice"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(mine.ID)
+	theirs, err := m.Start(StartRequest{Command: "sleep", Args: []string{"30"}, Owner: "bob"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(theirs.ID)
+	shared, err := m.Start(StartRequest{Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(shared.ID)
+
+	got := m.ListAccessible("alice")
+	if len(got) != 2 {
+		t.Fatalf("alice sees %d processes, want 2", len(got))
+	}
+	for _, p := range got {
+		if p.ID == theirs.ID {
+			t.Fatal("alice can see bob's process")
+		}
+	}
+	if !theirs.AccessibleBy("bob") || theirs.AccessibleBy("alice") || !shared.AccessibleBy("alice") {
+		t.Fatal("AccessibleBy mismatch")
+	}
+}
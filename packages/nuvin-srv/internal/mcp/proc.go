@@ -0,0 +1,469 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marschhuynh/nuvin-srv/internal/proctree"
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+// Process statuses.
+const (
+	StatusRunning = "running"
+	StatusExited  = "exited"
+	StatusStopped = "stopped"
+)
+
+// StartRequest describes an MCP server to launch over stdio.
+type StartRequest struct {
+	// Name is a human-readable label for the server.
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+	// Framing selects the wire format ("ndjson" default, "content-length").
+	Framing string `json:"framing,omitempty"`
+	// MaxLineBytes overrides the stdout line buffer (and the chunk size for
+	// oversized non-frame output); 0 means 1MiB.
+	MaxLineBytes int `json:"maxLineBytes,omitempty"`
+	// Owner is the subject the process belongs to, set server-side from the
+	// request identity; empty means shared (supervisor-launched).
+	Owner string `json:"-"`
+	// DefName and Restarts are set by the supervisor when launching from a
+	// stored definition; they must be on the process before its read loop
+	// starts, so they travel with the request.
+	DefName  string `json:"-"`
+	Restarts int    `json:"-"`
+}
+
+// Process is one running MCP server.
+type Process struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Command   string    `json:"command"`
+	Args      []string  `json:"args,omitempty"`
+	Framing   string    `json:"framing"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"startedAt"`
+	ExitCode  int       `json:"exitCode,omitempty"`
+	// DefName and Restarts are set for supervised processes launched from a
+	// stored server definition.
+	DefName  string `json:"defName,omitempty"`
+	Restarts int    `json:"restarts,omitempty"`
+	// Health is set by the periodic ping checker ("healthy"/"unhealthy").
+	Health string `json:"health,omitempty"`
+	// Owner namespaces the process to the subject that started it; empty
+	// means shared.
+	Owner string `json:"owner,omitempty"`
+	// Pinned exempts the process from idle reaping.
+	Pinned bool `json:"pinned,omitempty"`
+
+	cmd    *exec.Cmd
+	tree   *proctree.Tree
+	stdin  io.WriteCloser
+	nextID atomic.Int64
+
+	mu           sync.Mutex
+	pending      map[string]chan *Message
+	writes       chan writeReq
+	quit         chan struct{}
+	writesClosed bool
+	initialized  bool
+
+	// CPU sampling state for Metrics.
+	lastCPUTicks uint64
+	lastSampleAt time.Time
+
+	// lastActivity tracks stdin/stdout traffic for the idle reaper.
+	lastActivity time.Time
+
+	// maxLine is the stdout line buffer override from the start request.
+	maxLine int
+}
+
+// Manager starts, tracks, and talks to MCP stdio processes, and supervises
+// the ones launched from stored definitions.
+type Manager struct {
+	mu        sync.Mutex
+	procs     map[string]*Process
+	defs      map[string]ServerDef
+	byDef     map[string]string
+	httpConns map[string]*HTTPConn
+	subs      map[*Subscriber]struct{}
+	eventSeq  int64
+
+	healthCfg  HealthConfig
+	healthStop chan struct{}
+	idleCfg    IdleConfig
+	idleStop   chan struct{}
+
+	maxPerOwner  int
+	secrets      *secrets.Store
+	registry     RegistryConfig
+	relays       map[string]serverRequest
+	roots        []Root
+	resourceSubs map[string]map[string]struct{}
+	groups       map[string]Group
+
+	// Replay ring for SubscribeFrom.
+	history   []Event
+	histStart int
+	histCount int
+}
+
+// NewManager returns an empty process manager.
+func NewManager() *Manager {
+	return &Manager{procs: make(map[string]*Process)}
+}
+
+// Start launches the MCP server and begins reading its stdout frames.
+func (m *Manager) Start(req StartRequest) (*Process, error) {
+	if req.Command == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+	switch req.Framing {
+	case "", FramingNDJSON, FramingContentLength:
+	default:
+		return nil, fmt.Errorf("unsupported framing: %q", req.Framing)
+	}
+	if err := m.checkOwnerQuota(req.Owner); err != nil {
+		return nil, err
+	}
+
+	env, err := m.resolveEnvSecrets(req.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(req.Command, req.Args...)
+	cmd.Dir = req.Cwd
+	if len(env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("starting mcp server: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("starting mcp server: %w", err)
+	}
+	cmd.Stderr = io.Discard
+	// Servers started through npx/uvx wrap the real process; tree-based
+	// termination keeps Stop from orphaning it.
+	tree := proctree.Setup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting mcp server: %w", err)
+	}
+	tree.Adopt()
+
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	framing := req.Framing
+	if framing == "" {
+		framing = FramingNDJSON
+	}
+	p := &Process{
+		ID:           "mcp-" + hex.EncodeToString(buf),
+		Name:         req.Name,
+		Command:      req.Command,
+		Args:         req.Args,
+		Framing:      framing,
+		Status:       StatusRunning,
+		StartedAt:    time.Now().UTC(),
+		lastActivity: time.Now(),
+		cmd:          cmd,
+		tree:         tree,
+		Owner:        req.Owner,
+		DefName:      req.DefName,
+		Restarts:     req.Restarts,
+		stdin:        stdin,
+		pending:      make(map[string]chan *Message),
+		writes:       make(chan writeReq, 16),
+		quit:         make(chan struct{}),
+		maxLine:      req.MaxLineBytes,
+	}
+
+	m.mu.Lock()
+	m.procs[p.ID] = p
+	if p.DefName != "" {
+		if m.byDef == nil {
+			m.byDef = make(map[string]string)
+		}
+		m.byDef[p.DefName] = p.ID
+	}
+	m.mu.Unlock()
+
+	go p.writeLoop()
+	go m.readLoop(p, stdout)
+	m.publish(p.ID, EventStarted, "", nil)
+	return p, nil
+}
+
+// readLoop routes stdout frames to waiting callers until the process exits.
+func (m *Manager) readLoop(p *Process, stdout io.Reader) {
+	reader := newFrameReaderSize(stdout, p.maxLine)
+	reader.output = func(chunk []byte) {
+		p.touch()
+		m.publishOutput(p, chunk)
+	}
+	for {
+		msg, err := reader.ReadMessage()
+		if err != nil {
+			break
+		}
+		p.touch()
+		if msg.isResponse() {
+			p.mu.Lock()
+			ch := p.pending[idKey(msg.ID)]
+			delete(p.pending, idKey(msg.ID))
+			p.mu.Unlock()
+			if ch != nil {
+				ch <- msg
+			}
+			continue
+		}
+		if len(msg.ID) > 0 && msg.Method != "" {
+			// roots/list is answered from our own state; other
+			// server-initiated requests (sampling, elicitation) are parked
+			// for a client answer instead of being treated as notifications.
+			if msg.Method == "roots/list" {
+				go m.answerRootsList(p, msg.ID)
+				continue
+			}
+			m.relayServerRequest(p, msg)
+			continue
+		}
+		m.handleNotification(p, msg)
+	}
+
+	err := p.cmd.Wait()
+	p.tree.Close()
+	p.mu.Lock()
+	if p.Status == StatusRunning {
+		p.Status = StatusExited
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			p.ExitCode = exitErr.ExitCode()
+		}
+	}
+	// Fail anything still waiting for a response.
+	for id, ch := range p.pending {
+		delete(p.pending, id)
+		close(ch)
+	}
+	p.closeWritesLocked()
+	exited := p.Status == StatusExited
+	exitCode := p.ExitCode
+	p.mu.Unlock()
+
+	m.dropRelays(p.ID)
+	m.dropResourceSubs(p.ID)
+	if exited {
+		payload, _ := json.Marshal(map[string]any{"exitCode": exitCode})
+		m.publish(p.ID, EventExited, "", payload)
+	}
+	if exited && p.DefName != "" {
+		m.maybeRestart(p)
+	}
+}
+
+// handleNotification fans server-initiated messages out to subscribers.
+// Resource update notifications get their own event type so the UI can
+// live-refresh the referenced URI without inspecting every notification.
+func (m *Manager) handleNotification(p *Process, msg *Message) {
+	if msg.Method == "notifications/resources/updated" {
+		m.publish(p.ID, EventResource, msg.Method, msg.Params)
+		return
+	}
+	m.publish(p.ID, EventNotification, msg.Method, msg.Params)
+}
+
+// publishOutput surfaces non-frame stdout as an event, tagging chunks that
+// are not valid UTF-8 as base64 so binary data survives the JSON transport.
+func (m *Manager) publishOutput(p *Process, chunk []byte) {
+	encoding := "utf8"
+	data := string(chunk)
+	if !utf8.Valid(chunk) || bytes.IndexByte(chunk, 0) >= 0 {
+		encoding = "base64"
+		data = base64.StdEncoding.EncodeToString(chunk)
+	}
+	payload, _ := json.Marshal(map[string]string{"data": data, "encoding": encoding})
+	m.publish(p.ID, EventOutput, "", payload)
+}
+
+// ProcessInfo is the JSON view of a process. Handlers marshal this snapshot
+// rather than the live *Process, whose status fields the read loop, health
+// checker, and pinner keep mutating under p.mu.
+type ProcessInfo struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Command   string    `json:"command"`
+	Args      []string  `json:"args,omitempty"`
+	Framing   string    `json:"framing"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"startedAt"`
+	ExitCode  int       `json:"exitCode,omitempty"`
+	DefName   string    `json:"defName,omitempty"`
+	Restarts  int       `json:"restarts,omitempty"`
+	Health    string    `json:"health,omitempty"`
+	Owner     string    `json:"owner,omitempty"`
+	Pinned    bool      `json:"pinned,omitempty"`
+}
+
+// Info snapshots the process under its lock.
+func (p *Process) Info() ProcessInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return ProcessInfo{
+		ID:        p.ID,
+		Name:      p.Name,
+		Command:   p.Command,
+		Args:      p.Args,
+		Framing:   p.Framing,
+		Status:    p.Status,
+		StartedAt: p.StartedAt,
+		ExitCode:  p.ExitCode,
+		DefName:   p.DefName,
+		Restarts:  p.Restarts,
+		Health:    p.Health,
+		Owner:     p.Owner,
+		Pinned:    p.Pinned,
+	}
+}
+
+// runState reports the fields the read loop and health checker mutate,
+// under the process lock; callers holding only m.mu must go through this
+// instead of reading the fields directly.
+func (p *Process) runState() (status, health string, exitCode int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Status, p.Health, p.ExitCode
+}
+
+// idKey normalizes a raw JSON-RPC ID for map lookup.
+func idKey(raw json.RawMessage) string {
+	return string(raw)
+}
+
+// Call sends a request to the server with the given ID — a stdio process or
+// an HTTP connection — and waits for the matching response.
+func (m *Manager) Call(ctx context.Context, procID, method string, params json.RawMessage) (*Message, error) {
+	p, ok := m.Get(procID)
+	if !ok {
+		m.mu.Lock()
+		conn, connOK := m.httpConns[procID]
+		m.mu.Unlock()
+		if connOK {
+			return m.callHTTP(ctx, conn, method, params)
+		}
+		return nil, fmt.Errorf("unknown mcp process: %s", procID)
+	}
+
+	id := json.RawMessage(strconv.FormatInt(p.nextID.Add(1), 10))
+	msg := &Message{JSONRPC: "2.0", ID: id, Method: method, Params: params}
+
+	ch := make(chan *Message, 1)
+	p.mu.Lock()
+	if p.Status != StatusRunning {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("mcp process %s is %s", procID, p.Status)
+	}
+	p.pending[idKey(id)] = ch
+	p.mu.Unlock()
+	if err := p.send(msg); err != nil {
+		p.mu.Lock()
+		delete(p.pending, idKey(id))
+		p.mu.Unlock()
+		return nil, fmt.Errorf("writing request: %w", err)
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, fmt.Errorf("mcp process %s exited before responding", procID)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		delete(p.pending, idKey(id))
+		p.mu.Unlock()
+		// Best effort: tell the server the call was abandoned so it can stop
+		// working on it. Asynchronous so a wedged server can't block us here.
+		go func() {
+			params, _ := json.Marshal(map[string]any{"requestId": id})
+			_ = p.send(&Message{JSONRPC: "2.0", Method: "notifications/cancelled", Params: params})
+		}()
+		return nil, fmt.Errorf("call %s: %w", method, ctx.Err())
+	}
+}
+
+// Notify sends a notification (no response expected).
+func (m *Manager) Notify(procID, method string, params json.RawMessage) error {
+	p, ok := m.Get(procID)
+	if !ok {
+		return fmt.Errorf("unknown mcp process: %s", procID)
+	}
+	return p.send(&Message{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// Get returns a process by ID.
+func (m *Manager) Get(id string) (*Process, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.procs[id]
+	return p, ok
+}
+
+// List returns all processes sorted by start time, newest first.
+func (m *Manager) List() []*Process {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*Process, 0, len(m.procs))
+	for _, p := range m.procs {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+	return out
+}
+
+// Stop terminates the process and removes it from the manager.
+func (m *Manager) Stop(id string) error {
+	m.mu.Lock()
+	p, ok := m.procs[id]
+	if ok {
+		delete(m.procs, id)
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown mcp process: %s", id)
+	}
+
+	p.mu.Lock()
+	p.Status = StatusStopped
+	p.closeWritesLocked()
+	p.mu.Unlock()
+	p.stdin.Close()
+	if p.cmd.Process != nil {
+		_ = p.tree.Kill()
+	}
+	return nil
+}
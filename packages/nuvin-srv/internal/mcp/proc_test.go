@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// echoServer is a minimal JSON-RPC responder: every request gets a response
+// with the same ID.
+const echoServer = `while IFS= read -r line; do
+  id=$(printf '%s' "$line" | sed -n 's/.*"id":\([0-9]*\).*/\1/p')
+  if [ -n "$id" ]; then
+    printf '{"jsonrpc":"2.0","id":%s,"result":{"echo":true}}\n' "$id"
+  fi
+done`
+
+func startEcho(t *testing.T, m *Manager) *Process {
+	t.Helper()
+	p, err := m.Start(StartRequest{Name: "echo", Command: "bash", Args: []string{"-c", echoServer}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { m.Stop(p.ID) })
+	return p
+}
+
+func TestCallCorrelatesResponses(t *testing.T) {
+	m := NewManager()
+	p := startEcho(t, m)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		resp, err := m.Call(ctx, p.ID, "ping", nil)
+		if err != nil {
+			t.Fatalf("Call: %v", err)
+		}
+		var result struct {
+			Echo bool `json:"echo"`
+		}
+		if err := json.Unmarshal(resp.Result, &result); err != nil || !result.Echo {
+			t.Fatalf("result = %s, err = %v", resp.Result, err)
+		}
+	}
+}
+
+func TestCallTimesOut(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := m.Call(ctx, p.ID, "ping", nil); err == nil {
+		t.Fatal("expected timeout")
+	}
+}
+
+func TestCallAfterExit(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Command: "true"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, _ := m.Get(p.ID)
+		if status, _, _ := got.runState(); status == StatusExited {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("process never marked exited")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx := context.Background()
+	if _, err := m.Call(ctx, p.ID, "ping", nil); err == nil {
+		t.Fatal("call against exited process accepted")
+	}
+	if _, err := m.Call(ctx, "mcp-missing", "ping", nil); err == nil {
+		t.Fatal("call against unknown process accepted")
+	}
+}
+
+func TestStartValidation(t *testing.T) {
+	m := NewManager()
+	if _, err := m.Start(StartRequest{}); err == nil {
+		t.Fatal("empty command accepted")
+	}
+	if _, err := m.Start(StartRequest{Command: "true", Framing: "exotic"}); err == nil {
+		t.Fatal("unknown framing accepted")
+	}
+}
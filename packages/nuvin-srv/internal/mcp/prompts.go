@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// PromptArgument describes one argument a prompt accepts.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Prompt is one entry from a server's prompts/list, tagged with its origin.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+	Server      string           `json:"server"`
+	ServerName  string           `json:"serverName,omitempty"`
+}
+
+// PromptCatalog is the consolidated prompt list across every running server.
+type PromptCatalog struct {
+	Prompts []Prompt       `json:"prompts"`
+	Errors  []CatalogError `json:"errors,omitempty"`
+}
+
+// listedPrompts fetches prompts/list from an already-initialized server by ID.
+func (m *Manager) listedPrompts(ctx context.Context, id, name string) ([]Prompt, error) {
+	resp, err := m.Call(ctx, id, "prompts/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("prompts/list: %s", resp.Error.Message)
+	}
+	var result struct {
+		Prompts []Prompt `json:"prompts"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("prompts/list: %w", err)
+	}
+	for i := range result.Prompts {
+		result.Prompts[i].Server = id
+		result.Prompts[i].ServerName = name
+	}
+	return result.Prompts, nil
+}
+
+// ListPrompts builds the consolidated prompt catalog across all running
+// servers, both stdio processes and HTTP connections. Servers that fail
+// discovery are reported in Errors instead of failing the whole catalog.
+func (m *Manager) ListPrompts(ctx context.Context) *PromptCatalog {
+	catalog := &PromptCatalog{Prompts: []Prompt{}}
+	for _, p := range m.List() {
+		if status, _, _ := p.runState(); status != StatusRunning {
+			continue
+		}
+		if err := m.initialize(ctx, p); err != nil {
+			catalog.Errors = append(catalog.Errors, CatalogError{Server: p.ID, ServerName: p.Name, Error: err.Error()})
+			continue
+		}
+		prompts, err := m.listedPrompts(ctx, p.ID, p.Name)
+		if err != nil {
+			catalog.Errors = append(catalog.Errors, CatalogError{Server: p.ID, ServerName: p.Name, Error: err.Error()})
+			continue
+		}
+		catalog.Prompts = append(catalog.Prompts, prompts...)
+	}
+	for _, c := range m.HTTPConnections() {
+		if c.Status != StatusRunning {
+			continue
+		}
+		prompts, err := m.listedPrompts(ctx, c.ID, c.Name)
+		if err != nil {
+			catalog.Errors = append(catalog.Errors, CatalogError{Server: c.ID, ServerName: c.Name, Error: err.Error()})
+			continue
+		}
+		catalog.Prompts = append(catalog.Prompts, prompts...)
+	}
+	sort.Slice(catalog.Prompts, func(i, j int) bool {
+		if catalog.Prompts[i].Name != catalog.Prompts[j].Name {
+			return catalog.Prompts[i].Name < catalog.Prompts[j].Name
+		}
+		return catalog.Prompts[i].Server < catalog.Prompts[j].Server
+	})
+	return catalog
+}
+
+// GetPrompt proxies prompts/get to one server, returning the raw result
+// (description plus rendered messages).
+func (m *Manager) GetPrompt(ctx context.Context, server, name string, args map[string]string) (json.RawMessage, error) {
+	if name == "" {
+		return nil, fmt.Errorf("prompt name is required")
+	}
+	payload := map[string]any{"name": name}
+	if len(args) > 0 {
+		payload["arguments"] = args
+	}
+	params, _ := json.Marshal(payload)
+	resp, err := m.Call(ctx, server, "prompts/get", params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("prompts/get: %s", resp.Error.Message)
+	}
+	return resp.Result, nil
+}
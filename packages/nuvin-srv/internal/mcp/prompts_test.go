@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakePromptServer answers the discovery handshake with one prompt and
+// renders it on prompts/get.
+const fakePromptServer = `while IFS= read -r line; do
+  id=$(printf '%s' "$line" | sed -n 's/.*"id":\([0-9]*\).*/\1/p')
+  case "$line" in
+    *'"method":"initialize"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2024-11-05","serverInfo":{"name":"fake"}}}\n' "$id";;
+    *'"method":"prompts/list"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{"prompts":[{"name":"review","description":"code review","arguments":[{"name":"file","required":true}]}]}}\n' "$id";;
+    *'"method":"prompts/get"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{"messages":[{"role":"user","content":{"type":"text","text":"review main.go"}}]}}\n' "$id";;
+    *'"id"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{}}\n' "$id";;
+  esac
+done`
+
+func TestListPromptsBuildsCatalog(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "prompter", Command: "bash", Args: []string{"-c", fakePromptServer}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	catalog := m.ListPrompts(ctx)
+	if len(catalog.Errors) != 0 {
+		t.Fatalf("errors = %+v", catalog.Errors)
+	}
+	if len(catalog.Prompts) != 1 {
+		t.Fatalf("prompts = %+v, want one", catalog.Prompts)
+	}
+	prompt := catalog.Prompts[0]
+	if prompt.Name != "review" || prompt.Server != p.ID || prompt.ServerName != "prompter" {
+		t.Fatalf("prompt = %+v", prompt)
+	}
+	if len(prompt.Arguments) != 1 || prompt.Arguments[0].Name != "file" || !prompt.Arguments[0].Required {
+		t.Fatalf("arguments = %+v", prompt.Arguments)
+	}
+
+	result, err := m.GetPrompt(ctx, p.ID, "review", map[string]string{"file": "main.go"})
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if !strings.Contains(string(result), "review main.go") {
+		t.Fatalf("result = %s", result)
+	}
+
+	if _, err := m.GetPrompt(ctx, p.ID, "", nil); err == nil {
+		t.Fatal("empty prompt name accepted")
+	}
+}
@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// RegistryConfig points the manager at an MCP server registry: a JSON index
+// of installable servers.
+type RegistryConfig struct {
+	// URL serves the index, either {"servers": [...]} or a bare entry array.
+	URL string `json:"url"`
+	// InstallDir is where package installs land, one subdirectory per server;
+	// empty means a "nuvin-mcp" directory under the OS temp dir.
+	InstallDir string `json:"installDir,omitempty"`
+}
+
+// RegistryEntry describes one installable server from the index.
+type RegistryEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Runtime selects the installer and launcher: "npx", "uvx", or "pip".
+	Runtime string `json:"runtime"`
+	// Package is the npm or PyPI package; Version pins it when set.
+	Package string `json:"package"`
+	Version string `json:"version,omitempty"`
+	// Module overrides the python -m entry point for pip servers; it
+	// defaults to the package name.
+	Module string `json:"module,omitempty"`
+	// Args and Env are passed through to the registered server definition.
+	Args []string          `json:"args,omitempty"`
+	Env  map[string]string `json:"env,omitempty"`
+}
+
+// InstallResult reports a completed registry install.
+type InstallResult struct {
+	Name string    `json:"name"`
+	Def  ServerDef `json:"def"`
+	// ProcessID is set when the install request asked to start the server.
+	ProcessID string `json:"processId,omitempty"`
+}
+
+// SetRegistryConfig replaces the registry configuration.
+func (m *Manager) SetRegistryConfig(cfg RegistryConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.registry = cfg
+}
+
+// RegistryConfig reports the current registry configuration.
+func (m *Manager) RegistryConfig() RegistryConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.registry
+}
+
+// FetchRegistry downloads and decodes the index, sorted by name.
+func (m *Manager) FetchRegistry(ctx context.Context) ([]RegistryEntry, error) {
+	cfg := m.RegistryConfig()
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("no registry configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching registry: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching registry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching registry: %s returned %s", cfg.URL, resp.Status)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("fetching registry: %w", err)
+	}
+	var wrapped struct {
+		Servers []RegistryEntry `json:"servers"`
+	}
+	var entries []RegistryEntry
+	if err := json.Unmarshal(raw, &wrapped); err == nil && wrapped.Servers != nil {
+		entries = wrapped.Servers
+	} else if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("decoding registry index: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// InstallFromRegistry installs the named entry into the managed directory,
+// registers it as a stdio server definition, and optionally starts it.
+func (m *Manager) InstallFromRegistry(ctx context.Context, name string, start bool) (*InstallResult, error) {
+	entries, err := m.FetchRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var entry *RegistryEntry
+	for i := range entries {
+		if entries[i].Name == name {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("unknown registry server: %s", name)
+	}
+	if entry.Package == "" {
+		return nil, fmt.Errorf("registry server %s has no package", name)
+	}
+
+	dir := filepath.Join(m.installDir(), entry.Name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("installing %s: %w", name, err)
+	}
+	if bin, args, ok := installCommand(*entry, dir); ok {
+		cmd := exec.CommandContext(ctx, bin, args...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("installing %s: %w: %s", name, err, tail(out, 512))
+		}
+	}
+
+	def := launchDef(*entry, dir)
+	if err := m.SaveDef(def); err != nil {
+		return nil, err
+	}
+	result := &InstallResult{Name: entry.Name, Def: def}
+	if start {
+		p, err := m.StartServer(def.Name)
+		if err != nil {
+			return result, fmt.Errorf("starting %s after install: %w", name, err)
+		}
+		result.ProcessID = p.ID
+	}
+	return result, nil
+}
+
+func (m *Manager) installDir() string {
+	cfg := m.RegistryConfig()
+	if cfg.InstallDir != "" {
+		return cfg.InstallDir
+	}
+	return filepath.Join(os.TempDir(), "nuvin-mcp")
+}
+
+// installCommand builds the installer invocation for an entry; ok is false
+// for runtimes that resolve packages at launch time (uvx).
+func installCommand(entry RegistryEntry, dir string) (bin string, args []string, ok bool) {
+	switch entry.Runtime {
+	case "npx":
+		return "npm", []string{"install", "--prefix", dir, npmSpec(entry)}, true
+	case "pip":
+		return "python3", []string{"-m", "pip", "install", "--target", dir, pipSpec(entry)}, true
+	default:
+		return "", nil, false
+	}
+}
+
+// launchDef builds the server definition registered after an install.
+func launchDef(entry RegistryEntry, dir string) ServerDef {
+	def := ServerDef{Name: entry.Name, Env: entry.Env}
+	switch entry.Runtime {
+	case "npx":
+		def.Command = "npx"
+		def.Args = append([]string{"-y", "--prefix", dir, entry.Package}, entry.Args...)
+	case "pip":
+		module := entry.Module
+		if module == "" {
+			module = entry.Package
+		}
+		def.Command = "python3"
+		def.Args = append([]string{"-m", module}, entry.Args...)
+		if def.Env == nil {
+			def.Env = map[string]string{}
+		}
+		def.Env["PYTHONPATH"] = dir
+	default: // uvx and anything uvx-like resolves the package itself
+		def.Command = entry.Runtime
+		def.Args = append([]string{pipSpec(entry)}, entry.Args...)
+	}
+	return def
+}
+
+func npmSpec(entry RegistryEntry) string {
+	if entry.Version != "" {
+		return entry.Package + "@" + entry.Version
+	}
+	return entry.Package
+}
+
+func pipSpec(entry RegistryEntry) string {
+	if entry.Version != "" {
+		return entry.Package + "==" + entry.Version
+	}
+	return entry.Package
+}
+
+// tail clips installer output for error messages.
+func tail(b []byte, n int) string {
+	if len(b) > n {
+		b = b[len(b)-n:]
+	}
+	return string(b)
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const registryIndex = `{"servers":[
+  {"name":"files","runtime":"npx","package":"@example/files-mcp","version":"1.2.3","description":"file tools"},
+  {"name":"calc","runtime":"uvx","package":"calc-mcp","args":["--stdio"]}
+]}`
+
+func TestFetchRegistrySortsEntries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(registryIndex))
+	}))
+	defer srv.Close()
+
+	m := NewManager()
+	m.SetRegistryConfig(RegistryConfig{URL: srv.URL})
+	entries, err := m.FetchRegistry(context.Background())
+	if err != nil {
+		t.Fatalf("FetchRegistry: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != "calc" || entries[1].Name != "files" {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestFetchRegistryBareArray(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"calc","runtime":"uvx","package":"calc-mcp"}]`))
+	}))
+	defer srv.Close()
+
+	m := NewManager()
+	m.SetRegistryConfig(RegistryConfig{URL: srv.URL})
+	entries, err := m.FetchRegistry(context.Background())
+	if err != nil {
+		t.Fatalf("FetchRegistry: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "calc" {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestInstallFromRegistryRegistersDef(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(registryIndex))
+	}))
+	defer srv.Close()
+
+	m := NewManager()
+	// uvx resolves the package at launch, so install only registers the def.
+	m.SetRegistryConfig(RegistryConfig{URL: srv.URL, InstallDir: t.TempDir()})
+	result, err := m.InstallFromRegistry(context.Background(), "calc", false)
+	if err != nil {
+		t.Fatalf("InstallFromRegistry: %v", err)
+	}
+	def, ok := m.Def("calc")
+	if !ok {
+		t.Fatal("definition not registered")
+	}
+	if def.Command != "uvx" || len(def.Args) != 2 || def.Args[0] != "calc-mcp" || def.Args[1] != "--stdio" {
+		t.Fatalf("def = %+v", def)
+	}
+	if result.ProcessID != "" {
+		t.Fatalf("unexpected process: %s", result.ProcessID)
+	}
+
+	if _, err := m.InstallFromRegistry(context.Background(), "missing", false); err == nil {
+		t.Fatal("unknown entry accepted")
+	}
+}
+
+func TestInstallCommands(t *testing.T) {
+	bin, args, ok := installCommand(RegistryEntry{Runtime: "npx", Package: "p", Version: "2.0.0"}, "/managed/p")
+	if !ok || bin != "npm" || args[len(args)-1] != "p@2.0.0" {
+		t.Fatalf("npx install = %s %v", bin, args)
+	}
+	bin, args, ok = installCommand(RegistryEntry{Runtime: "pip", Package: "p", Version: "2.0.0"}, "/managed/p")
+	if !ok || bin != "python3" || args[len(args)-1] != "p==2.0.0" {
+		t.Fatalf("pip install = %s %v", bin, args)
+	}
+	if _, _, ok := installCommand(RegistryEntry{Runtime: "uvx", Package: "p"}, "/managed/p"); ok {
+		t.Fatal("uvx should have no install step")
+	}
+
+	def := launchDef(RegistryEntry{Runtime: "pip", Package: "p", Module: "p_server"}, "/managed/p")
+	if def.Command != "python3" || def.Args[1] != "p_server" || def.Env["PYTHONPATH"] != "/managed/p" {
+		t.Fatalf("pip def = %+v", def)
+	}
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// serverRequest is a server-initiated JSON-RPC request (sampling/createMessage,
+// elicitation/create, ...) parked until a client answers it.
+type serverRequest struct {
+	procID    string
+	id        json.RawMessage
+	method    string
+	createdAt time.Time
+}
+
+// relayServerRequest parks the request under a correlation ID and publishes it
+// to subscribers so the UI can answer via Respond.
+func (m *Manager) relayServerRequest(p *Process, msg *Message) {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	cid := "req-" + hex.EncodeToString(buf)
+
+	m.mu.Lock()
+	if m.relays == nil {
+		m.relays = make(map[string]serverRequest)
+	}
+	m.relays[cid] = serverRequest{
+		procID:    p.ID,
+		id:        msg.ID,
+		method:    msg.Method,
+		createdAt: time.Now().UTC(),
+	}
+	m.mu.Unlock()
+
+	payload, _ := json.Marshal(map[string]any{
+		"correlationId": cid,
+		"params":        msg.Params,
+	})
+	m.publish(p.ID, EventRequest, msg.Method, payload)
+}
+
+// RelayProcess returns the process a parked server request belongs to.
+func (m *Manager) RelayProcess(correlationID string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sr, ok := m.relays[correlationID]
+	return sr.procID, ok
+}
+
+// Respond answers a parked server request by correlation ID, writing the
+// response frame back to the originating process.
+func (m *Manager) Respond(correlationID string, result json.RawMessage, rpcErr *RPCError) error {
+	m.mu.Lock()
+	sr, ok := m.relays[correlationID]
+	if ok {
+		delete(m.relays, correlationID)
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown correlation id: %s", correlationID)
+	}
+	p, ok := m.Get(sr.procID)
+	if !ok {
+		return fmt.Errorf("unknown mcp process: %s", sr.procID)
+	}
+	return p.send(&Message{JSONRPC: "2.0", ID: sr.id, Result: result, Error: rpcErr})
+}
+
+// dropRelays discards parked requests for a process that is gone.
+func (m *Manager) dropRelays(procID string) {
+	m.mu.Lock()
+	for cid, sr := range m.relays {
+		if sr.procID == procID {
+			delete(m.relays, cid)
+		}
+	}
+	m.mu.Unlock()
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// samplingServer sends one sampling request at startup and acknowledges the
+// answer with a notification so the test can observe the full round trip.
+const samplingServer = `printf '{"jsonrpc":"2.0","id":99,"method":"sampling/createMessage","params":{"maxTokens":10}}\n'
+while IFS= read -r line; do
+  case "$line" in
+    *'"id":99'*) printf '{"jsonrpc":"2.0","method":"test/answered"}\n' ;;
+  esac
+done`
+
+func TestServerRequestRelayRoundTrip(t *testing.T) {
+	m := NewManager()
+	sub := m.Subscribe("", 0)
+	defer m.Unsubscribe(sub)
+
+	p, err := m.Start(StartRequest{Name: "sampler", Command: "bash", Args: []string{"-c", samplingServer}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	events := make(chan Event, 8)
+	go func() {
+		for {
+			ev, ok := sub.Next()
+			if !ok {
+				return
+			}
+			events <- ev
+		}
+	}()
+
+	var cid string
+	deadline := time.After(5 * time.Second)
+	for cid == "" {
+		select {
+		case ev := <-events:
+			if ev.Type != EventRequest {
+				continue
+			}
+			if ev.Method != "sampling/createMessage" {
+				t.Fatalf("method = %q", ev.Method)
+			}
+			var payload struct {
+				CorrelationID string          `json:"correlationId"`
+				Params        json.RawMessage `json:"params"`
+			}
+			if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+				t.Fatalf("payload %s: %v", ev.Payload, err)
+			}
+			cid = payload.CorrelationID
+		case <-deadline:
+			t.Fatal("request event never arrived")
+		}
+	}
+
+	if procID, ok := m.RelayProcess(cid); !ok || procID != p.ID {
+		t.Fatalf("RelayProcess = %q, %v", procID, ok)
+	}
+	if err := m.Respond(cid, json.RawMessage(`{"role":"assistant"}`), nil); err != nil {
+		t.Fatalf("Respond: %v", err)
+	}
+	if err := m.Respond(cid, nil, nil); err == nil {
+		t.Fatal("double respond accepted")
+	}
+
+	for {
+		select {
+		case ev := <-events:
+			if ev.Type == EventNotification && ev.Method == "test/answered" {
+				return
+			}
+		case <-deadline:
+			t.Fatal("server never acknowledged the response")
+		}
+	}
+}
+
+func TestRespondUnknownCorrelation(t *testing.T) {
+	m := NewManager()
+	if err := m.Respond("req-missing", nil, nil); err == nil {
+		t.Fatal("unknown correlation accepted")
+	}
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ResourceSubscription names one resource URI watched on one server.
+type ResourceSubscription struct {
+	Process string `json:"process"`
+	URI     string `json:"uri"`
+}
+
+// SubscribeResource asks the server to watch a resource URI; subsequent
+// notifications/resources/updated notifications surface on the event stream
+// as resource events.
+func (m *Manager) SubscribeResource(ctx context.Context, procID, uri string) error {
+	if uri == "" {
+		return fmt.Errorf("uri is required")
+	}
+	params, _ := json.Marshal(map[string]string{"uri": uri})
+	resp, err := m.Call(ctx, procID, "resources/subscribe", params)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("resources/subscribe: %s", resp.Error.Message)
+	}
+	m.mu.Lock()
+	if m.resourceSubs == nil {
+		m.resourceSubs = make(map[string]map[string]struct{})
+	}
+	if m.resourceSubs[procID] == nil {
+		m.resourceSubs[procID] = make(map[string]struct{})
+	}
+	m.resourceSubs[procID][uri] = struct{}{}
+	m.mu.Unlock()
+	return nil
+}
+
+// UnsubscribeResource stops watching a resource URI.
+func (m *Manager) UnsubscribeResource(ctx context.Context, procID, uri string) error {
+	params, _ := json.Marshal(map[string]string{"uri": uri})
+	resp, err := m.Call(ctx, procID, "resources/unsubscribe", params)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("resources/unsubscribe: %s", resp.Error.Message)
+	}
+	m.mu.Lock()
+	delete(m.resourceSubs[procID], uri)
+	m.mu.Unlock()
+	return nil
+}
+
+// ResourceSubscriptions lists the active watches, sorted by process then URI.
+func (m *Manager) ResourceSubscriptions() []ResourceSubscription {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []ResourceSubscription{}
+	for procID, uris := range m.resourceSubs {
+		for uri := range uris {
+			out = append(out, ResourceSubscription{Process: procID, URI: uri})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Process != out[j].Process {
+			return out[i].Process < out[j].Process
+		}
+		return out[i].URI < out[j].URI
+	})
+	return out
+}
+
+// dropResourceSubs discards the watches of a process that is gone.
+func (m *Manager) dropResourceSubs(procID string) {
+	m.mu.Lock()
+	delete(m.resourceSubs, procID)
+	m.mu.Unlock()
+}
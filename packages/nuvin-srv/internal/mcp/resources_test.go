@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resourceServer acknowledges subscribe/unsubscribe and emits one update
+// notification for the subscribed URI.
+const resourceServer = `while IFS= read -r line; do
+  id=$(printf '%s' "$line" | sed -n 's/.*"id":\([0-9]*\).*/\1/p')
+  case "$line" in
+    *'resources/subscribe'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{}}\n' "$id"
+      printf '{"jsonrpc":"2.0","method":"notifications/resources/updated","params":{"uri":"file:///watched.txt"}}\n'
+      ;;
+    *'resources/unsubscribe'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{}}\n' "$id"
+      ;;
+  esac
+done`
+
+func TestResourceSubscriptionRelay(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "resources", Command: "bash", Args: []string{"-c", resourceServer}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	sub := m.Subscribe(p.ID, 0)
+	defer m.Unsubscribe(sub)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.SubscribeResource(ctx, p.ID, "file:///watched.txt"); err != nil {
+		t.Fatalf("SubscribeResource: %v", err)
+	}
+	subs := m.ResourceSubscriptions()
+	if len(subs) != 1 || subs[0].URI != "file:///watched.txt" || subs[0].Process != p.ID {
+		t.Fatalf("subscriptions = %+v", subs)
+	}
+
+	done := make(chan Event, 1)
+	go func() {
+		for {
+			ev, ok := sub.Next()
+			if !ok {
+				return
+			}
+			if ev.Type == EventResource {
+				done <- ev
+				return
+			}
+		}
+	}()
+	select {
+	case ev := <-done:
+		if ev.Method != "notifications/resources/updated" || !strings.Contains(string(ev.Payload), "file:///watched.txt") {
+			t.Fatalf("event = %+v", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("resource update never surfaced")
+	}
+
+	if err := m.UnsubscribeResource(ctx, p.ID, "file:///watched.txt"); err != nil {
+		t.Fatalf("UnsubscribeResource: %v", err)
+	}
+	if subs := m.ResourceSubscriptions(); len(subs) != 0 {
+		t.Fatalf("subscriptions after unsubscribe = %+v", subs)
+	}
+
+	if err := m.SubscribeResource(ctx, p.ID, ""); err == nil {
+		t.Fatal("empty uri accepted")
+	}
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"encoding/json"
+)
+
+// Root is one workspace root advertised to MCP servers via roots/list.
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// FileRoot builds a root for a local directory path.
+func FileRoot(path, name string) Root {
+	return Root{URI: "file://" + path, Name: name}
+}
+
+// SetRoots replaces the advertised roots and notifies every running stdio
+// process via notifications/roots/list_changed so they re-query roots/list.
+func (m *Manager) SetRoots(roots []Root) {
+	m.mu.Lock()
+	m.roots = append([]Root(nil), roots...)
+	ids := make([]string, 0, len(m.procs))
+	for id, p := range m.procs {
+		if status, _, _ := p.runState(); status == StatusRunning {
+			ids = append(ids, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		_ = m.Notify(id, "notifications/roots/list_changed", nil)
+	}
+}
+
+// Roots returns the advertised roots.
+func (m *Manager) Roots() []Root {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Root(nil), m.roots...)
+}
+
+// answerRootsList replies to a server's roots/list request with the current
+// roots; these never go to the UI.
+func (m *Manager) answerRootsList(p *Process, id json.RawMessage) {
+	roots := m.Roots()
+	if roots == nil {
+		roots = []Root{}
+	}
+	result, _ := json.Marshal(map[string]any{"roots": roots})
+	_ = p.send(&Message{JSONRPC: "2.0", ID: id, Result: result})
+}
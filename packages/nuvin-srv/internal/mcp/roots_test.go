@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rootsClient asks for roots/list at startup and prints each received line
+// back as a notification param so the test can inspect the answer.
+const rootsClient = `printf '{"jsonrpc":"2.0","id":7,"method":"roots/list"}\n'
+while IFS= read -r line; do
+  case "$line" in
+    *'"roots"'*) printf '{"jsonrpc":"2.0","method":"test/roots","params":%s}\n' "$line" ;;
+  esac
+done`
+
+func TestRootsListAnswered(t *testing.T) {
+	m := NewManager()
+	m.SetRoots([]Root{FileRoot("/srv/project", "project")})
+
+	sub := m.Subscribe("", 0)
+	defer m.Unsubscribe(sub)
+	p, err := m.Start(StartRequest{Name: "rooter", Command: "bash", Args: []string{"-c", rootsClient}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	done := make(chan Event, 1)
+	go func() {
+		for {
+			ev, ok := sub.Next()
+			if !ok {
+				return
+			}
+			if ev.Type == EventNotification && ev.Method == "test/roots" {
+				done <- ev
+				return
+			}
+		}
+	}()
+	select {
+	case ev := <-done:
+		if !strings.Contains(string(ev.Payload), "file:///srv/project") {
+			t.Fatalf("payload = %s", ev.Payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("roots/list was never answered")
+	}
+}
+
+func TestSetRootsNotifiesRunningServers(t *testing.T) {
+	m := NewManager()
+	p := startEcho(t, m)
+
+	// The echo server ignores notifications; this only asserts the write
+	// path stays healthy and the stored roots round-trip.
+	m.SetRoots([]Root{{URI: "file:///a"}, {URI: "file:///b", Name: "b"}})
+	roots := m.Roots()
+	if len(roots) != 2 || roots[1].Name != "b" {
+		t.Fatalf("roots = %+v", roots)
+	}
+	if got, _ := m.Get(p.ID); got.Status != StatusRunning {
+		t.Fatalf("process status = %q after notify", got.Status)
+	}
+}
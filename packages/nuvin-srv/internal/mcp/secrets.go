@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+// secretScheme marks an env value as a reference into the server-side secret
+// store, e.g. "secret://github-token".
+const secretScheme = "secret://"
+
+// SetSecretStore attaches the store used to resolve secret:// env references.
+func (m *Manager) SetSecretStore(store *secrets.Store) {
+	m.mu.Lock()
+	m.secrets = store
+	m.mu.Unlock()
+}
+
+// resolveEnvSecrets replaces secret:// references with their stored values,
+// leaving plain values untouched. Unknown references fail the start so a
+// typo never launches a server with the literal reference in its env.
+func (m *Manager) resolveEnvSecrets(env map[string]string) (map[string]string, error) {
+	m.mu.Lock()
+	store := m.secrets
+	m.mu.Unlock()
+
+	resolved := make(map[string]string, len(env))
+	for k, v := range env {
+		name, ok := strings.CutPrefix(v, secretScheme)
+		if !ok {
+			resolved[k] = v
+			continue
+		}
+		if store == nil {
+			return nil, fmt.Errorf("env %s: no secret store configured", k)
+		}
+		value, ok := store.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("env %s: unknown secret %q", k, name)
+		}
+		resolved[k] = value
+	}
+	return resolved, nil
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+func TestResolveEnvSecrets(t *testing.T) {
+	m := NewManager()
+	store := secrets.NewStore()
+	if err := store.Set("api-key", "s3cret"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	m.SetSecretStore(store)
+
+	env, err := m.resolveEnvSecrets(map[string]string{
+		"API_KEY": "secret://api-key",
+		"PLAIN":   "value",
+	})
+	if err != nil {
+		t.Fatalf("resolveEnvSecrets: %v", err)
+	}
+	if env["API_KEY"] != "s3cret" || env["PLAIN"] != "value" {
+		t.Fatalf("env = %+v", env)
+	}
+
+	if _, err := m.resolveEnvSecrets(map[string]string{"X": "secret://missing"}); err == nil {
+		t.Fatal("unknown secret reference accepted")
+	}
+}
+
+func TestStartRejectsUnresolvableSecret(t *testing.T) {
+	m := NewManager()
+	_, err := m.Start(StartRequest{Command: "true", Env: map[string]string{"KEY": "secret://nope"}})
+	if err == nil {
+		t.Fatal("start with unresolvable secret accepted")
+	}
+	if !strings.Contains(err.Error(), "no secret store") {
+		t.Fatalf("err = %v", err)
+	}
+}
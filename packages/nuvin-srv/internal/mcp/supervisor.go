@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Restart policy defaults.
+const (
+	defaultMaxRestarts = 5
+	defaultBackoff     = 500 * time.Millisecond
+	maxBackoff         = 30 * time.Second
+)
+
+// RestartPolicy controls supervision of a defined server.
+type RestartPolicy struct {
+	// AutoRestart relaunches the server when it exits on its own; explicit
+	// stops never restart.
+	AutoRestart bool `json:"autoRestart,omitempty"`
+	// MaxRestarts caps consecutive relaunches; 0 means 5.
+	MaxRestarts int `json:"maxRestarts,omitempty"`
+	// BackoffMs is the first restart delay; it doubles per attempt, capped at
+	// 30 seconds. 0 means 500ms.
+	BackoffMs int `json:"backoffMs,omitempty"`
+}
+
+// ServerDef is a persisted MCP server definition the supervisor can launch.
+type ServerDef struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+	Framing string            `json:"framing,omitempty"`
+	Restart RestartPolicy     `json:"restart,omitempty"`
+}
+
+// ServerStatus pairs a definition with its current process, if any.
+type ServerStatus struct {
+	Def       ServerDef `json:"def"`
+	ProcessID string    `json:"processId,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Health    string    `json:"health,omitempty"`
+	Restarts  int       `json:"restarts"`
+}
+
+// SaveDef adds or replaces a server definition.
+func (m *Manager) SaveDef(def ServerDef) error {
+	if def.Name == "" {
+		return fmt.Errorf("server name is required")
+	}
+	if def.Command == "" {
+		return fmt.Errorf("server %q: command is required", def.Name)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.defs == nil {
+		m.defs = make(map[string]ServerDef)
+	}
+	m.defs[def.Name] = def
+	return nil
+}
+
+// DeleteDef removes a definition; a running instance keeps running but will
+// no longer be restarted.
+func (m *Manager) DeleteDef(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.defs, name)
+	delete(m.byDef, name)
+}
+
+// Def returns a definition by name.
+func (m *Manager) Def(name string) (ServerDef, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	def, ok := m.defs[name]
+	return def, ok
+}
+
+// Servers reports every definition with its live status, sorted by name.
+func (m *Manager) Servers() []ServerStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]ServerStatus, 0, len(m.defs))
+	for name, def := range m.defs {
+		st := ServerStatus{Def: def}
+		if id, ok := m.byDef[name]; ok {
+			if p, ok := m.procs[id]; ok {
+				st.ProcessID = p.ID
+				st.Status, st.Health, _ = p.runState()
+				st.Restarts = p.Restarts
+			}
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Def.Name < out[j].Def.Name })
+	return out
+}
+
+// StartServer launches a defined server under supervision.
+func (m *Manager) StartServer(name string) (*Process, error) {
+	def, ok := m.Def(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown mcp server: %s", name)
+	}
+	m.mu.Lock()
+	var running *Process
+	if id, ok := m.byDef[name]; ok {
+		running = m.procs[id]
+	}
+	m.mu.Unlock()
+	if running != nil {
+		if status, _, _ := running.runState(); status == StatusRunning {
+			return nil, fmt.Errorf("mcp server %s is already running", name)
+		}
+	}
+	return m.startFromDef(def, 0)
+}
+
+// StopServer stops the running instance of a defined server without
+// triggering a restart.
+func (m *Manager) StopServer(name string) error {
+	m.mu.Lock()
+	id, ok := m.byDef[name]
+	delete(m.byDef, name)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("mcp server %s is not running", name)
+	}
+	return m.Stop(id)
+}
+
+func (m *Manager) startFromDef(def ServerDef, restarts int) (*Process, error) {
+	// DefName and Restarts ride on the request so the process carries them
+	// before its read loop starts; Start also registers the byDef mapping.
+	return m.Start(StartRequest{
+		Name:     def.Name,
+		Command:  def.Command,
+		Args:     def.Args,
+		Env:      def.Env,
+		Cwd:      def.Cwd,
+		Framing:  def.Framing,
+		DefName:  def.Name,
+		Restarts: restarts,
+	})
+}
+
+// maybeRestart runs after a supervised process exits on its own and schedules
+// the relaunch per the definition's policy.
+func (m *Manager) maybeRestart(p *Process) {
+	m.mu.Lock()
+	def, ok := m.defs[p.DefName]
+	current := m.byDef[p.DefName]
+	m.mu.Unlock()
+	if !ok || !def.Restart.AutoRestart || current != p.ID {
+		return
+	}
+	maxRestarts := def.Restart.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = defaultMaxRestarts
+	}
+	if p.Restarts >= maxRestarts {
+		return
+	}
+
+	backoff := defaultBackoff
+	if def.Restart.BackoffMs > 0 {
+		backoff = time.Duration(def.Restart.BackoffMs) * time.Millisecond
+	}
+	backoff <<= p.Restarts
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	time.AfterFunc(backoff, func() {
+		m.mu.Lock()
+		_, ok := m.defs[p.DefName]
+		stillCurrent := m.byDef[p.DefName] == p.ID
+		if ok && stillCurrent {
+			delete(m.procs, p.ID)
+		}
+		m.mu.Unlock()
+		if !ok || !stillCurrent {
+			return
+		}
+		_, _ = m.startFromDef(def, p.Restarts+1)
+	})
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSupervisorRestartsCrashedServer(t *testing.T) {
+	m := NewManager()
+	err := m.SaveDef(ServerDef{
+		Name:    "flaky",
+		Command: "bash",
+		Args:    []string{"-c", "exit 1"},
+		Restart: RestartPolicy{AutoRestart: true, MaxRestarts: 2, BackoffMs: 10},
+	})
+	if err != nil {
+		t.Fatalf("SaveDef: %v", err)
+	}
+	if _, err := m.StartServer("flaky"); err != nil {
+		t.Fatalf("StartServer: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		servers := m.Servers()
+		if len(servers) != 1 {
+			t.Fatalf("servers = %+v", servers)
+		}
+		if servers[0].Restarts == 2 && servers[0].Status == StatusExited {
+			// Give the supervisor a moment to prove it stays at the cap.
+			time.Sleep(100 * time.Millisecond)
+			if got := m.Servers()[0].Restarts; got != 2 {
+				t.Fatalf("restarts = %d, want capped at 2", got)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server status = %+v, want 2 restarts", servers[0])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStopServerPreventsRestart(t *testing.T) {
+	m := NewManager()
+	err := m.SaveDef(ServerDef{
+		Name:    "steady",
+		Command: "sleep",
+		Args:    []string{"30"},
+		Restart: RestartPolicy{AutoRestart: true, BackoffMs: 10},
+	})
+	if err != nil {
+		t.Fatalf("SaveDef: %v", err)
+	}
+	p, err := m.StartServer("steady")
+	if err != nil {
+		t.Fatalf("StartServer: %v", err)
+	}
+	if _, err := m.StartServer("steady"); err == nil {
+		t.Fatal("second start accepted while running")
+	}
+
+	if err := m.StopServer("steady"); err != nil {
+		t.Fatalf("StopServer: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	servers := m.Servers()
+	if servers[0].ProcessID != "" {
+		t.Fatalf("server = %+v, want no live process after stop", servers[0])
+	}
+	if _, ok := m.Get(p.ID); ok {
+		t.Fatal("stopped process still tracked")
+	}
+}
+
+func TestSaveDefValidation(t *testing.T) {
+	m := NewManager()
+	if err := m.SaveDef(ServerDef{Command: "x"}); err == nil {
+		t.Fatal("missing name accepted")
+	}
+	if err := m.SaveDef(ServerDef{Name: "x"}); err == nil {
+		t.Fatal("missing command accepted")
+	}
+	if _, err := m.StartServer("ghost"); err == nil {
+		t.Fatal("unknown server started")
+	}
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ContentBlock is one structured content entry from a tools/call result.
+type ContentBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// ToolCallResult is the structured outcome of a tool invocation.
+type ToolCallResult struct {
+	Content []ContentBlock `json:"content"`
+	IsError bool           `json:"isError,omitempty"`
+}
+
+// CallTool routes a tools/call to the given server (stdio process or HTTP
+// connection). An empty server resolves the tool by name across the catalog
+// when it is unambiguous. Context cancellation sends the server a
+// notifications/cancelled so abandoned calls can stop working.
+func (m *Manager) CallTool(ctx context.Context, server, tool string, args json.RawMessage) (*ToolCallResult, error) {
+	if tool == "" {
+		return nil, fmt.Errorf("tool name is required")
+	}
+	if server == "" {
+		resolved, err := m.resolveToolServer(ctx, tool)
+		if err != nil {
+			return nil, err
+		}
+		server = resolved
+	}
+	if p, ok := m.Get(server); ok {
+		if err := m.initialize(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+
+	payload := map[string]any{"name": tool}
+	if len(args) > 0 {
+		payload["arguments"] = args
+	}
+	params, _ := json.Marshal(payload)
+	resp, err := m.Call(ctx, server, "tools/call", params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("tools/call: %s", resp.Error.Message)
+	}
+	var result ToolCallResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("tools/call: %w", err)
+	}
+	if result.Content == nil {
+		result.Content = []ContentBlock{}
+	}
+	return &result, nil
+}
+
+// resolveToolServer finds the single server offering a tool.
+func (m *Manager) resolveToolServer(ctx context.Context, tool string) (string, error) {
+	var matches []string
+	for _, t := range m.ListTools(ctx).Tools {
+		if t.Name == tool {
+			matches = append(matches, t.Server)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("unknown tool: %s", tool)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("tool %s is offered by %d servers; specify one", tool, len(matches))
+	}
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// fakeCallServer answers tools/call with content blocks; "slow" calls are
+// never answered, and a cancellation notification is acknowledged with a
+// marker notification.
+const fakeCallServer = `while IFS= read -r line; do
+  id=$(printf '%s' "$line" | sed -n 's/.*"id":\([0-9]*\).*/\1/p')
+  case "$line" in
+    *'"method":"initialize"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2024-11-05","serverInfo":{"name":"fake"}}}\n' "$id";;
+    *'"method":"tools/list"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{"tools":[{"name":"add","description":"adds"}]}}\n' "$id";;
+    *'"slow"'*)
+      : ;;
+    *'"method":"tools/call"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{"content":[{"type":"text","text":"3"}]}}\n' "$id";;
+    *'notifications/cancelled'*)
+      printf '{"jsonrpc":"2.0","method":"test/cancelled"}\n';;
+    *'"id"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{}}\n' "$id";;
+  esac
+done`
+
+func TestCallToolReturnsContentBlocks(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "calc", Command: "bash", Args: []string{"-c", fakeCallServer}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := m.CallTool(ctx, p.ID, "add", json.RawMessage(`{"a":1,"b":2}`))
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Type != "text" || result.Content[0].Text != "3" {
+		t.Fatalf("result = %+v", result)
+	}
+
+	// Routing by tool name alone works when the tool is unambiguous.
+	if _, err := m.CallTool(ctx, "", "add", nil); err != nil {
+		t.Fatalf("CallTool by name: %v", err)
+	}
+	if _, err := m.CallTool(ctx, "", "missing", nil); err == nil {
+		t.Fatal("unknown tool accepted")
+	}
+	if _, err := m.CallTool(ctx, p.ID, "", nil); err == nil {
+		t.Fatal("empty tool name accepted")
+	}
+}
+
+func TestCallToolTimeoutSendsCancellation(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "calc", Command: "bash", Args: []string{"-c", fakeCallServer}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	sub := m.Subscribe(p.ID, 0)
+	defer m.Unsubscribe(sub)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if _, err := m.CallTool(ctx, p.ID, "add", json.RawMessage(`{"mode":"slow"}`)); err == nil {
+		t.Fatal("expected timeout")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			ev, ok := sub.Next()
+			if !ok {
+				return
+			}
+			if ev.Type == EventNotification && ev.Method == "test/cancelled" {
+				close(done)
+				return
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server never saw the cancellation")
+	}
+}
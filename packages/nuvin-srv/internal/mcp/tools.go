@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// protocolVersion is the MCP revision this client speaks.
+const protocolVersion = "2024-11-05"
+
+// Tool is one entry from a server's tools/list, tagged with its origin.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+	Server      string          `json:"server"`
+	ServerName  string          `json:"serverName,omitempty"`
+}
+
+// CatalogError records a server that failed the discovery handshake.
+type CatalogError struct {
+	Server     string `json:"server"`
+	ServerName string `json:"serverName,omitempty"`
+	Error      string `json:"error"`
+}
+
+// Catalog is the consolidated tool list across every running server.
+type Catalog struct {
+	Tools  []Tool         `json:"tools"`
+	Errors []CatalogError `json:"errors,omitempty"`
+}
+
+// initialize performs the MCP handshake once per process.
+func (m *Manager) initialize(ctx context.Context, p *Process) error {
+	p.mu.Lock()
+	done := p.initialized
+	p.mu.Unlock()
+	if done {
+		return nil
+	}
+
+	params, _ := json.Marshal(map[string]any{
+		"protocolVersion": protocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "nuvin-srv", "version": "dev"},
+	})
+	resp, err := m.Call(ctx, p.ID, "initialize", params)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("initialize: %s", resp.Error.Message)
+	}
+	if err := m.Notify(p.ID, "notifications/initialized", nil); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.initialized = true
+	p.mu.Unlock()
+	return nil
+}
+
+// serverTools runs the handshake and tools/list against one stdio process.
+func (m *Manager) serverTools(ctx context.Context, p *Process) ([]Tool, error) {
+	if err := m.initialize(ctx, p); err != nil {
+		return nil, err
+	}
+	return m.listedTools(ctx, p.ID, p.Name)
+}
+
+// listedTools fetches tools/list from an already-initialized server by ID.
+func (m *Manager) listedTools(ctx context.Context, id, name string) ([]Tool, error) {
+	resp, err := m.Call(ctx, id, "tools/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("tools/list: %s", resp.Error.Message)
+	}
+	var result struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("tools/list: %w", err)
+	}
+	for i := range result.Tools {
+		result.Tools[i].Server = id
+		result.Tools[i].ServerName = name
+	}
+	return result.Tools, nil
+}
+
+// ListTools builds the consolidated catalog across all running servers, both
+// stdio processes and HTTP connections. Servers that fail discovery are
+// reported in Errors instead of failing the whole catalog.
+func (m *Manager) ListTools(ctx context.Context) *Catalog {
+	catalog := &Catalog{Tools: []Tool{}}
+	for _, p := range m.List() {
+		if status, _, _ := p.runState(); status != StatusRunning {
+			continue
+		}
+		tools, err := m.serverTools(ctx, p)
+		if err != nil {
+			catalog.Errors = append(catalog.Errors, CatalogError{Server: p.ID, ServerName: p.Name, Error: err.Error()})
+			continue
+		}
+		catalog.Tools = append(catalog.Tools, tools...)
+	}
+	for _, c := range m.HTTPConnections() {
+		if c.Status != StatusRunning {
+			continue
+		}
+		tools, err := m.listedTools(ctx, c.ID, c.Name)
+		if err != nil {
+			catalog.Errors = append(catalog.Errors, CatalogError{Server: c.ID, ServerName: c.Name, Error: err.Error()})
+			continue
+		}
+		catalog.Tools = append(catalog.Tools, tools...)
+	}
+	sort.Slice(catalog.Tools, func(i, j int) bool {
+		if catalog.Tools[i].Name != catalog.Tools[j].Name {
+			return catalog.Tools[i].Name < catalog.Tools[j].Name
+		}
+		return catalog.Tools[i].Server < catalog.Tools[j].Server
+	})
+	return catalog
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeToolServer answers the discovery handshake with one tool.
+const fakeToolServer = `while IFS= read -r line; do
+  id=$(printf '%s' "$line" | sed -n 's/.*"id":\([0-9]*\).*/\1/p')
+  case "$line" in
+    *'"method":"initialize"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2024-11-05","serverInfo":{"name":"fake"}}}\n' "$id";;
+    *'"method":"tools/list"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{"tools":[{"name":"add","description":"adds numbers","inputSchema":{"type":"object"}}]}}\n' "$id";;
+    *'"id"'*)
+      printf '{"jsonrpc":"2.0","id":%s,"result":{}}\n' "$id";;
+  esac
+done`
+
+func TestListToolsBuildsCatalog(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "calc", Command: "bash", Args: []string{"-c", fakeToolServer}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	catalog := m.ListTools(ctx)
+	if len(catalog.Errors) != 0 {
+		t.Fatalf("errors = %+v", catalog.Errors)
+	}
+	if len(catalog.Tools) != 1 {
+		t.Fatalf("tools = %+v, want one", catalog.Tools)
+	}
+	tool := catalog.Tools[0]
+	if tool.Name != "add" || tool.Server != p.ID || tool.ServerName != "calc" {
+		t.Fatalf("tool = %+v", tool)
+	}
+
+	// A second listing reuses the initialized session.
+	if catalog := m.ListTools(ctx); len(catalog.Tools) != 1 {
+		t.Fatalf("second catalog = %+v", catalog)
+	}
+}
+
+func TestListToolsReportsFailingServer(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "mute", Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	catalog := m.ListTools(ctx)
+	if len(catalog.Errors) != 1 || catalog.Errors[0].Server != p.ID {
+		t.Fatalf("errors = %+v, want the mute server reported", catalog.Errors)
+	}
+	if len(catalog.Tools) != 0 {
+		t.Fatalf("tools = %+v", catalog.Tools)
+	}
+}
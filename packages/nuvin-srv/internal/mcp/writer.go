@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// writeReq is one queued stdin write; done reports the write-and-flush result.
+type writeReq struct {
+	msg  *Message
+	done chan error
+}
+
+// writeLoop owns the process stdin: it frames each queued message, writes it,
+// and flushes before reporting back, so concurrent senders can never
+// interleave partial frames. It exits when the process shuts down; queued
+// senders are released through the quit channel instead.
+func (p *Process) writeLoop() {
+	w := bufio.NewWriter(p.stdin)
+	for {
+		select {
+		case req := <-p.writes:
+			err := writeFrame(w, p.Framing, req.msg)
+			if err == nil {
+				err = w.Flush()
+			}
+			req.done <- err
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// send queues a message for the writer goroutine and waits until it has been
+// written and flushed. The queue is bounded and the enqueue happens outside
+// p.mu: a server that stops reading stdin must never leave a sender blocked
+// on the channel while holding the lock Stop needs to kill it.
+func (p *Process) send(msg *Message) error {
+	done := make(chan error, 1)
+	p.mu.Lock()
+	if p.Status != StatusRunning || p.writesClosed {
+		status := p.Status
+		p.mu.Unlock()
+		return fmt.Errorf("mcp process %s is %s", p.ID, status)
+	}
+	p.lastActivity = time.Now()
+	p.mu.Unlock()
+
+	select {
+	case p.writes <- writeReq{msg: msg, done: done}:
+	case <-p.quit:
+		return fmt.Errorf("mcp process %s is shutting down", p.ID)
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-p.quit:
+		return fmt.Errorf("mcp process %s is shutting down", p.ID)
+	}
+}
+
+// closeWritesLocked shuts the writer goroutine down exactly once and releases
+// every queued or blocked sender. Callers must hold p.mu and must have
+// already moved Status off running.
+func (p *Process) closeWritesLocked() {
+	if !p.writesClosed {
+		p.writesClosed = true
+		close(p.quit)
+	}
+}
+
+// Send delivers a caller-supplied JSON-RPC message to a stdio process in
+// message mode: the payload is validated as JSON and framed/flushed atomically
+// by the writer goroutine.
+func (m *Manager) Send(procID string, raw json.RawMessage) error {
+	p, ok := m.Get(procID)
+	if !ok {
+		return fmt.Errorf("unknown mcp process: %s", procID)
+	}
+	var msg Message
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return fmt.Errorf("invalid JSON-RPC message: %w", err)
+	}
+	if msg.JSONRPC == "" {
+		msg.JSONRPC = "2.0"
+	}
+	return p.send(&msg)
+}
@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// seqEchoServer responds to each request with its ID and the seq from its
+// params, so a torn or interleaved frame surfaces as a parse failure or a
+// response landing on the wrong caller.
+const seqEchoServer = `while IFS= read -r line; do
+  id=$(printf '%s' "$line" | sed -n 's/.*"id":\([0-9]*\).*/\1/p')
+  seq=$(printf '%s' "$line" | sed -n 's/.*"seq":\([0-9]*\).*/\1/p')
+  if [ -n "$id" ]; then
+    printf '{"jsonrpc":"2.0","id":%s,"result":{"seq":%s}}\n' "$id" "$seq"
+  fi
+done`
+
+func TestConcurrentCallsNeverInterleave(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "echo", Command: "bash", Args: []string{"-c", seqEchoServer}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := m.Call(ctx, p.ID, "test/echo", json.RawMessage(fmt.Sprintf(`{"seq":%d}`, i)))
+			if err != nil {
+				errs <- err
+				return
+			}
+			var result struct {
+				Seq int `json:"seq"`
+			}
+			if err := json.Unmarshal(resp.Result, &result); err != nil {
+				errs <- fmt.Errorf("result %s: %w", resp.Result, err)
+				return
+			}
+			if result.Seq != i {
+				errs <- fmt.Errorf("seq = %d, want %d", result.Seq, i)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestSendValidatesMessageAndProcess(t *testing.T) {
+	m := NewManager()
+	if err := m.Send("mcp-missing", json.RawMessage(`{}`)); err == nil {
+		t.Fatal("send to unknown process succeeded")
+	}
+
+	p, err := m.Start(StartRequest{Command: "cat"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Stop(p.ID)
+
+	if err := m.Send(p.ID, json.RawMessage(`not json`)); err == nil {
+		t.Fatal("malformed message accepted")
+	}
+
+	sub := m.Subscribe(p.ID, 0)
+	defer m.Unsubscribe(sub)
+	if err := m.Send(p.ID, json.RawMessage(`{"method":"test/ping"}`)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	// cat echoes the notification back; it should arrive as an event.
+	done := make(chan Event, 1)
+	go func() {
+		for {
+			ev, ok := sub.Next()
+			if !ok {
+				return
+			}
+			if ev.Type == EventNotification {
+				done <- ev
+				return
+			}
+		}
+	}()
+	select {
+	case ev := <-done:
+		if ev.Method != "test/ping" {
+			t.Fatalf("method = %q", ev.Method)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification never echoed back")
+	}
+}
+
+func TestSendAfterStopFails(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Command: "cat"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := m.Stop(p.ID); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := p.send(&Message{JSONRPC: "2.0", Method: "test/ping"}); err == nil {
+		t.Fatal("send to stopped process succeeded")
+	}
+}
+
+// A server that never reads stdin must not wedge the manager: once the pipe
+// and the write queue are full, blocked senders have to release p.mu so Stop
+// can still kill the process and unblock them.
+func TestStopUnblocksSendersToWedgedServer(t *testing.T) {
+	m := NewManager()
+	p, err := m.Start(StartRequest{Name: "wedged", Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	payload, _ := json.Marshal(map[string]any{"data": string(make([]byte, 64*1024))})
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = m.Notify(p.ID, "notifications/flood", payload)
+		}()
+	}
+	// Give the senders time to fill the pipe and the queue.
+	time.Sleep(100 * time.Millisecond)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- m.Stop(p.ID) }()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop deadlocked behind blocked senders")
+	}
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("senders still blocked after Stop")
+	}
+}
@@ -0,0 +1,304 @@
+// Package oauth connects user accounts at external providers through the
+// authorization-code flow with PKCE, generalizing what the GitHub device
+// flow does for providers that don't offer device authorization.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider configures one OAuth provider. Known names (google, anthropic)
+// get their endpoints filled in automatically; anything else must supply
+// authUrl and tokenUrl explicitly.
+type Provider struct {
+	Name        string   `json:"name"`
+	ClientID    string   `json:"clientId"`
+	AuthURL     string   `json:"authUrl,omitempty"`
+	TokenURL    string   `json:"tokenUrl,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+	RedirectURI string   `json:"redirectUri,omitempty"`
+}
+
+// Token is a stored provider token. Access and refresh tokens stay
+// server-side; JSON serialization only carries the metadata the UI needs.
+type Token struct {
+	AccessToken  string    `json:"-"`
+	RefreshToken string    `json:"-"`
+	ExpiresAt    time.Time `json:"expiresAt,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	ObtainedAt   time.Time `json:"obtainedAt"`
+}
+
+// AuthRequest is what a client needs to send the user off to authorize.
+type AuthRequest struct {
+	URL   string `json:"url"`
+	State string `json:"state"`
+}
+
+// pending is an authorization awaiting its callback.
+type pending struct {
+	provider  string
+	verifier  string
+	createdAt time.Time
+}
+
+// Manager holds provider configs, in-flight authorizations, and the tokens
+// they produce.
+type Manager struct {
+	mu        sync.Mutex
+	providers map[string]Provider
+	pending   map[string]pending
+	tokens    map[string]Token
+
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewManager returns an empty manager.
+func NewManager() *Manager {
+	return &Manager{
+		providers: make(map[string]Provider),
+		pending:   make(map[string]pending),
+		tokens:    make(map[string]Token),
+		client:    &http.Client{Timeout: 15 * time.Second},
+		now:       time.Now,
+	}
+}
+
+// defaultEndpoints fills in the endpoints for well-known providers.
+func defaultEndpoints(p *Provider) {
+	switch p.Name {
+	case "google":
+		if p.AuthURL == "" {
+			p.AuthURL = "https://accounts.google.com/o/oauth2/v2/auth"
+		}
+		if p.TokenURL == "" {
+			p.TokenURL = "https://oauth2.googleapis.com/token"
+		}
+	case "anthropic":
+		if p.AuthURL == "" {
+			p.AuthURL = "https://claude.ai/oauth/authorize"
+		}
+		if p.TokenURL == "" {
+			p.TokenURL = "https://console.anthropic.com/v1/oauth/token"
+		}
+	}
+}
+
+// Register adds or replaces a provider config.
+func (m *Manager) Register(p Provider) error {
+	if p.Name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+	if p.ClientID == "" {
+		return fmt.Errorf("provider %q: clientId is required", p.Name)
+	}
+	defaultEndpoints(&p)
+	if p.AuthURL == "" || p.TokenURL == "" {
+		return fmt.Errorf("provider %q: authUrl and tokenUrl are required", p.Name)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.providers[p.Name] = p
+	return nil
+}
+
+// Providers lists registered configs with whether a token is stored.
+func (m *Manager) Providers() []map[string]any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]map[string]any, 0, len(m.providers))
+	for name, p := range m.providers {
+		_, connected := m.tokens[name]
+		out = append(out, map[string]any{
+			"name":      name,
+			"clientId":  p.ClientID,
+			"scopes":    p.Scopes,
+			"connected": connected,
+		})
+	}
+	return out
+}
+
+// Begin starts an authorization: it generates the state and PKCE verifier,
+// remembers them, and returns the URL to send the user to.
+func (m *Manager) Begin(provider string) (AuthRequest, error) {
+	m.mu.Lock()
+	p, ok := m.providers[provider]
+	m.mu.Unlock()
+	if !ok {
+		return AuthRequest{}, fmt.Errorf("unknown oauth provider %q", provider)
+	}
+	state, err := randomToken()
+	if err != nil {
+		return AuthRequest{}, err
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return AuthRequest{}, err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.ClientID},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	if len(p.Scopes) > 0 {
+		q.Set("scope", strings.Join(p.Scopes, " "))
+	}
+	if p.RedirectURI != "" {
+		q.Set("redirect_uri", p.RedirectURI)
+	}
+	m.mu.Lock()
+	m.pending[state] = pending{provider: provider, verifier: verifier, createdAt: m.now()}
+	m.mu.Unlock()
+	return AuthRequest{URL: p.AuthURL + "?" + q.Encode(), State: state}, nil
+}
+
+// Complete exchanges the callback's code for tokens and stores them.
+func (m *Manager) Complete(ctx context.Context, state, code string) (string, error) {
+	m.mu.Lock()
+	pend, ok := m.pending[state]
+	if ok {
+		delete(m.pending, state)
+	}
+	p := m.providers[pend.provider]
+	m.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("unknown or expired oauth state")
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {p.ClientID},
+		"code":          {code},
+		"code_verifier": {pend.verifier},
+	}
+	if p.RedirectURI != "" {
+		form.Set("redirect_uri", p.RedirectURI)
+	}
+	tok, err := m.exchange(ctx, p.TokenURL, form)
+	if err != nil {
+		return "", fmt.Errorf("oauth %s: %w", pend.provider, err)
+	}
+	m.mu.Lock()
+	m.tokens[pend.provider] = tok
+	m.mu.Unlock()
+	return pend.provider, nil
+}
+
+// Current returns a valid access token for the provider, refreshing with
+// the stored refresh token when the access token is expired.
+func (m *Manager) Current(ctx context.Context, provider string) (Token, error) {
+	m.mu.Lock()
+	tok, ok := m.tokens[provider]
+	p := m.providers[provider]
+	now := m.now()
+	m.mu.Unlock()
+	if !ok {
+		return Token{}, fmt.Errorf("no token stored for %q", provider)
+	}
+	if tok.ExpiresAt.IsZero() || now.Before(tok.ExpiresAt.Add(-time.Minute)) {
+		return tok, nil
+	}
+	if tok.RefreshToken == "" {
+		return Token{}, fmt.Errorf("token for %q expired and has no refresh token", provider)
+	}
+	fresh, err := m.exchange(ctx, p.TokenURL, url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {p.ClientID},
+		"refresh_token": {tok.RefreshToken},
+	})
+	if err != nil {
+		return Token{}, fmt.Errorf("oauth %s: refreshing token: %w", provider, err)
+	}
+	if fresh.RefreshToken == "" {
+		fresh.RefreshToken = tok.RefreshToken
+	}
+	m.mu.Lock()
+	m.tokens[provider] = fresh
+	m.mu.Unlock()
+	return fresh, nil
+}
+
+// Token returns the stored token without refreshing.
+func (m *Manager) Token(provider string) (Token, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tok, ok := m.tokens[provider]
+	return tok, ok
+}
+
+// Disconnect drops the stored token for a provider.
+func (m *Manager) Disconnect(provider string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, provider)
+}
+
+// exchange posts an OAuth token request and parses the response.
+func (m *Manager) exchange(ctx context.Context, tokenURL string, form url.Values) (Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Token{}, fmt.Errorf("token endpoint returned %d", resp.StatusCode)
+	}
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		Scope        string `json:"scope"`
+		Error        string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Token{}, err
+	}
+	if body.Error != "" {
+		return Token{}, fmt.Errorf("token endpoint returned %s", body.Error)
+	}
+	if body.AccessToken == "" {
+		return Token{}, fmt.Errorf("token endpoint returned no access token")
+	}
+	tok := Token{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		Scope:        body.Scope,
+		ObtainedAt:   m.now(),
+	}
+	if body.ExpiresIn > 0 {
+		tok.ExpiresAt = m.now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return tok, nil
+}
+
+// randomToken returns an unguessable URL-safe token.
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
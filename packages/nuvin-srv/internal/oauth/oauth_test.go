@@ -0,0 +1,119 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestBeginAndCompleteWithPKCE(t *testing.T) {
+	var sawVerifier, sawCode string
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawVerifier = r.FormValue("code_verifier")
+		sawCode = r.FormValue("code")
+		fmt.Fprint(w, `{"access_token":"at-1","refresh_token":"rt-1","expires_in":3600,"scope":"openid"}`)
+	}))
+	defer tokenSrv.Close()
+
+	m := NewManager()
+	if err := m.Register(Provider{
+		Name: "google", ClientID: "cid", TokenURL: tokenSrv.URL,
+		Scopes: []string{"openid"}, RedirectURI: "http://127.0.0.1:8787/oauth/google/callback",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := m.Begin("google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "cid" ||
+		q.Get("state") != req.State || q.Get("scope") != "openid" {
+		t.Fatalf("auth url query = %v", q)
+	}
+
+	provider, err := m.Complete(context.Background(), req.State, "authcode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider != "google" || sawCode != "authcode" {
+		t.Errorf("provider = %q, code = %q", provider, sawCode)
+	}
+	// The verifier sent at exchange must hash to the challenge we offered.
+	sum := sha256.Sum256([]byte(sawVerifier))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != q.Get("code_challenge") {
+		t.Error("verifier does not match the challenge")
+	}
+	if tok, ok := m.Token("google"); !ok || tok.AccessToken != "at-1" || tok.Scope != "openid" {
+		t.Errorf("stored token = %+v ok=%v", tok, ok)
+	}
+
+	// A state can only be redeemed once.
+	if _, err := m.Complete(context.Background(), req.State, "authcode"); err == nil {
+		t.Error("state redeemed twice")
+	}
+}
+
+func TestCurrentRefreshesExpiredToken(t *testing.T) {
+	var grants []string
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		grants = append(grants, r.FormValue("grant_type"))
+		if r.FormValue("grant_type") == "refresh_token" {
+			if r.FormValue("refresh_token") != "rt-1" {
+				t.Errorf("refresh_token = %q", r.FormValue("refresh_token"))
+			}
+			fmt.Fprint(w, `{"access_token":"at-2","expires_in":3600}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"at-1","refresh_token":"rt-1","expires_in":300}`)
+	}))
+	defer tokenSrv.Close()
+
+	m := NewManager()
+	m.Register(Provider{Name: "google", ClientID: "cid", TokenURL: tokenSrv.URL})
+	req, _ := m.Begin("google")
+	if _, err := m.Complete(context.Background(), req.State, "code"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Within the lifetime: no refresh.
+	if tok, err := m.Current(context.Background(), "google"); err != nil || tok.AccessToken != "at-1" {
+		t.Fatalf("token = %+v err=%v", tok, err)
+	}
+	// After expiry: refresh, keeping the old refresh token.
+	m.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+	tok, err := m.Current(context.Background(), "google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "at-2" || tok.RefreshToken != "rt-1" {
+		t.Errorf("token = %+v", tok)
+	}
+	if len(grants) != 2 || grants[1] != "refresh_token" {
+		t.Errorf("grants = %v", grants)
+	}
+}
+
+func TestRegisterDefaultsAndValidation(t *testing.T) {
+	m := NewManager()
+	if err := m.Register(Provider{Name: "google", ClientID: "cid"}); err != nil {
+		t.Fatalf("google defaults missing: %v", err)
+	}
+	if err := m.Register(Provider{Name: "custom", ClientID: "cid"}); err == nil {
+		t.Error("custom provider without endpoints accepted")
+	}
+	if err := m.Register(Provider{Name: "google"}); err == nil {
+		t.Error("provider without clientId accepted")
+	}
+}
@@ -0,0 +1,52 @@
+// Package outbound configures egress through corporate HTTP or SOCKS5
+// proxies for the transports nuvin-srv uses to reach upstream APIs.
+package outbound
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	xproxy "golang.org/x/net/proxy"
+)
+
+// Apply returns a copy of base that routes connections through proxyURL.
+// Supported schemes are http, https, and socks5 (with optional user:pass
+// credentials in the URL). An empty proxyURL returns base unchanged.
+func Apply(base *http.Transport, proxyURL string) (*http.Transport, error) {
+	if proxyURL == "" {
+		return base, nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outbound proxy URL %q: %w", proxyURL, err)
+	}
+
+	t := base.Clone()
+	switch u.Scheme {
+	case "http", "https":
+		t.Proxy = http.ProxyURL(u)
+	case "socks5":
+		var auth *xproxy.Auth
+		if u.User != nil {
+			password, _ := u.User.Password()
+			auth = &xproxy.Auth{User: u.User.Username(), Password: password}
+		}
+		dialer, err := xproxy.SOCKS5("tcp", u.Host, auth, xproxy.Direct)
+		if err != nil {
+			return nil, fmt.Errorf("building SOCKS5 dialer for %q: %w", u.Host, err)
+		}
+		t.Proxy = nil
+		t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if cd, ok := dialer.(xproxy.ContextDialer); ok {
+				return cd.DialContext(ctx, network, addr)
+			}
+			return dialer.Dial(network, addr)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported outbound proxy scheme %q (want http, https, or socks5)", u.Scheme)
+	}
+	return t, nil
+}
@@ -0,0 +1,54 @@
+package outbound
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// TLSOptions customizes upstream TLS for deployments with private PKI or
+// self-hosted LLM gateways.
+type TLSOptions struct {
+	// InsecureSkipVerify disables certificate verification entirely.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// CACertPEM adds PEM-encoded CA certificates trusted for this
+	// connection, replacing the system roots.
+	CACertPEM string `json:"caCertPem,omitempty"`
+	// ClientCertPEM and ClientKeyPEM enable mutual TLS.
+	ClientCertPEM string `json:"clientCertPem,omitempty"`
+	ClientKeyPEM  string `json:"clientKeyPem,omitempty"`
+	// ServerName overrides SNI / the name certificates are verified
+	// against.
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// Config builds a *tls.Config from the options.
+func (o *TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: o.InsecureSkipVerify,
+		ServerName:         o.ServerName,
+	}
+	if o.CACertPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(o.CACertPEM)) {
+			return nil, fmt.Errorf("caCertPem contains no valid certificates")
+		}
+		cfg.RootCAs = pool
+	}
+	if o.ClientCertPEM != "" || o.ClientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(o.ClientCertPEM), []byte(o.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// Key returns a stable cache key for transports built from these options.
+func (o *TLSOptions) Key() string {
+	if o == nil {
+		return ""
+	}
+	return fmt.Sprintf("%t|%s|%s|%s|%s", o.InsecureSkipVerify, o.CACertPEM, o.ClientCertPEM, o.ClientKeyPEM, o.ServerName)
+}
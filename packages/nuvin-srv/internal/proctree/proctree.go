@@ -0,0 +1,6 @@
+// Package proctree terminates a started command together with every process
+// it spawned. On Unix the child is placed in its own process group and the
+// group is signalled as a whole; on Windows the child is assigned to a
+// kill-on-close job object so the tree dies even when the direct child is a
+// shell or launcher that has long since forked the real worker.
+package proctree
@@ -0,0 +1,44 @@
+//go:build !windows
+
+package proctree
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// Tree tracks one started command's process group.
+type Tree struct {
+	cmd *exec.Cmd
+}
+
+// Setup places the child in its own process group so Kill can signal the
+// group as a whole. It must be called before cmd.Start.
+func Setup(cmd *exec.Cmd) *Tree {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	return &Tree{cmd: cmd}
+}
+
+// Adopt completes setup once the command has started. The group already
+// exists on Unix, so there is nothing to do.
+func (t *Tree) Adopt() {}
+
+// Kill terminates the process and every descendant still in its group.
+func (t *Tree) Kill() error {
+	p := t.cmd.Process
+	if p == nil {
+		return nil
+	}
+	// A negative PID addresses the whole group; fall back to the direct
+	// child if the group is already gone.
+	if err := syscall.Kill(-p.Pid, syscall.SIGKILL); err != nil {
+		return p.Kill()
+	}
+	return nil
+}
+
+// Close releases platform resources; none are held on Unix.
+func (t *Tree) Close() {}
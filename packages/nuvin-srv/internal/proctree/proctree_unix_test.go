@@ -0,0 +1,54 @@
+//go:build !windows
+
+package proctree
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestKillTakesDownGrandchildren(t *testing.T) {
+	// The shell backgrounds a sleep and prints its PID, mirroring a command
+	// that forks a worker.
+	cmd := exec.Command("sh", "-c", "sleep 60 & echo $!; wait")
+	tree := Setup(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("StdoutPipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	tree.Adopt()
+	defer tree.Close()
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading child pid: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		t.Fatalf("parsing child pid %q: %v", line, err)
+	}
+
+	if err := tree.Kill(); err != nil {
+		t.Fatalf("Kill: %v", err)
+	}
+	_ = cmd.Wait()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if err := syscall.Kill(pid, 0); err == syscall.ESRCH {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("grandchild %d still alive after Kill", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
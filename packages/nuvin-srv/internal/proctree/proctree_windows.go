@@ -0,0 +1,144 @@
+//go:build windows
+
+package proctree
+
+import (
+	"os/exec"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procCreateJobObjectW         = kernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = kernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = kernel32.NewProc("TerminateJobObject")
+)
+
+const (
+	jobObjectExtendedLimitInformation = 9
+	jobObjectLimitKillOnJobClose      = 0x2000
+
+	processTerminate = 0x0001
+	processSetQuota  = 0x0100
+)
+
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+type jobObjectExtendedLimitInfo struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+// Tree tracks one started command and the job object holding its process
+// tree.
+type Tree struct {
+	cmd *exec.Cmd
+
+	mu  sync.Mutex
+	job syscall.Handle
+}
+
+// Setup creates a kill-on-close job object for the command. It must be
+// called before cmd.Start; SysProcAttr settings applied elsewhere (cmd.exe
+// quoting) are left untouched. When job creation fails the Tree degrades to
+// killing the direct child only.
+func Setup(cmd *exec.Cmd) *Tree {
+	t := &Tree{cmd: cmd}
+	job, _, _ := procCreateJobObjectW.Call(0, 0)
+	if job == 0 {
+		return t
+	}
+	var info jobObjectExtendedLimitInfo
+	info.BasicLimitInformation.LimitFlags = jobObjectLimitKillOnJobClose
+	ret, _, _ := procSetInformationJobObject.Call(job,
+		jobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		unsafe.Sizeof(info))
+	if ret == 0 {
+		_ = syscall.CloseHandle(syscall.Handle(job))
+		return t
+	}
+	t.job = syscall.Handle(job)
+	return t
+}
+
+// Adopt assigns the started process to the job so descendants spawned from
+// here on are terminated with it.
+func (t *Tree) Adopt() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.job == 0 || t.cmd.Process == nil {
+		return
+	}
+	h, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(t.cmd.Process.Pid))
+	if err != nil {
+		t.dropJobLocked()
+		return
+	}
+	ret, _, _ := procAssignProcessToJobObject.Call(uintptr(t.job), uintptr(h))
+	_ = syscall.CloseHandle(h)
+	if ret == 0 {
+		t.dropJobLocked()
+	}
+}
+
+// Kill terminates the whole job, or just the direct child when no job is
+// attached.
+func (t *Tree) Kill() error {
+	t.mu.Lock()
+	job := t.job
+	t.job = 0
+	t.mu.Unlock()
+	if job != 0 {
+		ret, _, _ := procTerminateJobObject.Call(uintptr(job), 1)
+		_ = syscall.CloseHandle(job)
+		if ret != 0 {
+			return nil
+		}
+	}
+	if p := t.cmd.Process; p != nil {
+		return p.Kill()
+	}
+	return nil
+}
+
+// Close releases the job handle; kill-on-close reaps any processes still in
+// the job.
+func (t *Tree) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dropJobLocked()
+}
+
+func (t *Tree) dropJobLocked() {
+	if t.job != 0 {
+		_ = syscall.CloseHandle(t.job)
+		t.job = 0
+	}
+}
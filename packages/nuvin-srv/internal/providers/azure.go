@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// defaultAzureAPIVersion is used when a provider entry doesn't pin one.
+const defaultAzureAPIVersion = "2024-02-01"
+
+// AzureConfig holds the Azure OpenAI specifics for a provider entry. Azure
+// addresses deployments, not models, so OpenAI-style requests need their
+// model mapped to a deployment name and the call moved under
+// /openai/deployments/{deployment}/ with an api-version parameter.
+type AzureConfig struct {
+	// APIVersion is the api-version query parameter; empty uses the default.
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Deployments maps logical model names ("gpt-4o") to deployment names
+	// ("gpt4o-prod"). A model without an entry is forwarded as its own
+	// deployment name.
+	Deployments map[string]string `json:"deployments,omitempty"`
+}
+
+// WantsAdaptation reports whether requests through the named provider need
+// AdaptRequest before forwarding.
+func (r *Registry) WantsAdaptation(name string) bool {
+	p, ok := r.Get(name)
+	return ok && p.Kind == KindAzureOpenAI
+}
+
+// AdaptRequest rewrites an OpenAI-style request path for the named
+// provider. For Azure OpenAI the body's model picks the deployment, the
+// path moves under /openai/deployments/{deployment}/, and the api-version
+// parameter is appended; the returned tail includes the query. Other kinds
+// return the input unchanged.
+func (r *Registry) AdaptRequest(name, tail string, body []byte) (string, error) {
+	p, ok := r.Get(name)
+	if !ok {
+		return "", fmt.Errorf("unknown provider %q", name)
+	}
+	if p.Kind != KindAzureOpenAI {
+		return tail, nil
+	}
+	var req struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || req.Model == "" {
+		return "", fmt.Errorf("provider %q: request body has no model to map to a deployment", name)
+	}
+	deployment := req.Model
+	apiVersion := defaultAzureAPIVersion
+	if p.Azure != nil {
+		if d, ok := p.Azure.Deployments[req.Model]; ok {
+			deployment = d
+		}
+		if p.Azure.APIVersion != "" {
+			apiVersion = p.Azure.APIVersion
+		}
+	}
+	op := strings.TrimPrefix(tail, "v1/")
+	return fmt.Sprintf("openai/deployments/%s/%s?api-version=%s", deployment, op, apiVersion), nil
+}
+
+// NormalizeAzureError rewrites Azure's error body into the OpenAI error
+// shape clients of the facade expect. Azure nests a code and sometimes an
+// innererror; unparseable bodies come back unchanged.
+func NormalizeAzureError(body []byte) []byte {
+	var azure struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Status  int    `json:"status"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &azure); err != nil || azure.Error.Message == "" {
+		return body
+	}
+	out, err := json.Marshal(map[string]any{
+		"error": map[string]any{
+			"message": azure.Error.Message,
+			"type":    "azure_openai_error",
+			"code":    azure.Error.Code,
+		},
+	})
+	if err != nil {
+		return body
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+func newAzureRegistry(t *testing.T) *Registry {
+	t.Helper()
+	reg := NewRegistry(secrets.NewStore(), nil)
+	err := reg.Set(Provider{
+		Name: "az", Kind: KindAzureOpenAI,
+		Azure: &AzureConfig{
+			APIVersion:  "2024-06-01",
+			Deployments: map[string]string{"gpt-4o": "gpt4o-prod"},
+		},
+	}, "az-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+func TestAdaptRequestMapsDeployment(t *testing.T) {
+	reg := newAzureRegistry(t)
+	tail, err := reg.AdaptRequest("az", "v1/chat/completions", []byte(`{"model":"gpt-4o"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "openai/deployments/gpt4o-prod/chat/completions?api-version=2024-06-01"
+	if tail != want {
+		t.Errorf("tail = %q, want %q", tail, want)
+	}
+
+	// Unmapped models forward under their own name.
+	tail, err = reg.AdaptRequest("az", "v1/chat/completions", []byte(`{"model":"o3-mini"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(tail, "/deployments/o3-mini/") {
+		t.Errorf("tail = %q", tail)
+	}
+
+	if _, err := reg.AdaptRequest("az", "v1/chat/completions", []byte(`{}`)); err == nil {
+		t.Error("body without model accepted")
+	}
+}
+
+func TestAdaptRequestPassThroughForOtherKinds(t *testing.T) {
+	reg := NewRegistry(secrets.NewStore(), nil)
+	reg.Set(Provider{Name: "oa", Kind: KindOpenAI}, "sk")
+	tail, err := reg.AdaptRequest("oa", "v1/chat/completions", []byte(`{"model":"gpt-4o"}`))
+	if err != nil || tail != "v1/chat/completions" {
+		t.Errorf("tail = %q, err = %v", tail, err)
+	}
+	if reg.WantsAdaptation("oa") {
+		t.Error("openai provider wants adaptation")
+	}
+}
+
+func TestSetRejectsAzureConfigOnOtherKinds(t *testing.T) {
+	reg := NewRegistry(secrets.NewStore(), nil)
+	err := reg.Set(Provider{Name: "oa", Kind: KindOpenAI, Azure: &AzureConfig{}}, "sk")
+	if err == nil {
+		t.Fatal("azure config accepted on an openai provider")
+	}
+}
+
+func TestNormalizeAzureError(t *testing.T) {
+	out := NormalizeAzureError([]byte(`{"error":{"code":"DeploymentNotFound","message":"no such deployment","status":404}}`))
+	want := `{"error":{"code":"DeploymentNotFound","message":"no such deployment","type":"azure_openai_error"}}`
+	if string(out) != want {
+		t.Errorf("out = %s", out)
+	}
+	// Bodies that aren't Azure errors pass through untouched.
+	raw := []byte(`not json`)
+	if got := NormalizeAzureError(raw); string(got) != "not json" {
+		t.Errorf("out = %s", got)
+	}
+}
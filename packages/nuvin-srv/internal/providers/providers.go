@@ -0,0 +1,240 @@
+// Package providers registers the upstream LLM providers a deployment can
+// talk to, holding their API keys server-side so proxy routes can inject
+// credentials without clients ever seeing them.
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/marschhuynh/nuvin-srv/internal/github"
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+	"github.com/marschhuynh/nuvin-srv/internal/urlcheck"
+)
+
+// Supported provider kinds. The kind decides which auth header carries the
+// key and which endpoint a validation ping probes.
+const (
+	KindOpenAI      = "openai"
+	KindAnthropic   = "anthropic"
+	KindAzureOpenAI = "azure-openai"
+	KindCopilot     = "copilot"
+	KindOllama      = "ollama"
+	KindOpenRouter  = "openrouter"
+)
+
+// Provider is one registered upstream. The API key itself lives in the
+// secret store and never appears in listings; HasKey reports its presence.
+type Provider struct {
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	BaseURL      string `json:"baseUrl,omitempty"`
+	DefaultModel string `json:"defaultModel,omitempty"`
+	QuotaNote    string `json:"quotaNote,omitempty"`
+	// Azure carries deployment mapping and api-version settings; only
+	// meaningful (and only accepted) on kind azure-openai.
+	Azure  *AzureConfig `json:"azure,omitempty"`
+	HasKey bool         `json:"hasKey"`
+}
+
+// Registry is the provider table. Keys are kept in the shared secret store
+// under "provider/<name>"; Copilot entries borrow their bearer token from
+// the GitHub token manager instead of a stored key.
+type Registry struct {
+	mu        sync.Mutex
+	providers map[string]Provider
+
+	secrets *secrets.Store
+	copilot *github.TokenManager
+	client  *http.Client
+}
+
+// NewRegistry returns a registry backed by store; copilot may be nil when
+// GitHub integration is not configured.
+func NewRegistry(store *secrets.Store, copilot *github.TokenManager) *Registry {
+	return &Registry{
+		providers: make(map[string]Provider),
+		secrets:   store,
+		copilot:   copilot,
+		client:    &http.Client{},
+	}
+}
+
+// secretName is the key's slot in the secret store.
+func secretName(provider string) string { return "provider/" + provider }
+
+// Set adds or replaces a provider. An empty apiKey keeps any key already
+// stored, so metadata edits don't force re-entering credentials.
+func (r *Registry) Set(p Provider, apiKey string) error {
+	if p.Name == "" {
+		return fmt.Errorf("provider name is required")
+	}
+	if strings.ContainsAny(p.Name, "/ ") {
+		return fmt.Errorf("provider %q: name must not contain slashes or spaces", p.Name)
+	}
+	switch p.Kind {
+	case KindOpenAI, KindAnthropic, KindAzureOpenAI, KindCopilot, KindOllama, KindOpenRouter:
+	default:
+		return fmt.Errorf("provider %q: unsupported kind %q", p.Name, p.Kind)
+	}
+	if p.Azure != nil && p.Kind != KindAzureOpenAI {
+		return fmt.Errorf("provider %q: azure settings require kind %q", p.Name, KindAzureOpenAI)
+	}
+	if p.BaseURL != "" {
+		base, err := urlcheck.Normalize(p.BaseURL)
+		if err != nil {
+			return fmt.Errorf("provider %q: %w", p.Name, err)
+		}
+		p.BaseURL = base
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if apiKey != "" {
+		if err := r.secrets.Set(secretName(p.Name), apiKey); err != nil {
+			return err
+		}
+	}
+	_, p.HasKey = r.secrets.Get(secretName(p.Name))
+	r.providers[p.Name] = p
+	return nil
+}
+
+// Get looks a provider up by name.
+func (r *Registry) Get(name string) (Provider, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// List returns all providers sorted by name.
+func (r *Registry) List() []Provider {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Provider, 0, len(r.providers))
+	for _, p := range r.providers {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Remove deletes a provider and its stored key.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.providers, name)
+	_ = r.secrets.Set(secretName(name), "")
+}
+
+// AuthHeaders returns the headers the proxy must inject to authenticate
+// against the named provider — the header-injection layer routes consult
+// via Route.Provider.
+func (r *Registry) AuthHeaders(ctx context.Context, name string) (map[string]string, error) {
+	r.mu.Lock()
+	p, ok := r.providers[name]
+	var key string
+	if ok {
+		key, _ = r.secrets.Get(secretName(name))
+	}
+	copilot := r.copilot
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+	switch p.Kind {
+	case KindCopilot:
+		if copilot == nil {
+			return nil, fmt.Errorf("provider %q: github integration not configured", name)
+		}
+		tok, err := copilot.Current(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", name, err)
+		}
+		return map[string]string{"Authorization": "Bearer " + tok.Token}, nil
+	case KindOllama:
+		return map[string]string{}, nil
+	}
+	if key == "" {
+		return nil, fmt.Errorf("provider %q: no api key stored", name)
+	}
+	switch p.Kind {
+	case KindAnthropic:
+		return map[string]string{"X-Api-Key": key, "Anthropic-Version": "2023-06-01"}, nil
+	case KindAzureOpenAI:
+		return map[string]string{"Api-Key": key}, nil
+	default: // openai, openrouter
+		return map[string]string{"Authorization": "Bearer " + key}, nil
+	}
+}
+
+// Validate pings the provider with its credentials and reports whether the
+// upstream accepted them.
+func (r *Registry) Validate(ctx context.Context, name string) error {
+	r.mu.Lock()
+	p, ok := r.providers[name]
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown provider %q", name)
+	}
+	target, err := validationURL(p)
+	if err != nil {
+		return err
+	}
+	headers, err := r.AuthHeaders(ctx, name)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("provider %q: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("provider %q: upstream rejected the credentials (%d)", name, resp.StatusCode)
+	}
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("provider %q: upstream returned %d", name, resp.StatusCode)
+	}
+	return nil
+}
+
+// validationURL picks the cheapest authenticated endpoint per kind.
+func validationURL(p Provider) (string, error) {
+	base := strings.TrimSuffix(p.BaseURL, "/")
+	if base == "" {
+		switch p.Kind {
+		case KindOpenAI:
+			base = "https://api.openai.com"
+		case KindAnthropic:
+			base = "https://api.anthropic.com"
+		case KindCopilot:
+			base = "https://api.githubcopilot.com"
+		case KindOllama:
+			base = "http://127.0.0.1:11434"
+		case KindOpenRouter:
+			base = "https://openrouter.ai/api"
+		default:
+			return "", fmt.Errorf("provider %q: baseUrl is required for kind %q", p.Name, p.Kind)
+		}
+	}
+	switch p.Kind {
+	case KindOllama:
+		return base + "/api/tags", nil
+	case KindAzureOpenAI:
+		return base + "/openai/models?api-version=2024-02-01", nil
+	default:
+		return base + "/v1/models", nil
+	}
+}
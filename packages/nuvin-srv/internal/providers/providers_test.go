@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/secrets"
+)
+
+func TestSetListAndKeyVisibility(t *testing.T) {
+	store := secrets.NewStore()
+	reg := NewRegistry(store, nil)
+	if err := reg.Set(Provider{Name: "openai", Kind: KindOpenAI, DefaultModel: "gpt-4o"}, "sk-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Set(Provider{Name: "local", Kind: KindOllama, BaseURL: "http://127.0.0.1:11434"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	list := reg.List()
+	if len(list) != 2 || list[0].Name != "local" || list[1].Name != "openai" {
+		t.Fatalf("list = %+v", list)
+	}
+	if !list[1].HasKey || list[0].HasKey {
+		t.Errorf("hasKey flags wrong: %+v", list)
+	}
+
+	// Editing metadata without an apiKey keeps the stored key.
+	if err := reg.Set(Provider{Name: "openai", Kind: KindOpenAI, QuotaNote: "tier 2"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := reg.Get("openai"); !p.HasKey || p.QuotaNote != "tier 2" {
+		t.Errorf("provider = %+v", p)
+	}
+
+	reg.Remove("openai")
+	if _, ok := store.Get("provider/openai"); ok {
+		t.Error("key survived provider removal")
+	}
+}
+
+func TestSetRejectsBadInput(t *testing.T) {
+	reg := NewRegistry(secrets.NewStore(), nil)
+	for _, p := range []Provider{
+		{Name: "", Kind: KindOpenAI},
+		{Name: "x", Kind: "mystery"},
+		{Name: "a/b", Kind: KindOpenAI},
+		{Name: "x", Kind: KindOpenAI, BaseURL: "::not-a-url"},
+	} {
+		if err := reg.Set(p, "k"); err == nil {
+			t.Errorf("Set(%+v) accepted", p)
+		}
+	}
+}
+
+func TestAuthHeadersPerKind(t *testing.T) {
+	reg := NewRegistry(secrets.NewStore(), nil)
+	reg.Set(Provider{Name: "oa", Kind: KindOpenAI}, "sk-1")
+	reg.Set(Provider{Name: "ant", Kind: KindAnthropic}, "ak-1")
+	reg.Set(Provider{Name: "az", Kind: KindAzureOpenAI}, "az-1")
+	reg.Set(Provider{Name: "local", Kind: KindOllama}, "")
+	reg.Set(Provider{Name: "keyless", Kind: KindOpenAI}, "")
+
+	ctx := context.Background()
+	if h, _ := reg.AuthHeaders(ctx, "oa"); h["Authorization"] != "Bearer sk-1" {
+		t.Errorf("openai headers = %v", h)
+	}
+	if h, _ := reg.AuthHeaders(ctx, "ant"); h["X-Api-Key"] != "ak-1" || h["Anthropic-Version"] == "" {
+		t.Errorf("anthropic headers = %v", h)
+	}
+	if h, _ := reg.AuthHeaders(ctx, "az"); h["Api-Key"] != "az-1" {
+		t.Errorf("azure headers = %v", h)
+	}
+	if h, err := reg.AuthHeaders(ctx, "local"); err != nil || len(h) != 0 {
+		t.Errorf("ollama headers = %v, err = %v", h, err)
+	}
+	if _, err := reg.AuthHeaders(ctx, "keyless"); err == nil {
+		t.Error("keyless provider produced headers")
+	}
+	if _, err := reg.AuthHeaders(ctx, "ghost"); err == nil {
+		t.Error("unknown provider produced headers")
+	}
+}
+
+func TestValidatePing(t *testing.T) {
+	var sawAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawAuth = r.Header.Get("Authorization")
+		if sawAuth != "Bearer sk-good" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	reg := NewRegistry(secrets.NewStore(), nil)
+	reg.Set(Provider{Name: "good", Kind: KindOpenAI, BaseURL: srv.URL}, "sk-good")
+	reg.Set(Provider{Name: "bad", Kind: KindOpenAI, BaseURL: srv.URL}, "sk-bad")
+
+	if err := reg.Validate(context.Background(), "good"); err != nil {
+		t.Errorf("valid key rejected: %v", err)
+	}
+	if err := reg.Validate(context.Background(), "bad"); err == nil {
+		t.Error("invalid key accepted")
+	}
+}
@@ -0,0 +1,147 @@
+package proxy
+
+import (
+	"time"
+)
+
+// Balancing strategies and passive health defaults.
+const (
+	StrategyRoundRobin = "roundrobin"
+	StrategyPriority   = "priority"
+
+	// ejectAfter consecutive failures take a backend out of rotation for
+	// ejectFor; it is retried once the cooldown expires.
+	ejectAfter = 3
+	ejectFor   = 30 * time.Second
+)
+
+// Upstream is one backend of a multi-target route.
+type Upstream struct {
+	URL string `json:"url"`
+	// Priority orders failover under the "priority" strategy; lower is
+	// tried first. Round-robin ignores it.
+	Priority int `json:"priority,omitempty"`
+}
+
+type backendState struct {
+	url         string
+	priority    int
+	failures    int
+	ejectedTill time.Time
+}
+
+type lbState struct {
+	next     int
+	backends []*backendState
+}
+
+// rebuildLBLocked resets balancing state after a route changes.
+func (s *Service) rebuildLBLocked(route Route) {
+	if s.lb == nil {
+		s.lb = make(map[string]*lbState)
+	}
+	if len(route.Upstreams) == 0 {
+		delete(s.lb, route.Name)
+		return
+	}
+	st := &lbState{}
+	for _, u := range route.Upstreams {
+		st.backends = append(st.backends, &backendState{url: u.URL, priority: u.Priority})
+	}
+	s.lb[route.Name] = st
+}
+
+// BackendCount reports how many upstreams a route balances across.
+func (s *Service) BackendCount(name string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if st, ok := s.lb[name]; ok {
+		return len(st.backends)
+	}
+	return 1
+}
+
+// PickUpstream selects the next upstream base URL for a route, skipping
+// backends in tried and preferring ones that are not ejected; ok is false
+// once every backend has been tried. Single-upstream routes always return
+// their one target.
+func (s *Service) PickUpstream(name string, tried map[string]bool) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	route, okRoute := s.routes[name]
+	st := s.lb[name]
+	if st == nil {
+		if !okRoute || route.Upstream == "" || tried[route.Upstream] {
+			return "", false
+		}
+		return route.Upstream, true
+	}
+
+	now := time.Now()
+	healthy := func(b *backendState) bool { return !b.ejectedTill.After(now) }
+
+	var pick *backendState
+	if route.Strategy == StrategyPriority {
+		for _, b := range st.backends {
+			if tried[b.url] || !healthy(b) {
+				continue
+			}
+			if pick == nil || b.priority < pick.priority {
+				pick = b
+			}
+		}
+	} else {
+		for i := 0; i < len(st.backends); i++ {
+			b := st.backends[(st.next+i)%len(st.backends)]
+			if tried[b.url] || !healthy(b) {
+				continue
+			}
+			pick = b
+			st.next = (st.next + i + 1) % len(st.backends)
+			break
+		}
+	}
+	if pick == nil {
+		// Everything healthy is exhausted; fall back to the ejected backend
+		// whose cooldown expires soonest rather than failing outright.
+		for _, b := range st.backends {
+			if tried[b.url] {
+				continue
+			}
+			if pick == nil || b.ejectedTill.Before(pick.ejectedTill) {
+				pick = b
+			}
+		}
+	}
+	if pick == nil {
+		return "", false
+	}
+	return pick.url, true
+}
+
+// ReportUpstream feeds the passive health checker: a success resets a
+// backend's failure streak, a failure counts toward ejection.
+func (s *Service) ReportUpstream(name, url string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.lb[name]
+	if st == nil {
+		return
+	}
+	for _, b := range st.backends {
+		if b.url != url {
+			continue
+		}
+		if ok {
+			b.failures = 0
+			b.ejectedTill = time.Time{}
+		} else {
+			b.failures++
+			if b.failures >= ejectAfter {
+				b.ejectedTill = time.Now().Add(ejectFor)
+				b.failures = 0
+			}
+		}
+		return
+	}
+}
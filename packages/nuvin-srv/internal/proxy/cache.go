@@ -0,0 +1,228 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheConfig turns on response caching for a route. Model lists and
+// embedding metadata are fetched constantly with identical requests, so
+// even a short TTL removes most upstream round trips.
+type CacheConfig struct {
+	// TTLSeconds is how long cached responses stay fresh (default 60).
+	TTLSeconds int `json:"ttlSeconds,omitempty"`
+	// MaxEntries bounds the cache size (default 256, oldest evicted).
+	MaxEntries int `json:"maxEntries,omitempty"`
+	// Dir stores cached bodies on disk instead of in memory; the index
+	// stays in memory either way.
+	Dir string `json:"dir,omitempty"`
+}
+
+// CachedResponse is one cacheable upstream response.
+type CachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+type cacheEntry struct {
+	status   int
+	header   http.Header
+	body     []byte // in-memory backend
+	bodyPath string // disk backend
+	expires  time.Time
+}
+
+// routeCache caches GET responses for one route, honoring the Vary header:
+// each resource remembers which request headers its responses vary on and
+// keys variants by their values.
+type routeCache struct {
+	ttl time.Duration
+	max int
+	dir string
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	order   []string
+	varies  map[string][]string
+}
+
+func newRouteCache(cfg CacheConfig) *routeCache {
+	ttl := time.Duration(cfg.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = 60 * time.Second
+	}
+	max := cfg.MaxEntries
+	if max <= 0 {
+		max = 256
+	}
+	return &routeCache{
+		ttl:     ttl,
+		max:     max,
+		dir:     cfg.Dir,
+		now:     time.Now,
+		entries: make(map[string]*cacheEntry),
+		varies:  make(map[string][]string),
+	}
+}
+
+// variantKey extends the resource key with the request's values for the
+// headers this resource varies on.
+func (c *routeCache) variantKey(resource string, reqHeader http.Header) string {
+	names := c.varies[resource]
+	if len(names) == 0 {
+		return resource
+	}
+	var b strings.Builder
+	b.WriteString(resource)
+	for _, name := range names {
+		b.WriteByte('\x00')
+		b.WriteString(reqHeader.Get(name))
+	}
+	return b.String()
+}
+
+func (c *routeCache) lookup(resource string, reqHeader http.Header) (CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[c.variantKey(resource, reqHeader)]
+	if !ok || !c.now().Before(entry.expires) {
+		return CachedResponse{}, false
+	}
+	body := entry.body
+	if entry.bodyPath != "" {
+		raw, err := os.ReadFile(entry.bodyPath)
+		if err != nil {
+			return CachedResponse{}, false
+		}
+		body = raw
+	}
+	return CachedResponse{Status: entry.status, Header: entry.header.Clone(), Body: body}, true
+}
+
+func (c *routeCache) store(resource string, reqHeader http.Header, resp CachedResponse) {
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return
+	}
+	var names []string
+	for _, v := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return // uncacheable by definition
+			}
+			if name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(names) > 0 {
+		c.varies[resource] = names
+	}
+	key := c.variantKey(resource, reqHeader)
+	entry := &cacheEntry{
+		status:  resp.Status,
+		header:  resp.Header.Clone(),
+		expires: c.now().Add(c.ttl),
+	}
+	if c.dir != "" {
+		sum := sha256.Sum256([]byte(key))
+		path := filepath.Join(c.dir, hex.EncodeToString(sum[:])+".cache")
+		if os.MkdirAll(c.dir, 0o755) != nil || os.WriteFile(path, resp.Body, 0o644) != nil {
+			return
+		}
+		entry.bodyPath = path
+	} else {
+		entry.body = append([]byte(nil), resp.Body...)
+	}
+	if _, exists := c.entries[key]; !exists {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = entry
+	for len(c.order) > c.max {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		c.removeLocked(oldest)
+	}
+}
+
+func (c *routeCache) removeLocked(key string) {
+	if entry, ok := c.entries[key]; ok {
+		if entry.bodyPath != "" {
+			_ = os.Remove(entry.bodyPath)
+		}
+		delete(c.entries, key)
+	}
+}
+
+func (c *routeCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		c.removeLocked(key)
+	}
+	c.order = nil
+	c.varies = make(map[string][]string)
+}
+
+func (c *routeCache) size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// CacheLookup serves a cached response for a route's resource (path plus
+// query) if one is fresh.
+func (s *Service) CacheLookup(name, resource string, reqHeader http.Header) (CachedResponse, bool) {
+	s.mu.RLock()
+	c := s.caches[name]
+	s.mu.RUnlock()
+	if c == nil {
+		return CachedResponse{}, false
+	}
+	return c.lookup(resource, reqHeader)
+}
+
+// CacheStore records an upstream response for a route's resource. Routes
+// without caching configured ignore the call.
+func (s *Service) CacheStore(name, resource string, reqHeader http.Header, resp CachedResponse) {
+	s.mu.RLock()
+	c := s.caches[name]
+	s.mu.RUnlock()
+	if c != nil {
+		c.store(resource, reqHeader, resp)
+	}
+}
+
+// PurgeCache drops a route's cached responses; an empty name purges every
+// route.
+func (s *Service) PurgeCache(name string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for routeName, c := range s.caches {
+		if name == "" || name == routeName {
+			c.purge()
+		}
+	}
+}
+
+// CacheStats reports the entry count per cached route.
+func (s *Service) CacheStats() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]int, len(s.caches))
+	for name, c := range s.caches {
+		out[name] = c.size()
+	}
+	return out
+}
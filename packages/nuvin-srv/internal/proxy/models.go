@@ -0,0 +1,68 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelRule maps a model name to the proxy route serving it. Model is an
+// exact name ("gpt-4o") or a prefix pattern ending in * ("claude-*").
+type ModelRule struct {
+	Model string `json:"model"`
+	Route string `json:"route"`
+}
+
+// SetModelRules replaces the model routing table used by the OpenAI-compatible
+// /v1/* facade. defaultRoute handles models no rule matches (and requests
+// without a model); empty disables the fallback.
+func (s *Service) SetModelRules(rules []ModelRule, defaultRoute string) error {
+	for _, r := range rules {
+		if r.Model == "" || r.Route == "" {
+			return fmt.Errorf("model rule: model and route are required")
+		}
+		if strings.Count(r.Model, "*") > 1 ||
+			(strings.Contains(r.Model, "*") && !strings.HasSuffix(r.Model, "*")) {
+			return fmt.Errorf("model rule %q: * is only allowed as a trailing wildcard", r.Model)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modelRules = append([]ModelRule(nil), rules...)
+	s.defaultModelRoute = defaultRoute
+	return nil
+}
+
+// ModelRules returns the model routing table and the default route.
+func (s *Service) ModelRules() ([]ModelRule, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]ModelRule(nil), s.modelRules...), s.defaultModelRoute
+}
+
+// RouteForModel resolves a model name to a route name: an exact rule wins,
+// then the longest matching prefix pattern, then the default route.
+func (s *Service) RouteForModel(model string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	bestLen := -1
+	best := ""
+	for _, r := range s.modelRules {
+		if !strings.Contains(r.Model, "*") {
+			if r.Model == model {
+				return r.Route, true
+			}
+			continue
+		}
+		prefix := strings.TrimSuffix(r.Model, "*")
+		if strings.HasPrefix(model, prefix) && len(prefix) > bestLen {
+			best, bestLen = r.Route, len(prefix)
+		}
+	}
+	if bestLen >= 0 {
+		return best, true
+	}
+	if s.defaultModelRoute != "" {
+		return s.defaultModelRoute, true
+	}
+	return "", false
+}
@@ -0,0 +1,348 @@
+// Package proxy implements the named-route reverse proxy that forwards LLM
+// traffic from the frontend to configured upstream providers.
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marschhuynh/nuvin-srv/internal/breaker"
+	"github.com/marschhuynh/nuvin-srv/internal/outbound"
+	"github.com/marschhuynh/nuvin-srv/internal/translate"
+	"github.com/marschhuynh/nuvin-srv/internal/urlcheck"
+)
+
+// Route describes one logical upstream the proxy can forward to.
+type Route struct {
+	// Name is the path clients address under /proxy/, e.g. "openai" in
+	// /proxy/openai/v1/chat/completions. It may span several segments
+	// ("v1/chat/completions") or end in a wildcard ("v1/*"); see Match for
+	// precedence.
+	Name string `json:"name"`
+	// Upstream is the base URL requests are forwarded to. Routes balancing
+	// across several backends set Upstreams instead.
+	Upstream string `json:"upstream,omitempty"`
+	// Upstreams lists alternative backends; Strategy picks between them
+	// ("roundrobin" default, or "priority" failover) and failing backends
+	// are ejected temporarily.
+	Upstreams []Upstream `json:"upstreams,omitempty"`
+	Strategy  string     `json:"strategy,omitempty"`
+	// Headers are set on every forwarded request (typically Authorization),
+	// overriding anything the client sent, so the browser never holds the
+	// upstream key.
+	Headers map[string]string `json:"headers,omitempty"`
+	// StripHeaders are inbound headers never forwarded upstream (e.g.
+	// Cookie, or a client Authorization that must not leak).
+	StripHeaders []string `json:"stripHeaders,omitempty"`
+	// Rewrite customizes the forwarded Host header and path.
+	Rewrite *Rewrite `json:"rewrite,omitempty"`
+	// Transform shapes JSON request and response bodies on the route.
+	Transform *Transform `json:"transform,omitempty"`
+	// Cache enables response caching for GET requests on the route.
+	Cache *CacheConfig `json:"cache,omitempty"`
+	// Mirror asynchronously duplicates a share of the route's traffic to a
+	// secondary target whose responses are ignored, so a new provider can
+	// be tested against production prompts safely.
+	Mirror *Mirror `json:"mirror,omitempty"`
+	// GRPC marks the upstream as a gRPC service. Requests are forwarded
+	// over HTTP/2 (h2c for plain-http upstreams), trailers are relayed,
+	// and trailers-only errors are translated into HTTP statuses for
+	// non-gRPC callers.
+	GRPC bool `json:"grpc,omitempty"`
+	// Protocol names the upstream's native API ("anthropic" or "gemini").
+	// Chat completions arriving in OpenAI format are translated on the way
+	// in and their responses converted back; empty means the upstream
+	// already speaks the OpenAI protocol.
+	Protocol string `json:"protocol,omitempty"`
+	// Provider names a registered provider (see the providers registry)
+	// whose credentials are injected into forwarded requests, replacing
+	// hand-maintained Authorization entries in Headers.
+	Provider string `json:"provider,omitempty"`
+	// OutboundProxy routes this route's upstream traffic through an HTTP
+	// or SOCKS5 proxy URL instead of direct egress.
+	OutboundProxy string `json:"outboundProxy,omitempty"`
+	// TLS customizes upstream TLS (client certs, CA bundle, skip-verify)
+	// for this route.
+	TLS *outbound.TLSOptions `json:"tls,omitempty"`
+}
+
+// Mirror configures traffic shadowing for a route.
+type Mirror struct {
+	// Upstream is the base URL mirrored requests are sent to.
+	Upstream string `json:"upstream"`
+	// Percent of requests to mirror, 1–100 (default 100).
+	Percent float64 `json:"percent,omitempty"`
+}
+
+// Rewrite adjusts how a matched request is presented to the upstream.
+type Rewrite struct {
+	// Host overrides the Host header sent upstream; empty keeps the
+	// upstream URL's own host.
+	Host string `json:"host,omitempty"`
+	// StripPrefix removes a leading path prefix from the forwarded tail.
+	StripPrefix string `json:"stripPrefix,omitempty"`
+	// AddPrefix prepends a path prefix to the forwarded tail.
+	AddPrefix string `json:"addPrefix,omitempty"`
+}
+
+// Apply rewrites the forwarded path tail.
+func (rw *Rewrite) Apply(tail string) string {
+	if rw == nil {
+		return tail
+	}
+	if p := strings.Trim(rw.StripPrefix, "/"); p != "" {
+		if tail == p {
+			tail = ""
+		} else if strings.HasPrefix(tail, p+"/") {
+			tail = tail[len(p)+1:]
+		}
+	}
+	if p := strings.Trim(rw.AddPrefix, "/"); p != "" {
+		if tail == "" {
+			tail = p
+		} else {
+			tail = p + "/" + tail
+		}
+	}
+	return tail
+}
+
+// Service holds the proxy route table.
+type Service struct {
+	mu         sync.RWMutex
+	routes     map[string]Route
+	lb         map[string]*lbState
+	caches     map[string]*routeCache
+	breaker    *breaker.Breaker
+	configPath string
+
+	modelRules        []ModelRule
+	defaultModelRoute string
+}
+
+// NewService returns an empty route table.
+func NewService() *Service {
+	return &Service{
+		routes:  make(map[string]Route),
+		caches:  make(map[string]*routeCache),
+		breaker: breaker.New(),
+	}
+}
+
+// AllowHost consults the per-host circuit breaker before an upstream call.
+// When the circuit is open it returns false and how long to wait before
+// retrying.
+func (s *Service) AllowHost(host string) (bool, time.Duration) {
+	return s.breaker.Allow(host)
+}
+
+// ReportHost records the outcome of an upstream call for the circuit
+// breaker.
+func (s *Service) ReportHost(host string, ok bool) {
+	s.breaker.Report(host, ok)
+}
+
+// SetRoute adds or replaces a route.
+func (s *Service) SetRoute(route Route) error {
+	if route.Name == "" {
+		return fmt.Errorf("route name is required")
+	}
+	if strings.Trim(route.Name, "/") != route.Name {
+		return fmt.Errorf("route %q: name must not start or end with /", route.Name)
+	}
+	if strings.Count(route.Name, "*") > 1 ||
+		(strings.Contains(route.Name, "*") && route.Name != "*" && !strings.HasSuffix(route.Name, "/*")) {
+		return fmt.Errorf("route %q: * is only allowed as a trailing /* segment", route.Name)
+	}
+	if route.Upstream == "" && len(route.Upstreams) == 0 {
+		return fmt.Errorf("route %q: upstream is required", route.Name)
+	}
+	switch route.Strategy {
+	case "", StrategyRoundRobin, StrategyPriority:
+	default:
+		return fmt.Errorf("route %q: unsupported strategy %q", route.Name, route.Strategy)
+	}
+	switch route.Protocol {
+	case "", translate.ProtocolAnthropic, translate.ProtocolGemini:
+	default:
+		return fmt.Errorf("route %q: unsupported protocol %q", route.Name, route.Protocol)
+	}
+	if route.Mirror != nil {
+		if route.Mirror.Percent < 0 || route.Mirror.Percent > 100 {
+			return fmt.Errorf("route %q: mirror percent must be between 0 and 100", route.Name)
+		}
+		target, err := urlcheck.Normalize(route.Mirror.Upstream)
+		if err != nil {
+			return fmt.Errorf("route %q: mirror: %w", route.Name, err)
+		}
+		route.Mirror.Upstream = target
+	}
+	if route.Upstream != "" {
+		upstream, err := urlcheck.Normalize(route.Upstream)
+		if err != nil {
+			return fmt.Errorf("route %q: %w", route.Name, err)
+		}
+		route.Upstream = upstream
+	}
+	for i, u := range route.Upstreams {
+		upstream, err := urlcheck.Normalize(u.URL)
+		if err != nil {
+			return fmt.Errorf("route %q: %w", route.Name, err)
+		}
+		route.Upstreams[i].URL = upstream
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[route.Name] = route
+	s.rebuildLBLocked(route)
+	s.rebuildCacheLocked(route)
+	return s.persistLocked()
+}
+
+// RemoveRoute deletes a route by name.
+func (s *Service) RemoveRoute(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.routes, name)
+	delete(s.lb, name)
+	delete(s.caches, name)
+	_ = s.persistLocked()
+}
+
+// SetConfigPath points the service at a JSON file holding the route table.
+// Routes already in the file are loaded immediately and every subsequent
+// change is written back, so configuration survives restarts.
+func (s *Service) SetConfigPath(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configPath = path
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("loading proxy config: %w", err)
+	}
+	var routes []Route
+	if err := json.Unmarshal(raw, &routes); err != nil {
+		return fmt.Errorf("loading proxy config: %w", err)
+	}
+	for _, r := range routes {
+		if r.Name != "" {
+			s.routes[r.Name] = r
+			s.rebuildLBLocked(r)
+			s.rebuildCacheLocked(r)
+		}
+	}
+	return nil
+}
+
+// rebuildCacheLocked syncs a route's cache with its config. Updating a
+// route starts it with a fresh, empty cache.
+func (s *Service) rebuildCacheLocked(route Route) {
+	if route.Cache == nil {
+		delete(s.caches, route.Name)
+		return
+	}
+	s.caches[route.Name] = newRouteCache(*route.Cache)
+}
+
+// persistLocked rewrites the config file; it is a no-op without one.
+func (s *Service) persistLocked() error {
+	if s.configPath == "" {
+		return nil
+	}
+	out := make([]Route, 0, len(s.routes))
+	for _, r := range s.routes {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	raw, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("saving proxy config: %w", err)
+	}
+	if err := os.WriteFile(s.configPath, raw, 0o644); err != nil {
+		return fmt.Errorf("saving proxy config: %w", err)
+	}
+	return nil
+}
+
+// Match resolves a request path (the part after /proxy/) to a route and the
+// tail forwarded to its upstream. Precedence is deterministic: an exact name
+// match wins, then the longest plain name that is a segment-boundary prefix
+// of the path, then the longest wildcard prefix ("v1/*"); a bare "*" catches
+// everything last.
+func (s *Service) Match(path string) (Route, string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, ok := s.routes[path]; ok && !strings.Contains(path, "*") {
+		return r, "", true
+	}
+
+	var best Route
+	var bestTail string
+	bestLen := -1
+	for name, r := range s.routes {
+		if strings.Contains(name, "*") {
+			continue
+		}
+		if strings.HasPrefix(path, name+"/") && len(name) > bestLen {
+			best, bestTail, bestLen = r, path[len(name)+1:], len(name)
+		}
+	}
+	if bestLen >= 0 {
+		return best, bestTail, true
+	}
+
+	for name, r := range s.routes {
+		var prefix string
+		switch {
+		case name == "*":
+			prefix = ""
+		case strings.HasSuffix(name, "/*"):
+			prefix = strings.TrimSuffix(name, "/*")
+		default:
+			continue
+		}
+		var tail string
+		switch {
+		case prefix == "":
+			tail = path
+		case path == prefix:
+			tail = ""
+		case strings.HasPrefix(path, prefix+"/"):
+			tail = path[len(prefix)+1:]
+		default:
+			continue
+		}
+		if len(prefix) > bestLen {
+			best, bestTail, bestLen = r, tail, len(prefix)
+		}
+	}
+	return best, bestTail, bestLen >= 0
+}
+
+// Route looks up a route by name.
+func (s *Service) Route(name string) (Route, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.routes[name]
+	return r, ok
+}
+
+// Routes returns a snapshot of all configured routes.
+func (s *Service) Routes() []Route {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Route, 0, len(s.routes))
+	for _, r := range s.routes {
+		out = append(out, r)
+	}
+	return out
+}
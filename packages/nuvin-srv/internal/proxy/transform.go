@@ -0,0 +1,90 @@
+package proxy
+
+import (
+	"encoding/json"
+)
+
+// Transform shapes JSON bodies crossing a route, so one OpenAI-compatible
+// facade can front upstreams with slightly different dialects. Non-JSON
+// bodies pass through untouched.
+type Transform struct {
+	// RenameFields maps request field names to the upstream's names; the
+	// value is kept, the old key dropped.
+	RenameFields map[string]string `json:"renameFields,omitempty"`
+	// RequestDefaults fills in request fields the client omitted, e.g. a
+	// default temperature or model.
+	RequestDefaults map[string]any `json:"requestDefaults,omitempty"`
+	// ModelMap rewrites the "model" field's value, mapping client-facing
+	// model names to upstream ones.
+	ModelMap map[string]string `json:"modelMap,omitempty"`
+	// StripResponseFields removes top-level fields from JSON responses
+	// before they reach the client.
+	StripResponseFields []string `json:"stripResponseFields,omitempty"`
+}
+
+// WantsRequest reports whether request bodies need buffering and rewriting.
+func (t *Transform) WantsRequest() bool {
+	return t != nil && (len(t.RenameFields) > 0 || len(t.RequestDefaults) > 0 || len(t.ModelMap) > 0)
+}
+
+// WantsResponse reports whether response bodies need rewriting.
+func (t *Transform) WantsResponse() bool {
+	return t != nil && len(t.StripResponseFields) > 0
+}
+
+// ApplyRequest rewrites a JSON request body per the transform; bodies that
+// do not parse as a JSON object are returned unchanged.
+func (t *Transform) ApplyRequest(body []byte) []byte {
+	if !t.WantsRequest() || len(body) == 0 {
+		return body
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return body
+	}
+	for old, renamed := range t.RenameFields {
+		if v, ok := obj[old]; ok {
+			delete(obj, old)
+			if _, taken := obj[renamed]; !taken {
+				obj[renamed] = v
+			}
+		}
+	}
+	for k, v := range t.RequestDefaults {
+		if _, ok := obj[k]; !ok {
+			obj[k] = v
+		}
+	}
+	if len(t.ModelMap) > 0 {
+		if model, ok := obj["model"].(string); ok {
+			if mapped, ok := t.ModelMap[model]; ok {
+				obj["model"] = mapped
+			}
+		}
+	}
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// ApplyResponse strips the configured top-level fields from a JSON response
+// body; bodies that do not parse as a JSON object are returned unchanged.
+func (t *Transform) ApplyResponse(body []byte) []byte {
+	if !t.WantsResponse() || len(body) == 0 {
+		return body
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return body
+	}
+	for _, field := range t.StripResponseFields {
+		delete(obj, field)
+	}
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return body
+	}
+	return out
+}
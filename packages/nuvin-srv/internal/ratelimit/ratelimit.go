@@ -0,0 +1,114 @@
+// Package ratelimit implements a token-bucket limiter keyed by upstream
+// host, protecting users' API keys from accidental rate bans.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// HostLimit configures the bucket for one upstream host.
+type HostLimit struct {
+	// RPS is the sustained refill rate in requests per second.
+	RPS float64 `json:"rps"`
+	// Burst is the bucket capacity (default 1).
+	Burst int `json:"burst,omitempty"`
+	// MaxWaitMs is how long a request may queue for a token before being
+	// rejected (default 0: reject immediately when empty).
+	MaxWaitMs int `json:"maxWaitMs,omitempty"`
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter holds per-host token buckets. Hosts without a configured limit
+// are unlimited.
+type Limiter struct {
+	mu      sync.Mutex
+	limits  map[string]HostLimit
+	buckets map[string]*bucket
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewLimiter returns a Limiter with no host limits configured.
+func NewLimiter() *Limiter {
+	return &Limiter{
+		limits:  make(map[string]HostLimit),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+}
+
+// SetLimit configures the limit for host. An RPS of 0 removes the limit.
+func (l *Limiter) SetLimit(host string, limit HostLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit.RPS <= 0 {
+		delete(l.limits, host)
+		delete(l.buckets, host)
+		return
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	l.limits[host] = limit
+	delete(l.buckets, host)
+}
+
+// Limits returns the configured per-host limits.
+func (l *Limiter) Limits() map[string]HostLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make(map[string]HostLimit, len(l.limits))
+	for host, limit := range l.limits {
+		out[host] = limit
+	}
+	return out
+}
+
+// Acquire takes a token for host, queueing up to the host's MaxWaitMs when
+// the bucket is empty. When the wait would be longer it returns false along
+// with how long the caller should tell the client to back off.
+func (l *Limiter) Acquire(host string) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	limit, limited := l.limits[host]
+	if !limited {
+		l.mu.Unlock()
+		return true, 0
+	}
+
+	b := l.buckets[host]
+	now := l.now()
+	if b == nil {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[host] = b
+	} else {
+		b.tokens += now.Sub(b.last).Seconds() * limit.RPS
+		if max := float64(limit.Burst); b.tokens > max {
+			b.tokens = max
+		}
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		l.mu.Unlock()
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / limit.RPS * float64(time.Second))
+	if wait <= time.Duration(limit.MaxWaitMs)*time.Millisecond {
+		// Queue: claim the token now and sleep outside the lock.
+		b.tokens--
+		l.mu.Unlock()
+		l.sleep(wait)
+		return true, 0
+	}
+	l.mu.Unlock()
+	return false, wait
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterBurstAndRejection(t *testing.T) {
+	l := NewLimiter()
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	l.sleep = func(time.Duration) {}
+	l.SetLimit("api.openai.com", HostLimit{RPS: 1, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Acquire("api.openai.com"); !ok {
+			t.Fatalf("burst request %d rejected", i+1)
+		}
+	}
+	ok, retryAfter := l.Acquire("api.openai.com")
+	if ok {
+		t.Fatal("request over burst accepted with no wait allowance")
+	}
+	if retryAfter <= 0 {
+		t.Errorf("retryAfter = %v, want > 0", retryAfter)
+	}
+
+	// Other hosts are unlimited.
+	if ok, _ := l.Acquire("other.example.com"); !ok {
+		t.Error("unconfigured host limited")
+	}
+
+	// Tokens refill over time.
+	now = now.Add(3 * time.Second)
+	if ok, _ := l.Acquire("api.openai.com"); !ok {
+		t.Error("request rejected after refill window")
+	}
+}
+
+func TestLimiterQueuesWithinMaxWait(t *testing.T) {
+	l := NewLimiter()
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	var slept time.Duration
+	l.sleep = func(d time.Duration) { slept += d }
+	l.SetLimit("host", HostLimit{RPS: 10, Burst: 1, MaxWaitMs: 500})
+
+	if ok, _ := l.Acquire("host"); !ok {
+		t.Fatal("first request rejected")
+	}
+	// Bucket is empty; the 100ms refill fits the 500ms budget, so the
+	// request queues instead of failing.
+	if ok, _ := l.Acquire("host"); !ok {
+		t.Fatal("queueable request rejected")
+	}
+	if slept < 90*time.Millisecond || slept > 110*time.Millisecond {
+		t.Errorf("queued for %v, want ~100ms", slept)
+	}
+}
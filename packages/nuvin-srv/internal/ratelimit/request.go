@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rule configures one request-rate bucket.
+type Rule struct {
+	// RPS is the sustained refill rate in requests per second.
+	RPS float64 `json:"rps"`
+	// Burst is the bucket capacity (default 1).
+	Burst int `json:"burst,omitempty"`
+}
+
+// Decision reports a limiter outcome together with the values for the
+// standard X-RateLimit-* response headers.
+type Decision struct {
+	Allowed bool
+	// Limited is false when no rule matched the request, in which case the
+	// header values are meaningless.
+	Limited    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Time
+	RetryAfter time.Duration
+}
+
+// RequestLimiter applies per-subject token buckets to request scopes. Rules
+// are keyed by a path-like scope ("proxy/openai", "fetch", "execute-command");
+// the longest rule whose key prefixes the request scope on a segment boundary
+// applies, and a rule under the empty key applies to every request. Each
+// applicable rule buckets per subject.
+type RequestLimiter struct {
+	mu      sync.Mutex
+	rules   map[string]Rule
+	buckets map[string]*bucket
+
+	now func() time.Time
+}
+
+// NewRequestLimiter returns a limiter with no rules; everything passes.
+func NewRequestLimiter() *RequestLimiter {
+	return &RequestLimiter{
+		rules:   make(map[string]Rule),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// SetRules replaces the rule table; rules with RPS <= 0 are dropped.
+func (l *RequestLimiter) SetRules(rules map[string]Rule) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules = make(map[string]Rule, len(rules))
+	l.buckets = make(map[string]*bucket)
+	for key, rule := range rules {
+		if rule.RPS <= 0 {
+			continue
+		}
+		if rule.Burst <= 0 {
+			rule.Burst = 1
+		}
+		l.rules[key] = rule
+	}
+}
+
+// Rules returns a snapshot of the rule table.
+func (l *RequestLimiter) Rules() map[string]Rule {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make(map[string]Rule, len(l.rules))
+	for k, v := range l.rules {
+		out[k] = v
+	}
+	return out
+}
+
+// Allow takes one token for the scope and subject from every applicable
+// bucket, consuming only when all of them have capacity so a denied request
+// never burns tokens.
+func (l *RequestLimiter) Allow(scope, subject string) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	keys := l.applicableKeys(scope)
+	if len(keys) == 0 {
+		return Decision{Allowed: true}
+	}
+
+	now := l.now()
+	type candidate struct {
+		rule Rule
+		b    *bucket
+	}
+	cands := make([]candidate, 0, len(keys))
+	allowed := true
+	for _, key := range keys {
+		rule := l.rules[key]
+		bkey := key + "\x00" + subject
+		b := l.buckets[bkey]
+		if b == nil {
+			b = &bucket{tokens: float64(rule.Burst), last: now}
+			l.buckets[bkey] = b
+		} else {
+			b.tokens += now.Sub(b.last).Seconds() * rule.RPS
+			if max := float64(rule.Burst); b.tokens > max {
+				b.tokens = max
+			}
+			b.last = now
+		}
+		if b.tokens < 1 {
+			allowed = false
+		}
+		cands = append(cands, candidate{rule, b})
+	}
+
+	d := Decision{Allowed: allowed, Limited: true}
+	for _, c := range cands {
+		if allowed {
+			c.b.tokens--
+		}
+		remaining := int(c.b.tokens)
+		if d.Limit == 0 || remaining < d.Remaining {
+			d.Limit = c.rule.Burst
+			d.Remaining = remaining
+			d.Reset = now.Add(time.Duration((float64(c.rule.Burst) - c.b.tokens) / c.rule.RPS * float64(time.Second)))
+			if !allowed {
+				d.RetryAfter = time.Duration((1 - c.b.tokens) / c.rule.RPS * float64(time.Second))
+			}
+		}
+	}
+	if d.Remaining < 0 {
+		d.Remaining = 0
+	}
+	return d
+}
+
+// applicableKeys returns the empty-key rule (if set) plus the longest
+// segment-boundary prefix rule for the scope.
+func (l *RequestLimiter) applicableKeys(scope string) []string {
+	var keys []string
+	if _, ok := l.rules[""]; ok {
+		keys = append(keys, "")
+	}
+	best := ""
+	for key := range l.rules {
+		if key == "" || len(key) < len(best) {
+			continue
+		}
+		if scope == key || strings.HasPrefix(scope, key+"/") {
+			best = key
+		}
+	}
+	if best != "" {
+		keys = append(keys, best)
+	}
+	return keys
+}
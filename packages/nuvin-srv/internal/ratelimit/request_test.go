@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequestLimiterScopesAndSubjects(t *testing.T) {
+	l := NewRequestLimiter()
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	l.SetRules(map[string]Rule{"proxy/openai": {RPS: 1, Burst: 2}})
+
+	for i := 0; i < 2; i++ {
+		d := l.Allow("proxy/openai/v1/chat/completions", "alice")
+		if !d.Allowed || !d.Limited {
+			t.Fatalf("burst request %d: %+v", i+1, d)
+		}
+	}
+	d := l.Allow("proxy/openai/v1/models", "alice")
+	if d.Allowed {
+		t.Fatalf("request over burst allowed: %+v", d)
+	}
+	if d.Limit != 2 || d.Remaining != 0 || d.RetryAfter <= 0 {
+		t.Errorf("decision = %+v", d)
+	}
+
+	// Other subjects and unmatched scopes have their own headroom.
+	if d := l.Allow("proxy/openai/v1/models", "bob"); !d.Allowed {
+		t.Error("other subject limited by alice's bucket")
+	}
+	if d := l.Allow("proxy/other/v1", "alice"); !d.Allowed || d.Limited {
+		t.Errorf("unmatched scope limited: %+v", d)
+	}
+
+	// Tokens refill over time.
+	now = now.Add(2 * time.Second)
+	if d := l.Allow("proxy/openai/v1/models", "alice"); !d.Allowed {
+		t.Error("request rejected after refill window")
+	}
+}
+
+func TestRequestLimiterGlobalRuleDoesNotBurnScopedTokens(t *testing.T) {
+	l := NewRequestLimiter()
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	l.SetRules(map[string]Rule{
+		"":      {RPS: 1, Burst: 1},
+		"fetch": {RPS: 1, Burst: 5},
+	})
+
+	if d := l.Allow("fetch", "alice"); !d.Allowed {
+		t.Fatalf("first request rejected: %+v", d)
+	}
+	// The global bucket is empty now; the denial must not consume from the
+	// scoped bucket.
+	if d := l.Allow("fetch", "alice"); d.Allowed {
+		t.Fatal("second request allowed past the global rule")
+	}
+	now = now.Add(time.Second)
+	d := l.Allow("fetch", "alice")
+	if !d.Allowed {
+		t.Fatalf("request after refill rejected: %+v", d)
+	}
+	if d.Remaining != 0 {
+		// The global bucket (burst 1) is the most constrained one.
+		t.Errorf("remaining = %d, want 0 from the global bucket", d.Remaining)
+	}
+}
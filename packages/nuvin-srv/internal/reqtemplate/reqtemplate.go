@@ -0,0 +1,119 @@
+// Package reqtemplate stores named, reusable fetch request templates with
+// {{variable}} placeholders the UI can render and execute.
+package reqtemplate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+// Template is a named FetchRequest whose URL, headers, and body may contain
+// {{variable}} placeholders.
+type Template struct {
+	Name    string                  `json:"name"`
+	Request fetchproxy.FetchRequest `json:"request"`
+}
+
+// Store keeps templates by name.
+type Store struct {
+	mu        sync.RWMutex
+	templates map[string]Template
+}
+
+// NewStore returns an empty template store.
+func NewStore() *Store {
+	return &Store{templates: make(map[string]Template)}
+}
+
+// Save adds or replaces a template.
+func (s *Store) Save(t Template) error {
+	if t.Name == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if t.Request.URL == "" {
+		return fmt.Errorf("template %q: request url is required", t.Name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates[t.Name] = t
+	return nil
+}
+
+// Get returns the template with the given name.
+func (s *Store) Get(name string) (Template, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[name]
+	return t, ok
+}
+
+// Delete removes a template by name.
+func (s *Store) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.templates, name)
+}
+
+// List returns all templates sorted by name.
+func (s *Store) List() []Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Template, 0, len(s.templates))
+	for _, t := range s.templates {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+var placeholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Render substitutes vars into the template's URL, headers, and body and
+// returns the concrete request. Placeholders without a matching variable
+// make rendering fail so typos don't silently reach the upstream.
+func Render(t Template, vars map[string]string) (fetchproxy.FetchRequest, error) {
+	var missing []string
+	substitute := func(s string) string {
+		return placeholderRe.ReplaceAllStringFunc(s, func(match string) string {
+			name := placeholderRe.FindStringSubmatch(match)[1]
+			value, ok := vars[name]
+			if !ok {
+				missing = append(missing, name)
+				return match
+			}
+			return value
+		})
+	}
+
+	req := t.Request
+	req.URL = substitute(req.URL)
+	req.Body = substitute(req.Body)
+	if len(t.Request.Headers) > 0 {
+		req.Headers = make(map[string]string, len(t.Request.Headers))
+		for k, v := range t.Request.Headers {
+			req.Headers[k] = substitute(v)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fetchproxy.FetchRequest{}, fmt.Errorf(
+			"template %q: unresolved variables: %s", t.Name, strings.Join(dedupe(missing), ", "))
+	}
+	return req, nil
+}
+
+func dedupe(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
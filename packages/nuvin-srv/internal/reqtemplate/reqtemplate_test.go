@@ -0,0 +1,74 @@
+package reqtemplate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marschhuynh/nuvin-srv/internal/fetchproxy"
+)
+
+func TestStoreCRUD(t *testing.T) {
+	s := NewStore()
+	if err := s.Save(Template{Name: "get-user", Request: fetchproxy.FetchRequest{URL: "https://api.example.com/users/{{id}}"}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := s.Save(Template{Name: "", Request: fetchproxy.FetchRequest{URL: "https://x"}}); err == nil {
+		t.Error("Save accepted a template without a name")
+	}
+	if err := s.Save(Template{Name: "no-url"}); err == nil {
+		t.Error("Save accepted a template without a URL")
+	}
+
+	tpl, ok := s.Get("get-user")
+	if !ok || tpl.Request.URL != "https://api.example.com/users/{{id}}" {
+		t.Fatalf("Get returned %+v, %v", tpl, ok)
+	}
+	if got := s.List(); len(got) != 1 || got[0].Name != "get-user" {
+		t.Errorf("List = %+v", got)
+	}
+
+	s.Delete("get-user")
+	if _, ok := s.Get("get-user"); ok {
+		t.Error("template still present after Delete")
+	}
+}
+
+func TestRenderSubstitutesVariables(t *testing.T) {
+	tpl := Template{
+		Name: "create",
+		Request: fetchproxy.FetchRequest{
+			URL:     "https://api.example.com/{{resource}}",
+			Method:  "POST",
+			Headers: map[string]string{"Authorization": "Bearer {{ token }}"},
+			Body:    `{"name":"{{name}}"}`,
+		},
+	}
+	req, err := Render(tpl, map[string]string{"resource": "widgets", "token": "tok-1", "name": "w"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if req.URL != "https://api.example.com/widgets" {
+		t.Errorf("URL = %q", req.URL)
+	}
+	if req.Headers["Authorization"] != "Bearer tok-1" {
+		t.Errorf("Authorization = %q", req.Headers["Authorization"])
+	}
+	if req.Body != `{"name":"w"}` {
+		t.Errorf("Body = %q", req.Body)
+	}
+	// The template itself must not be mutated.
+	if tpl.Request.Headers["Authorization"] != "Bearer {{ token }}" {
+		t.Errorf("template header mutated: %q", tpl.Request.Headers["Authorization"])
+	}
+}
+
+func TestRenderReportsMissingVariables(t *testing.T) {
+	tpl := Template{Name: "t", Request: fetchproxy.FetchRequest{URL: "https://x/{{a}}/{{b}}/{{a}}"}}
+	_, err := Render(tpl, map[string]string{"b": "1"})
+	if err == nil {
+		t.Fatal("Render succeeded with unresolved variables")
+	}
+	if !strings.Contains(err.Error(), "a") || strings.Contains(err.Error(), "a, a") {
+		t.Errorf("error = %v, want deduplicated missing list mentioning a", err)
+	}
+}
@@ -0,0 +1,235 @@
+// Package secrets holds named server-side credentials so clients can refer
+// to them without ever holding the raw values. With a path and a master
+// secret configured the store is persisted encrypted at rest (AES-GCM);
+// otherwise it stays purely in memory.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// auditLimit caps how many read records are kept; older entries roll off.
+const auditLimit = 256
+
+// AuditEntry records one read of a secret, so operators can see what has
+// been accessing credentials.
+type AuditEntry struct {
+	Name string    `json:"name"`
+	At   time.Time `json:"at"`
+}
+
+// Store is a named secret store. Values go in via Set and are only ever
+// read back server-side; listings expose names alone. Reads are recorded
+// in the audit trail.
+type Store struct {
+	mu     sync.Mutex
+	values map[string]string
+	key    []byte
+	path   string
+	audit  []AuditEntry
+	now    func() time.Time
+}
+
+// NewStore returns an empty in-memory store.
+func NewStore() *Store {
+	return &Store{values: make(map[string]string), now: time.Now}
+}
+
+// deriveKey turns the master secret into an AES-256 key.
+func deriveKey(master string) []byte {
+	sum := sha256.Sum256([]byte("nuvin-secrets:" + master))
+	return sum[:]
+}
+
+// Unlock derives the encryption key from the master secret and, when a
+// path is configured, loads the persisted secrets. A wrong master secret
+// fails here rather than yielding garbage values.
+func (s *Store) Unlock(master string) error {
+	if master == "" {
+		return fmt.Errorf("master secret is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.key = deriveKey(master)
+	return s.loadLocked()
+}
+
+// SetPath points the store at its encrypted file, loading it immediately
+// when the store is already unlocked.
+func (s *Store) SetPath(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.path = path
+	if s.key == nil {
+		return nil
+	}
+	return s.loadLocked()
+}
+
+// Rotate re-encrypts the persisted secrets under a new master secret.
+func (s *Store) Rotate(newMaster string) error {
+	if newMaster == "" {
+		return fmt.Errorf("master secret is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key == nil {
+		return fmt.Errorf("store is locked")
+	}
+	s.key = deriveKey(newMaster)
+	return s.persistLocked()
+}
+
+// Set stores a secret under name; an empty value removes it.
+func (s *Store) Set(name, value string) error {
+	if name == "" {
+		return fmt.Errorf("secret name is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value == "" {
+		delete(s.values, name)
+	} else {
+		s.values[name] = value
+	}
+	return s.persistLocked()
+}
+
+// Get returns the secret value by name, recording the read in the audit
+// trail.
+func (s *Store) Get(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[name]
+	if ok {
+		s.audit = append(s.audit, AuditEntry{Name: name, At: s.now()})
+		if len(s.audit) > auditLimit {
+			s.audit = s.audit[len(s.audit)-auditLimit:]
+		}
+	}
+	return v, ok
+}
+
+// Names lists the stored secret names, sorted.
+func (s *Store) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, 0, len(s.values))
+	for name := range s.values {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Audit returns the recorded reads, oldest first.
+func (s *Store) Audit() []AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]AuditEntry, len(s.audit))
+	copy(out, s.audit)
+	return out
+}
+
+// envelope is the on-disk shape of the encrypted store.
+type envelope struct {
+	Version int    `json:"version"`
+	Nonce   string `json:"nonce"`
+	Data    string `json:"data"`
+}
+
+// persistLocked writes the encrypted file; it is a no-op without a path.
+// With a path but no key the store refuses writes rather than persisting
+// plaintext.
+func (s *Store) persistLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	if s.key == nil {
+		return fmt.Errorf("secret store is locked")
+	}
+	plain, err := json.Marshal(s.values)
+	if err != nil {
+		return fmt.Errorf("saving secrets: %w", err)
+	}
+	gcm, err := s.gcmLocked()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("saving secrets: %w", err)
+	}
+	sealed := gcm.Seal(nil, nonce, plain, nil)
+	raw, err := json.MarshalIndent(envelope{
+		Version: 1,
+		Nonce:   base64.StdEncoding.EncodeToString(nonce),
+		Data:    base64.StdEncoding.EncodeToString(sealed),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("saving secrets: %w", err)
+	}
+	if err := os.WriteFile(s.path, raw, 0o600); err != nil {
+		return fmt.Errorf("saving secrets: %w", err)
+	}
+	return nil
+}
+
+// loadLocked decrypts the persisted file into the store.
+func (s *Store) loadLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("loading secrets: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return fmt.Errorf("loading secrets: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(env.Nonce)
+	if err != nil {
+		return fmt.Errorf("loading secrets: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(env.Data)
+	if err != nil {
+		return fmt.Errorf("loading secrets: %w", err)
+	}
+	gcm, err := s.gcmLocked()
+	if err != nil {
+		return err
+	}
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return fmt.Errorf("loading secrets: wrong master secret or corrupt file")
+	}
+	values := make(map[string]string)
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return fmt.Errorf("loading secrets: %w", err)
+	}
+	s.values = values
+	return nil
+}
+
+func (s *Store) gcmLocked() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
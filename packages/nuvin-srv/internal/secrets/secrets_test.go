@@ -0,0 +1,118 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInMemoryStore(t *testing.T) {
+	s := NewStore()
+	if err := s.Set("openai", "sk-1"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := s.Get("openai"); !ok || v != "sk-1" {
+		t.Fatalf("Get = %q %v", v, ok)
+	}
+	if err := s.Set("openai", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("openai"); ok {
+		t.Fatal("secret survived removal")
+	}
+}
+
+func TestEncryptedPersistenceRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	s := NewStore()
+	if err := s.Unlock("master-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetPath(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("provider/openai", "sk-secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "sk-secret") || strings.Contains(string(raw), "openai") {
+		t.Fatalf("plaintext leaked to disk: %s", raw)
+	}
+
+	// A fresh store with the right master secret reads it back.
+	s2 := NewStore()
+	if err := s2.SetPath(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.Unlock("master-1"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := s2.Get("provider/openai"); !ok || v != "sk-secret" {
+		t.Fatalf("Get after reload = %q %v", v, ok)
+	}
+
+	// The wrong master secret is rejected outright.
+	s3 := NewStore()
+	s3.SetPath(path)
+	if err := s3.Unlock("wrong"); err == nil {
+		t.Fatal("wrong master secret accepted")
+	}
+}
+
+func TestRotateReencrypts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	s := NewStore()
+	s.Unlock("old-master")
+	s.SetPath(path)
+	if err := s.Set("k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Rotate("new-master"); err != nil {
+		t.Fatal(err)
+	}
+
+	reread := NewStore()
+	reread.SetPath(path)
+	if err := reread.Unlock("old-master"); err == nil {
+		t.Fatal("old master secret still works after rotation")
+	}
+	if err := reread.Unlock("new-master"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := reread.Get("k"); v != "v" {
+		t.Fatalf("Get = %q", v)
+	}
+}
+
+func TestLockedStoreRefusesPersistence(t *testing.T) {
+	s := NewStore()
+	s.SetPath(filepath.Join(t.TempDir(), "secrets.json"))
+	if err := s.Set("k", "v"); err == nil {
+		t.Fatal("locked store persisted a secret")
+	}
+	if err := s.Rotate("x"); err == nil {
+		t.Fatal("locked store rotated")
+	}
+}
+
+func TestAuditTrailRecordsReads(t *testing.T) {
+	s := NewStore()
+	s.Set("a", "1")
+	s.Set("b", "2")
+	s.Get("a")
+	s.Get("b")
+	s.Get("missing")
+
+	audit := s.Audit()
+	if len(audit) != 2 || audit[0].Name != "a" || audit[1].Name != "b" {
+		t.Fatalf("audit = %+v", audit)
+	}
+	if audit[0].At.IsZero() {
+		t.Error("audit entry missing timestamp")
+	}
+}
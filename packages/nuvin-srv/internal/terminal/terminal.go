@@ -0,0 +1,147 @@
+// Package terminal manages PTY-backed shell sessions for the embedded
+// terminal in the frontend. Sessions are created over HTTP and bridged to
+// the UI over WebSocket by handlers.TerminalWSHandler.
+package terminal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// Session is one live PTY-backed shell.
+type Session struct {
+	ID        string    `json:"id"`
+	Shell     string    `json:"shell"`
+	Cwd       string    `json:"cwd,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	cmd  *exec.Cmd
+	ptmx *os.File
+
+	closeOnce sync.Once
+}
+
+// Read reads PTY output (what the shell writes to the terminal).
+func (s *Session) Read(p []byte) (int, error) { return s.ptmx.Read(p) }
+
+// Write sends input to the shell as if typed.
+func (s *Session) Write(p []byte) (int, error) { return s.ptmx.Write(p) }
+
+// Resize changes the PTY window size.
+func (s *Session) Resize(cols, rows uint16) error {
+	return pty.Setsize(s.ptmx, &pty.Winsize{Cols: cols, Rows: rows})
+}
+
+// close kills the shell and releases the PTY. Idempotent.
+func (s *Session) close() {
+	s.closeOnce.Do(func() {
+		if s.cmd.Process != nil {
+			_ = s.cmd.Process.Kill()
+		}
+		s.ptmx.Close()
+		go s.cmd.Wait()
+	})
+}
+
+// Manager tracks live terminal sessions.
+type Manager struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewManager returns an empty session table.
+func NewManager() *Manager {
+	return &Manager{sessions: make(map[string]*Session)}
+}
+
+// CreateOptions configure a new session.
+type CreateOptions struct {
+	// Shell is the program to run; empty means $SHELL, then /bin/sh.
+	Shell string `json:"shell,omitempty"`
+	Cwd   string `json:"cwd,omitempty"`
+	Cols  uint16 `json:"cols,omitempty"`
+	Rows  uint16 `json:"rows,omitempty"`
+}
+
+// Create starts a shell on a new PTY and registers the session.
+func (m *Manager) Create(opts CreateOptions) (*Session, error) {
+	shell := opts.Shell
+	if shell == "" {
+		shell = os.Getenv("SHELL")
+	}
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	cmd := exec.Command(shell)
+	cmd.Dir = opts.Cwd
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+
+	size := &pty.Winsize{Cols: 80, Rows: 24}
+	if opts.Cols > 0 {
+		size.Cols = opts.Cols
+	}
+	if opts.Rows > 0 {
+		size.Rows = opts.Rows
+	}
+	ptmx, err := pty.StartWithSize(cmd, size)
+	if err != nil {
+		return nil, fmt.Errorf("starting shell %q: %w", shell, err)
+	}
+
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	s := &Session{
+		ID:        "term-" + hex.EncodeToString(buf),
+		Shell:     shell,
+		Cwd:       opts.Cwd,
+		CreatedAt: time.Now().UTC(),
+		cmd:       cmd,
+		ptmx:      ptmx,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID] = s
+	return s, nil
+}
+
+// Get returns a live session by ID.
+func (m *Manager) Get(id string) (*Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	return s, ok
+}
+
+// List returns all live sessions sorted by creation time.
+func (m *Manager) List() []*Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Kill terminates a session's shell and removes it from the table.
+func (m *Manager) Kill(id string) bool {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	delete(m.sessions, id)
+	m.mu.Unlock()
+	if ok {
+		s.close()
+	}
+	return ok
+}
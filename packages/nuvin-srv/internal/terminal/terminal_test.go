@@ -0,0 +1,56 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	m := NewManager()
+	s, err := m.Create(CreateOptions{Shell: "/bin/sh", Cols: 100, Rows: 30})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(s.ID, "term-") {
+		t.Errorf("ID = %q", s.ID)
+	}
+
+	if _, err := s.Write([]byte("echo term-ok\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// The PTY echoes input and then prints the command output.
+	deadline := time.Now().Add(5 * time.Second)
+	var out strings.Builder
+	buf := make([]byte, 4096)
+	for time.Now().Before(deadline) {
+		n, err := s.Read(buf)
+		if n > 0 {
+			out.Write(buf[:n])
+		}
+		if strings.Count(out.String(), "term-ok") >= 2 || err != nil {
+			break
+		}
+	}
+	if !strings.Contains(out.String(), "term-ok") {
+		t.Errorf("PTY output %q does not contain command output", out.String())
+	}
+
+	if err := s.Resize(120, 40); err != nil {
+		t.Errorf("Resize: %v", err)
+	}
+
+	if got := m.List(); len(got) != 1 || got[0].ID != s.ID {
+		t.Errorf("List = %+v", got)
+	}
+	if !m.Kill(s.ID) {
+		t.Error("Kill returned false for live session")
+	}
+	if _, ok := m.Get(s.ID); ok {
+		t.Error("session still present after Kill")
+	}
+	if m.Kill(s.ID) {
+		t.Error("Kill returned true for dead session")
+	}
+}
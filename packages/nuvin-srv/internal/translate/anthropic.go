@@ -0,0 +1,151 @@
+package translate
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// defaultAnthropicMaxTokens fills the Messages API's required max_tokens
+// when the OpenAI request leaves it unset.
+const defaultAnthropicMaxTokens = 4096
+
+// toAnthropic converts an OpenAI chat request to an Anthropic Messages API
+// body. System messages move into the top-level system field.
+func toAnthropic(req chatRequest) ([]byte, string, error) {
+	var system strings.Builder
+	messages := make([]map[string]any, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		text := m.text()
+		switch m.Role {
+		case "system", "developer":
+			if system.Len() > 0 {
+				system.WriteString("\n\n")
+			}
+			system.WriteString(text)
+		case "assistant":
+			messages = append(messages, map[string]any{"role": "assistant", "content": text})
+		default:
+			messages = append(messages, map[string]any{"role": "user", "content": text})
+		}
+	}
+
+	maxTokens := req.maxTokens()
+	if maxTokens <= 0 {
+		maxTokens = defaultAnthropicMaxTokens
+	}
+	out := map[string]any{
+		"model":      req.Model,
+		"max_tokens": maxTokens,
+		"messages":   messages,
+	}
+	if system.Len() > 0 {
+		out["system"] = system.String()
+	}
+	if req.Temperature != nil {
+		out["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		out["top_p"] = *req.TopP
+	}
+	if stops := req.stopSequences(); len(stops) > 0 {
+		out["stop_sequences"] = stops
+	}
+	if req.Stream {
+		out["stream"] = true
+	}
+	raw, err := json.Marshal(out)
+	return raw, "v1/messages", err
+}
+
+// anthropicFinishReason maps Messages API stop reasons to OpenAI ones.
+func anthropicFinishReason(stopReason string) string {
+	switch stopReason {
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	default:
+		return "stop"
+	}
+}
+
+// fromAnthropic converts a non-streaming Messages API response to an OpenAI
+// chat completion.
+func fromAnthropic(body []byte) ([]byte, error) {
+	var resp struct {
+		ID      string `json:"id"`
+		Model   string `json:"model"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	var content strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			content.WriteString(c.Text)
+		}
+	}
+	return chatResponse(resp.ID, resp.Model, content.String(), anthropicFinishReason(resp.StopReason),
+		resp.Usage.InputTokens, resp.Usage.OutputTokens)
+}
+
+// anthropicChunk translates one Messages API streaming event. message_start
+// opens the assistant turn (and carries input tokens), content_block_delta
+// carries text, message_delta closes the turn with the stop reason and
+// output tokens; everything else is bookkeeping.
+func (s *Stream) anthropicChunk(data []byte) [][]byte {
+	var event struct {
+		Type    string `json:"type"`
+		Message struct {
+			ID    string `json:"id"`
+			Model string `json:"model"`
+			Usage struct {
+				InputTokens int `json:"input_tokens"`
+			} `json:"usage"`
+		} `json:"message"`
+		Delta struct {
+			Type       string `json:"type"`
+			Text       string `json:"text"`
+			StopReason string `json:"stop_reason"`
+		} `json:"delta"`
+		Usage struct {
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil
+	}
+	switch event.Type {
+	case "message_start":
+		if event.Message.ID != "" {
+			s.id = event.Message.ID
+		}
+		if event.Message.Model != "" {
+			s.model = event.Message.Model
+		}
+		s.promptTokens = event.Message.Usage.InputTokens
+		return [][]byte{s.chunk(map[string]any{"role": "assistant", "content": ""}, nil, false)}
+	case "content_block_delta":
+		if event.Delta.Type != "text_delta" || event.Delta.Text == "" {
+			return nil
+		}
+		return [][]byte{s.chunk(map[string]any{"content": event.Delta.Text}, nil, false)}
+	case "message_delta":
+		if event.Usage.OutputTokens > 0 {
+			s.completionTokens = event.Usage.OutputTokens
+		}
+		finish := anthropicFinishReason(event.Delta.StopReason)
+		return [][]byte{s.chunk(map[string]any{}, &finish, true)}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+package translate
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// toGemini converts an OpenAI chat request to a Gemini generateContent body.
+// The model rides in the URL, not the body; streaming uses the
+// streamGenerateContent verb with SSE framing.
+func toGemini(req chatRequest) ([]byte, string, error) {
+	var contents []map[string]any
+	var systemParts []map[string]any
+	for _, m := range req.Messages {
+		part := map[string]any{"text": m.text()}
+		switch m.Role {
+		case "system", "developer":
+			systemParts = append(systemParts, part)
+		case "assistant":
+			contents = append(contents, map[string]any{"role": "model", "parts": []map[string]any{part}})
+		default:
+			contents = append(contents, map[string]any{"role": "user", "parts": []map[string]any{part}})
+		}
+	}
+
+	out := map[string]any{"contents": contents}
+	if len(systemParts) > 0 {
+		out["systemInstruction"] = map[string]any{"parts": systemParts}
+	}
+	config := map[string]any{}
+	if req.Temperature != nil {
+		config["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		config["topP"] = *req.TopP
+	}
+	if maxTokens := req.maxTokens(); maxTokens > 0 {
+		config["maxOutputTokens"] = maxTokens
+	}
+	if stops := req.stopSequences(); len(stops) > 0 {
+		config["stopSequences"] = stops
+	}
+	if len(config) > 0 {
+		out["generationConfig"] = config
+	}
+
+	verb := ":generateContent"
+	if req.Stream {
+		verb = ":streamGenerateContent?alt=sse"
+	}
+	raw, err := json.Marshal(out)
+	return raw, "v1beta/models/" + req.Model + verb, err
+}
+
+// geminiPayload is the shape shared by full responses and SSE chunks.
+type geminiPayload struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+		FinishReason string `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+func (p geminiPayload) text() string {
+	var b strings.Builder
+	for _, c := range p.Candidates {
+		for _, part := range c.Content.Parts {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
+
+// geminiFinishReason maps Gemini finish reasons to OpenAI ones.
+func geminiFinishReason(reason string) string {
+	switch reason {
+	case "MAX_TOKENS":
+		return "length"
+	default:
+		return "stop"
+	}
+}
+
+// fromGemini converts a non-streaming generateContent response to an OpenAI
+// chat completion. Gemini does not echo the model, so the caller supplies it.
+func fromGemini(body []byte, model string) ([]byte, error) {
+	var resp geminiPayload
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	finish := "stop"
+	if len(resp.Candidates) > 0 {
+		finish = geminiFinishReason(resp.Candidates[0].FinishReason)
+	}
+	return chatResponse("", model, resp.text(), finish,
+		resp.UsageMetadata.PromptTokenCount, resp.UsageMetadata.CandidatesTokenCount)
+}
+
+// geminiChunk translates one streamGenerateContent SSE payload. Each chunk
+// carries a text fragment; the final one carries the finish reason, and
+// usage metadata arrives cumulatively.
+func (s *Stream) geminiChunk(data []byte) [][]byte {
+	var payload geminiPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil
+	}
+	if payload.UsageMetadata.PromptTokenCount > 0 {
+		s.promptTokens = payload.UsageMetadata.PromptTokenCount
+	}
+	if payload.UsageMetadata.CandidatesTokenCount > 0 {
+		s.completionTokens = payload.UsageMetadata.CandidatesTokenCount
+	}
+
+	delta := map[string]any{}
+	if text := payload.text(); text != "" {
+		delta["content"] = text
+	}
+	var finish *string
+	if len(payload.Candidates) > 0 && payload.Candidates[0].FinishReason != "" {
+		f := geminiFinishReason(payload.Candidates[0].FinishReason)
+		finish = &f
+	}
+	if len(delta) == 0 && finish == nil {
+		return nil
+	}
+	return [][]byte{s.chunk(delta, finish, finish != nil)}
+}
@@ -0,0 +1,212 @@
+// Package translate converts chat completions between provider protocols.
+// The frontend and SDKs speak the OpenAI chat format; routes whose upstream
+// natively speaks the Anthropic Messages API or the Google Gemini API have
+// their requests translated on the way in and their responses — including
+// streaming deltas — converted back, so every provider looks the same to
+// callers.
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Protocols a route can declare. Empty means the upstream already speaks
+// the OpenAI protocol and no translation happens.
+const (
+	ProtocolAnthropic = "anthropic"
+	ProtocolGemini    = "gemini"
+)
+
+// Translates reports whether protocol requires request/response translation.
+func Translates(protocol string) bool {
+	return protocol == ProtocolAnthropic || protocol == ProtocolGemini
+}
+
+// chatMessage is one OpenAI-format chat message. Content is either a plain
+// string or an array of typed parts; text() flattens both.
+type chatMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+func (m chatMessage) text() string {
+	var s string
+	if json.Unmarshal(m.Content, &s) == nil {
+		return s
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(m.Content, &parts) == nil {
+		var b strings.Builder
+		for _, p := range parts {
+			if p.Type == "" || p.Type == "text" {
+				b.WriteString(p.Text)
+			}
+		}
+		return b.String()
+	}
+	return ""
+}
+
+// chatRequest is the OpenAI chat completion request subset the translators
+// understand.
+type chatRequest struct {
+	Model               string          `json:"model"`
+	Messages            []chatMessage   `json:"messages"`
+	MaxTokens           int             `json:"max_tokens"`
+	MaxCompletionTokens int             `json:"max_completion_tokens"`
+	Temperature         *float64        `json:"temperature"`
+	TopP                *float64        `json:"top_p"`
+	Stop                json.RawMessage `json:"stop"`
+	Stream              bool            `json:"stream"`
+}
+
+func (r chatRequest) maxTokens() int {
+	if r.MaxTokens > 0 {
+		return r.MaxTokens
+	}
+	return r.MaxCompletionTokens
+}
+
+// stopSequences flattens the OpenAI "stop" field (string or array).
+func (r chatRequest) stopSequences() []string {
+	if len(r.Stop) == 0 {
+		return nil
+	}
+	var one string
+	if json.Unmarshal(r.Stop, &one) == nil {
+		return []string{one}
+	}
+	var many []string
+	if json.Unmarshal(r.Stop, &many) == nil {
+		return many
+	}
+	return nil
+}
+
+// Request converts an OpenAI-format chat request into the target protocol's
+// body, returning the upstream path to send it to (relative, possibly with a
+// query string), whether the caller asked for streaming, and the model name.
+func Request(protocol string, body []byte) (out []byte, path string, stream bool, model string, err error) {
+	var req chatRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, "", false, "", fmt.Errorf("invalid chat request: %w", err)
+	}
+	if req.Model == "" {
+		return nil, "", false, "", fmt.Errorf("chat request: model is required")
+	}
+	switch protocol {
+	case ProtocolAnthropic:
+		out, path, err = toAnthropic(req)
+	case ProtocolGemini:
+		out, path, err = toGemini(req)
+	default:
+		return nil, "", false, "", fmt.Errorf("unsupported protocol %q", protocol)
+	}
+	return out, path, req.Stream, req.Model, err
+}
+
+// Response converts a provider's non-streaming chat response back to the
+// OpenAI format. model fills the response's model field for providers that
+// do not echo it.
+func Response(protocol string, body []byte, model string) ([]byte, error) {
+	switch protocol {
+	case ProtocolAnthropic:
+		return fromAnthropic(body)
+	case ProtocolGemini:
+		return fromGemini(body, model)
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+}
+
+// chatResponse assembles an OpenAI-format chat completion.
+func chatResponse(id, model, content, finishReason string, promptTokens, completionTokens int) ([]byte, error) {
+	if id == "" {
+		id = "chatcmpl-nuvin"
+	}
+	out := map[string]any{
+		"id":      id,
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   model,
+		"choices": []any{map[string]any{
+			"index":         0,
+			"message":       map[string]any{"role": "assistant", "content": content},
+			"finish_reason": finishReason,
+		}},
+		"usage": map[string]any{
+			"prompt_tokens":     promptTokens,
+			"completion_tokens": completionTokens,
+			"total_tokens":      promptTokens + completionTokens,
+		},
+	}
+	return json.Marshal(out)
+}
+
+// Stream translates one provider's streaming events into OpenAI chat
+// completion chunks. One Stream serves one response; it accumulates token
+// usage as the provider reports it.
+type Stream struct {
+	protocol string
+	model    string
+	id       string
+
+	promptTokens     int
+	completionTokens int
+}
+
+// NewStream returns a translator for one streaming response.
+func NewStream(protocol, model string) *Stream {
+	return &Stream{protocol: protocol, model: model, id: "chatcmpl-nuvin"}
+}
+
+// Chunk translates one SSE data payload from the provider into zero or more
+// OpenAI-format chunk payloads (without the "data: " framing). Unparseable
+// or bookkeeping-only events yield nothing.
+func (s *Stream) Chunk(data []byte) [][]byte {
+	switch s.protocol {
+	case ProtocolAnthropic:
+		return s.anthropicChunk(data)
+	case ProtocolGemini:
+		return s.geminiChunk(data)
+	default:
+		return nil
+	}
+}
+
+// Usage returns the model and token counts accumulated from the stream.
+func (s *Stream) Usage() (model string, promptTokens, completionTokens int) {
+	return s.model, s.promptTokens, s.completionTokens
+}
+
+// chunk assembles one OpenAI chat.completion.chunk payload.
+func (s *Stream) chunk(delta map[string]any, finishReason *string, withUsage bool) []byte {
+	out := map[string]any{
+		"id":     s.id,
+		"object": "chat.completion.chunk",
+		"model":  s.model,
+		"choices": []any{map[string]any{
+			"index":         0,
+			"delta":         delta,
+			"finish_reason": finishReason,
+		}},
+	}
+	if withUsage {
+		out["usage"] = map[string]any{
+			"prompt_tokens":     s.promptTokens,
+			"completion_tokens": s.completionTokens,
+			"total_tokens":      s.promptTokens + s.completionTokens,
+		}
+	}
+	raw, err := json.Marshal(out)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
@@ -0,0 +1,234 @@
+package translate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRequestToAnthropic(t *testing.T) {
+	body := []byte(`{
+		"model": "claude-3-5-sonnet",
+		"messages": [
+			{"role": "system", "content": "be brief"},
+			{"role": "user", "content": "hi"},
+			{"role": "assistant", "content": "hello"},
+			{"role": "user", "content": [{"type": "text", "text": "continue"}]}
+		],
+		"max_tokens": 256,
+		"temperature": 0.5,
+		"stop": ["END"],
+		"stream": true
+	}`)
+	out, path, stream, model, err := Request(ProtocolAnthropic, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "v1/messages" || !stream || model != "claude-3-5-sonnet" {
+		t.Errorf("path=%q stream=%v model=%q", path, stream, model)
+	}
+	var req map[string]any
+	if err := json.Unmarshal(out, &req); err != nil {
+		t.Fatal(err)
+	}
+	if req["system"] != "be brief" {
+		t.Errorf("system = %v, want the system message", req["system"])
+	}
+	if req["max_tokens"] != float64(256) || req["temperature"] != 0.5 || req["stream"] != true {
+		t.Errorf("request = %v", req)
+	}
+	msgs := req["messages"].([]any)
+	if len(msgs) != 3 {
+		t.Fatalf("got %d messages, want 3 (system lifted out)", len(msgs))
+	}
+	last := msgs[2].(map[string]any)
+	if last["role"] != "user" || last["content"] != "continue" {
+		t.Errorf("last message = %v, want flattened text parts", last)
+	}
+}
+
+func TestRequestToAnthropicDefaultsMaxTokens(t *testing.T) {
+	out, _, _, _, err := Request(ProtocolAnthropic, []byte(`{"model":"claude-3-5-haiku","messages":[{"role":"user","content":"hi"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req map[string]any
+	if err := json.Unmarshal(out, &req); err != nil {
+		t.Fatal(err)
+	}
+	if req["max_tokens"] != float64(defaultAnthropicMaxTokens) {
+		t.Errorf("max_tokens = %v, want default %d", req["max_tokens"], defaultAnthropicMaxTokens)
+	}
+}
+
+func TestResponseFromAnthropic(t *testing.T) {
+	body := []byte(`{
+		"id": "msg_1", "model": "claude-3-5-sonnet",
+		"content": [{"type": "text", "text": "hello "}, {"type": "text", "text": "there"}],
+		"stop_reason": "max_tokens",
+		"usage": {"input_tokens": 11, "output_tokens": 7}
+	}`)
+	out, err := Response(ProtocolAnthropic, body, "claude-3-5-sonnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		ID      string `json:"id"`
+		Model   string `json:"model"`
+		Choices []struct {
+			Message      struct{ Role, Content string } `json:"message"`
+			FinishReason string                         `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			TotalTokens      int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != "msg_1" || resp.Model != "claude-3-5-sonnet" {
+		t.Errorf("id=%q model=%q", resp.ID, resp.Model)
+	}
+	c := resp.Choices[0]
+	if c.Message.Content != "hello there" || c.FinishReason != "length" {
+		t.Errorf("choice = %+v", c)
+	}
+	if resp.Usage.PromptTokens != 11 || resp.Usage.CompletionTokens != 7 || resp.Usage.TotalTokens != 18 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestRequestToGemini(t *testing.T) {
+	body := []byte(`{
+		"model": "gemini-1.5-pro",
+		"messages": [
+			{"role": "system", "content": "be brief"},
+			{"role": "user", "content": "hi"},
+			{"role": "assistant", "content": "hello"}
+		],
+		"max_tokens": 100,
+		"stream": true
+	}`)
+	out, path, stream, model, err := Request(ProtocolGemini, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "v1beta/models/gemini-1.5-pro:streamGenerateContent?alt=sse" || !stream || model != "gemini-1.5-pro" {
+		t.Errorf("path=%q stream=%v model=%q", path, stream, model)
+	}
+	var req map[string]any
+	if err := json.Unmarshal(out, &req); err != nil {
+		t.Fatal(err)
+	}
+	contents := req["contents"].([]any)
+	if len(contents) != 2 {
+		t.Fatalf("got %d contents, want 2", len(contents))
+	}
+	if contents[1].(map[string]any)["role"] != "model" {
+		t.Errorf("assistant role = %v, want model", contents[1])
+	}
+	if req["systemInstruction"] == nil {
+		t.Error("systemInstruction missing")
+	}
+	config := req["generationConfig"].(map[string]any)
+	if config["maxOutputTokens"] != float64(100) {
+		t.Errorf("generationConfig = %v", config)
+	}
+}
+
+func TestResponseFromGemini(t *testing.T) {
+	body := []byte(`{
+		"candidates": [{"content": {"parts": [{"text": "hi there"}], "role": "model"}, "finishReason": "STOP"}],
+		"usageMetadata": {"promptTokenCount": 4, "candidatesTokenCount": 3}
+	}`)
+	out, err := Response(ProtocolGemini, body, "gemini-1.5-pro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message      struct{ Content string } `json:"message"`
+			FinishReason string                   `json:"finish_reason"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Model != "gemini-1.5-pro" || resp.Choices[0].Message.Content != "hi there" || resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("response = %s", out)
+	}
+}
+
+func TestStreamAnthropicChunks(t *testing.T) {
+	s := NewStream(ProtocolAnthropic, "claude-3-5-sonnet")
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_1","model":"claude-3-5-sonnet","usage":{"input_tokens":9}}}`,
+		`{"type":"content_block_start","content_block":{"type":"text"}}`,
+		`{"type":"content_block_delta","delta":{"type":"text_delta","text":"hel"}}`,
+		`{"type":"content_block_delta","delta":{"type":"text_delta","text":"lo"}}`,
+		`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":5}}`,
+		`{"type":"message_stop"}`,
+	}
+	var text strings.Builder
+	var finish string
+	for _, ev := range events {
+		for _, chunk := range s.Chunk([]byte(ev)) {
+			var c struct {
+				Choices []struct {
+					Delta        struct{ Content string } `json:"delta"`
+					FinishReason *string                  `json:"finish_reason"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal(chunk, &c); err != nil {
+				t.Fatalf("chunk %s: %v", chunk, err)
+			}
+			text.WriteString(c.Choices[0].Delta.Content)
+			if c.Choices[0].FinishReason != nil {
+				finish = *c.Choices[0].FinishReason
+			}
+		}
+	}
+	if text.String() != "hello" || finish != "stop" {
+		t.Errorf("text=%q finish=%q", text.String(), finish)
+	}
+	model, promptTokens, completionTokens := s.Usage()
+	if model != "claude-3-5-sonnet" || promptTokens != 9 || completionTokens != 5 {
+		t.Errorf("usage = %s %d/%d", model, promptTokens, completionTokens)
+	}
+}
+
+func TestStreamGeminiChunks(t *testing.T) {
+	s := NewStream(ProtocolGemini, "gemini-1.5-pro")
+	events := []string{
+		`{"candidates":[{"content":{"parts":[{"text":"hi "}]}}]}`,
+		`{"candidates":[{"content":{"parts":[{"text":"there"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":4,"candidatesTokenCount":2}}`,
+	}
+	var text strings.Builder
+	var finish string
+	for _, ev := range events {
+		for _, chunk := range s.Chunk([]byte(ev)) {
+			var c struct {
+				Choices []struct {
+					Delta        struct{ Content string } `json:"delta"`
+					FinishReason *string                  `json:"finish_reason"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal(chunk, &c); err != nil {
+				t.Fatalf("chunk %s: %v", chunk, err)
+			}
+			text.WriteString(c.Choices[0].Delta.Content)
+			if c.Choices[0].FinishReason != nil {
+				finish = *c.Choices[0].FinishReason
+			}
+		}
+	}
+	if text.String() != "hi there" || finish != "stop" {
+		t.Errorf("text=%q finish=%q", text.String(), finish)
+	}
+	if _, promptTokens, completionTokens := s.Usage(); promptTokens != 4 || completionTokens != 2 {
+		t.Errorf("usage = %d/%d", promptTokens, completionTokens)
+	}
+}
@@ -0,0 +1,122 @@
+// Package urlcheck validates and normalizes upstream URLs before FetchProxy
+// and the route proxy dispatch them.
+package urlcheck
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+// Error codes returned in ValidationError.Code.
+const (
+	CodeMalformed        = "malformed_url"
+	CodeSchemeNotAllowed = "scheme_not_allowed"
+	CodeMissingHost      = "missing_host"
+	CodeInvalidHost      = "invalid_host"
+)
+
+// ValidationError explains why a URL was rejected, with a stable code the
+// frontend can branch on.
+type ValidationError struct {
+	URL    string `json:"url"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid url %q: %s", e.URL, e.Reason)
+}
+
+// allowedSchemes is the closed set of schemes the server will dial. Anything
+// else (file, gopher, ftp, javascript, ...) is rejected outright.
+var allowedSchemes = map[string]bool{
+	"http":  true,
+	"https": true,
+	"ws":    true,
+	"wss":   true,
+}
+
+// defaultPorts lets Normalize drop a redundant :80/:443 so cache and
+// rate-limit keys treat the same origin identically.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+}
+
+// Normalize validates rawURL and returns its canonical form: scheme and host
+// lowercased, IDN hosts converted to punycode under IDNA 2008 lookup rules
+// (which reject mixed-script homograph tricks), and default ports stripped.
+func Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", &ValidationError{URL: rawURL, Code: CodeMalformed, Reason: err.Error()}
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "" {
+		return "", &ValidationError{URL: rawURL, Code: CodeMalformed, Reason: "missing scheme"}
+	}
+	if !allowedSchemes[scheme] {
+		return "", &ValidationError{URL: rawURL, Code: CodeSchemeNotAllowed, Reason: "scheme " + scheme + " is not allowed"}
+	}
+	if u.Host == "" {
+		return "", &ValidationError{URL: rawURL, Code: CodeMissingHost, Reason: "missing host"}
+	}
+
+	host := strings.ToLower(u.Hostname())
+	ascii := host
+	if ip := net.ParseIP(host); ip == nil {
+		// IDNA lookup rules only apply to domain names, not IP literals.
+		if label, mixed := mixedScriptLabel(host); mixed {
+			return "", &ValidationError{URL: rawURL, Code: CodeInvalidHost,
+				Reason: fmt.Sprintf("label %q mixes confusable scripts", label)}
+		}
+		ascii, err = idna.Lookup.ToASCII(host)
+		if err != nil {
+			return "", &ValidationError{URL: rawURL, Code: CodeInvalidHost, Reason: "host fails IDNA lookup rules: " + err.Error()}
+		}
+	} else if ip.To4() == nil {
+		ascii = "[" + host + "]"
+	}
+
+	u.Scheme = scheme
+	if port := u.Port(); port != "" && port != defaultPorts[scheme] {
+		u.Host = ascii + ":" + port
+	} else {
+		u.Host = ascii
+	}
+	return u.String(), nil
+}
+
+// mixedScriptLabel reports whether any host label mixes Latin with Cyrillic
+// or Greek — the classic homograph trick (https://pаypal.com with a Cyrillic
+// а). IDNA itself allows such labels, so they are rejected here.
+func mixedScriptLabel(host string) (string, bool) {
+	for _, label := range strings.Split(host, ".") {
+		var latin, confusable bool
+		for _, r := range label {
+			switch {
+			case unicode.Is(unicode.Latin, r):
+				latin = true
+			case unicode.Is(unicode.Cyrillic, r) || unicode.Is(unicode.Greek, r):
+				confusable = true
+			}
+		}
+		if latin && confusable {
+			return label, true
+		}
+	}
+	return "", false
+}
+
+// Validate checks rawURL without returning the normalized form.
+func Validate(rawURL string) error {
+	_, err := Normalize(rawURL)
+	return err
+}
@@ -0,0 +1,62 @@
+package urlcheck
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"HTTPS://API.Example.COM/v1/Chat", "https://api.example.com/v1/Chat"},
+		{"https://api.example.com:443/v1", "https://api.example.com/v1"},
+		{"http://api.example.com:8080/v1", "http://api.example.com:8080/v1"},
+		{"https://bücher.example/x", "https://xn--bcher-kva.example/x"},
+		{"http://127.0.0.1:8787/fetch", "http://127.0.0.1:8787/fetch"},
+		{"http://[::1]:8080/x", "http://[::1]:8080/x"},
+		{"wss://relay.example.com/socket", "wss://relay.example.com/socket"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.in)
+		if err != nil {
+			t.Errorf("Normalize(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeRejects(t *testing.T) {
+	tests := []struct {
+		in   string
+		code string
+	}{
+		{"file:///etc/passwd", CodeSchemeNotAllowed},
+		{"gopher://evil.example/x", CodeSchemeNotAllowed},
+		{"javascript:alert(1)", CodeSchemeNotAllowed},
+		{"/relative/path", CodeMalformed},
+		{"https://", CodeMissingHost},
+		{"http://exa mple.com/", CodeMalformed},
+		// Mixed-script homograph: Cyrillic а in an otherwise Latin label.
+		{"https://pаypal.com/login", CodeInvalidHost},
+	}
+	for _, tt := range tests {
+		_, err := Normalize(tt.in)
+		if err == nil {
+			t.Errorf("Normalize(%q) accepted, want rejection", tt.in)
+			continue
+		}
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("Normalize(%q) error = %T, want *ValidationError", tt.in, err)
+			continue
+		}
+		if verr.Code != tt.code {
+			t.Errorf("Normalize(%q) code = %q, want %q", tt.in, verr.Code, tt.code)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// recordCapacity bounds the in-memory accounting log; the oldest entries
+// are evicted first.
+const recordCapacity = 10000
+
+// Record is one proxied LLM request's accounting entry.
+type Record struct {
+	Subject          string    `json:"subject"`
+	Route            string    `json:"route"`
+	Model            string    `json:"model,omitempty"`
+	PromptTokens     int       `json:"promptTokens,omitempty"`
+	CompletionTokens int       `json:"completionTokens,omitempty"`
+	Status           int       `json:"status"`
+	DurationMs       int64     `json:"durationMs"`
+	At               time.Time `json:"at"`
+}
+
+// Bucket is one row of a usage summary.
+type Bucket struct {
+	Key              string `json:"key"`
+	Requests         int    `json:"requests"`
+	Errors           int    `json:"errors,omitempty"`
+	PromptTokens     int    `json:"promptTokens"`
+	CompletionTokens int    `json:"completionTokens"`
+	AvgDurationMs    int64  `json:"avgDurationMs"`
+}
+
+// Record appends one accounting entry.
+func (t *Tracker) Record(rec Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if rec.At.IsZero() {
+		rec.At = t.now().UTC()
+	}
+	t.records = append(t.records, rec)
+	if len(t.records) > recordCapacity {
+		t.records = t.records[len(t.records)-recordCapacity:]
+	}
+}
+
+// Records returns a snapshot of the accounting log, oldest first.
+func (t *Tracker) Records() []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Record(nil), t.records...)
+}
+
+// Summarize aggregates the log by "day", "model", or "subject", sorted by
+// bucket key.
+func (t *Tracker) Summarize(by string) ([]Bucket, error) {
+	var key func(Record) string
+	switch by {
+	case "day":
+		key = func(r Record) string { return r.At.UTC().Format("2006-01-02") }
+	case "model":
+		key = func(r Record) string {
+			if r.Model == "" {
+				return "(unknown)"
+			}
+			return r.Model
+		}
+	case "subject":
+		key = func(r Record) string { return r.Subject }
+	default:
+		return nil, fmt.Errorf("unsupported grouping: %q", by)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	byKey := make(map[string]*Bucket)
+	totals := make(map[string]int64)
+	for _, r := range t.records {
+		k := key(r)
+		b := byKey[k]
+		if b == nil {
+			b = &Bucket{Key: k}
+			byKey[k] = b
+		}
+		b.Requests++
+		if r.Status >= 400 || r.Status == 0 {
+			b.Errors++
+		}
+		b.PromptTokens += r.PromptTokens
+		b.CompletionTokens += r.CompletionTokens
+		totals[k] += r.DurationMs
+	}
+	out := make([]Bucket, 0, len(byKey))
+	for k, b := range byKey {
+		b.AvgDurationMs = totals[k] / int64(b.Requests)
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// TokenCounts holds model and token usage parsed from a provider response.
+type TokenCounts struct {
+	Model      string
+	Prompt     int
+	Completion int
+}
+
+type providerUsage struct {
+	Model string `json:"model"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		InputTokens      int `json:"input_tokens"`
+		OutputTokens     int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// ParseResponse extracts the model and token usage from an OpenAI- or
+// Anthropic-style response body. Streamed SSE bodies are scanned data chunk
+// by data chunk, keeping the last usage reported. Fields a provider omits
+// stay zero.
+func ParseResponse(body []byte, streaming bool) TokenCounts {
+	var counts TokenCounts
+	merge := func(raw []byte) {
+		var u providerUsage
+		if err := json.Unmarshal(raw, &u); err != nil {
+			return
+		}
+		if counts.Model == "" {
+			counts.Model = u.Model
+		}
+		if n := u.Usage.PromptTokens + u.Usage.InputTokens; n > 0 {
+			counts.Prompt = n
+		}
+		if n := u.Usage.CompletionTokens + u.Usage.OutputTokens; n > 0 {
+			counts.Completion = n
+		}
+	}
+	if !streaming {
+		merge(body)
+		return counts
+	}
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		data, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:"))
+		if !ok {
+			continue
+		}
+		merge(bytes.TrimSpace(data))
+	}
+	return counts
+}
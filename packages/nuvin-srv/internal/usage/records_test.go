@@ -0,0 +1,91 @@
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeGroupsRecords(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+	tr.now = func() time.Time { return now }
+
+	tr.Record(Record{Subject: "alice", Route: "openai", Model: "gpt-4o", PromptTokens: 100, CompletionTokens: 50, Status: 200, DurationMs: 80})
+	tr.Record(Record{Subject: "alice", Route: "openai", Model: "gpt-4o", PromptTokens: 200, CompletionTokens: 20, Status: 500, DurationMs: 120})
+	now = now.Add(24 * time.Hour)
+	tr.Record(Record{Subject: "bob", Route: "claude", Model: "claude-3-5-sonnet", PromptTokens: 10, CompletionTokens: 5, Status: 200, DurationMs: 40})
+
+	buckets, err := tr.Summarize("day")
+	if err != nil {
+		t.Fatalf("Summarize(day): %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("got %d day buckets, want 2", len(buckets))
+	}
+	first := buckets[0]
+	if first.Key != "2025-01-01" || first.Requests != 2 || first.Errors != 1 {
+		t.Errorf("day bucket = %+v, want key 2025-01-01, 2 requests, 1 error", first)
+	}
+	if first.PromptTokens != 300 || first.CompletionTokens != 70 || first.AvgDurationMs != 100 {
+		t.Errorf("day bucket totals = %+v, want 300/70 tokens, avg 100ms", first)
+	}
+
+	byModel, err := tr.Summarize("model")
+	if err != nil {
+		t.Fatalf("Summarize(model): %v", err)
+	}
+	if len(byModel) != 2 || byModel[0].Key != "claude-3-5-sonnet" || byModel[1].Key != "gpt-4o" {
+		t.Errorf("model buckets = %+v, want claude-3-5-sonnet then gpt-4o", byModel)
+	}
+
+	bySubject, err := tr.Summarize("subject")
+	if err != nil {
+		t.Fatalf("Summarize(subject): %v", err)
+	}
+	if len(bySubject) != 2 || bySubject[0].Key != "alice" || bySubject[0].Requests != 2 {
+		t.Errorf("subject buckets = %+v, want alice with 2 requests first", bySubject)
+	}
+
+	if _, err := tr.Summarize("hour"); err == nil {
+		t.Error("Summarize accepted unsupported grouping")
+	}
+}
+
+func TestRecordCapsLog(t *testing.T) {
+	tr := NewTracker()
+	for i := 0; i < recordCapacity+10; i++ {
+		tr.Record(Record{Subject: "alice", Route: "r", Status: 200})
+	}
+	if got := len(tr.Records()); got != recordCapacity {
+		t.Errorf("log length = %d, want %d", got, recordCapacity)
+	}
+}
+
+func TestParseResponseNonStreaming(t *testing.T) {
+	body := []byte(`{"model":"gpt-4o","usage":{"prompt_tokens":12,"completion_tokens":34}}`)
+	counts := ParseResponse(body, false)
+	if counts.Model != "gpt-4o" || counts.Prompt != 12 || counts.Completion != 34 {
+		t.Errorf("counts = %+v, want gpt-4o 12/34", counts)
+	}
+
+	anthropic := []byte(`{"model":"claude-3-5-sonnet","usage":{"input_tokens":7,"output_tokens":9}}`)
+	counts = ParseResponse(anthropic, false)
+	if counts.Model != "claude-3-5-sonnet" || counts.Prompt != 7 || counts.Completion != 9 {
+		t.Errorf("counts = %+v, want claude-3-5-sonnet 7/9", counts)
+	}
+
+	if counts := ParseResponse([]byte("not json"), false); counts != (TokenCounts{}) {
+		t.Errorf("counts for garbage = %+v, want zero", counts)
+	}
+}
+
+func TestParseResponseStreaming(t *testing.T) {
+	body := []byte("data: {\"model\":\"gpt-4o\"}\n\n" +
+		"data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n" +
+		"data: {\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":8}}\n\n" +
+		"data: [DONE]\n\n")
+	counts := ParseResponse(body, true)
+	if counts.Model != "gpt-4o" || counts.Prompt != 5 || counts.Completion != 8 {
+		t.Errorf("counts = %+v, want gpt-4o 5/8", counts)
+	}
+}
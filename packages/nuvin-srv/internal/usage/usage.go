@@ -0,0 +1,97 @@
+// Package usage tracks per-subject consumption of proxied LLM requests and
+// enforces configurable quotas for shared deployments.
+package usage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Quota caps how many proxied requests a subject may make per period.
+// A zero Limit means unlimited.
+type Quota struct {
+	Limit  int           `json:"limit"`
+	Period time.Duration `json:"period"`
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// Tracker records per-subject request counts in rolling fixed windows and
+// answers quota checks.
+type Tracker struct {
+	mu      sync.Mutex
+	quota   Quota
+	windows map[string]*window
+	records []Record
+	now     func() time.Time
+}
+
+// NewTracker returns a Tracker with no quota configured.
+func NewTracker() *Tracker {
+	return &Tracker{
+		windows: make(map[string]*window),
+		now:     time.Now,
+	}
+}
+
+// SetQuota configures the per-subject quota. A zero limit disables
+// enforcement but counts are still recorded. An enforcing quota needs a
+// period, or the window would never reset.
+func (t *Tracker) SetQuota(q Quota) error {
+	if q.Limit < 0 {
+		return fmt.Errorf("quota limit must not be negative")
+	}
+	if q.Limit > 0 && q.Period <= 0 {
+		return fmt.Errorf("quota with a limit needs a period")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.quota = q
+	return nil
+}
+
+// Quota returns the currently configured quota.
+func (t *Tracker) Quota() Quota {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.quota
+}
+
+// Allow records one request for subject and reports whether it fits within
+// the quota. When the quota is exhausted it returns false along with the
+// time the subject's window resets.
+func (t *Tracker) Allow(subject string) (allowed bool, resetAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	w := t.windows[subject]
+	if w == nil || (t.quota.Period > 0 && now.Sub(w.start) >= t.quota.Period) {
+		w = &window{start: now}
+		t.windows[subject] = w
+	}
+
+	if t.quota.Limit > 0 && w.count >= t.quota.Limit {
+		return false, w.start.Add(t.quota.Period)
+	}
+	w.count++
+	return true, w.start.Add(t.quota.Period)
+}
+
+// Count returns the subject's request count in the current window.
+func (t *Tracker) Count(subject string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w := t.windows[subject]
+	if w == nil {
+		return 0
+	}
+	if t.quota.Period > 0 && t.now().Sub(w.start) >= t.quota.Period {
+		return 0
+	}
+	return w.count
+}
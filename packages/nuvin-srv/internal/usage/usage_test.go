@@ -0,0 +1,64 @@
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackerEnforcesQuotaAndResets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+	tr.now = func() time.Time { return now }
+	tr.SetQuota(Quota{Limit: 3, Period: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := tr.Allow("alice")
+		if !allowed {
+			t.Fatalf("request %d unexpectedly rejected", i+1)
+		}
+	}
+
+	allowed, resetAt := tr.Allow("alice")
+	if allowed {
+		t.Fatal("request over quota was allowed")
+	}
+	if want := now.Add(time.Hour); !resetAt.Equal(want) {
+		t.Errorf("resetAt = %v, want %v", resetAt, want)
+	}
+
+	// Another subject is unaffected.
+	if allowed, _ := tr.Allow("bob"); !allowed {
+		t.Error("quota leaked across subjects")
+	}
+
+	// After the period rolls over the subject gets a fresh window.
+	now = now.Add(time.Hour + time.Minute)
+	if allowed, _ := tr.Allow("alice"); !allowed {
+		t.Error("request rejected after window reset")
+	}
+}
+
+func TestTrackerUnlimitedWhenNoQuota(t *testing.T) {
+	tr := NewTracker()
+	for i := 0; i < 100; i++ {
+		if allowed, _ := tr.Allow("alice"); !allowed {
+			t.Fatal("request rejected with no quota configured")
+		}
+	}
+	if got := tr.Count("alice"); got != 100 {
+		t.Errorf("Count = %d, want 100", got)
+	}
+}
+
+func TestSetQuotaValidation(t *testing.T) {
+	tr := NewTracker()
+	if err := tr.SetQuota(Quota{Limit: 5}); err == nil {
+		t.Error("limit without a period accepted")
+	}
+	if err := tr.SetQuota(Quota{Limit: -1, Period: time.Hour}); err == nil {
+		t.Error("negative limit accepted")
+	}
+	if err := tr.SetQuota(Quota{}); err != nil {
+		t.Errorf("disabling the quota: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Search limits: files larger than this are skipped, and results are capped
+// when the caller sets no limit.
+const (
+	searchMaxFileBytes = 1 << 20 // 1 MiB
+	defaultMaxResults  = 100
+)
+
+// SearchRequest is one "find in project" query.
+type SearchRequest struct {
+	// Query is a regular expression matched per line.
+	Query string `json:"query"`
+	// Glob narrows the search to matching paths; patterns without a slash
+	// match the file name, patterns with one match the workspace-relative path.
+	Glob string `json:"glob,omitempty"`
+	// CaseInsensitive makes the query match regardless of case.
+	CaseInsensitive bool `json:"caseInsensitive,omitempty"`
+	// MaxResults caps the matches returned; 0 means 100.
+	MaxResults int `json:"maxResults,omitempty"`
+	// ContextLines includes that many lines before and after each match.
+	ContextLines int `json:"contextLines,omitempty"`
+}
+
+// SearchMatch is one matching line with its surrounding context.
+type SearchMatch struct {
+	Path   string   `json:"path"`
+	Line   int      `json:"line"`
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// SearchResult carries the matches and whether the cap cut the search short.
+type SearchResult struct {
+	Matches   []SearchMatch `json:"matches"`
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
+// ignoreSet holds .gitignore patterns for the common subset this server
+// supports: comments, blank lines, directory patterns ("dir/"), and glob
+// patterns matched against the base name (no slash) or relative path.
+type ignoreSet struct {
+	patterns []string
+	dirOnly  []bool
+}
+
+func parseIgnoreFile(file string) *ignoreSet {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	set := &ignoreSet{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		dir := strings.HasSuffix(line, "/")
+		set.patterns = append(set.patterns, strings.TrimSuffix(strings.TrimPrefix(line, "/"), "/"))
+		set.dirOnly = append(set.dirOnly, dir)
+	}
+	return set
+}
+
+func (s *ignoreSet) ignored(rel string, isDir bool) bool {
+	if s == nil {
+		return false
+	}
+	base := path.Base(rel)
+	for i, p := range s.patterns {
+		if s.dirOnly[i] && !isDir {
+			continue
+		}
+		target := base
+		if strings.Contains(p, "/") {
+			target = rel
+		}
+		if ok, err := path.Match(p, target); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Search scans text files under the root for lines matching the query.
+func (s *Service) Search(req SearchRequest) (*SearchResult, error) {
+	root := s.Root()
+	if root == "" {
+		return nil, fmt.Errorf("no workspace root configured")
+	}
+	if req.Query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	expr := req.Query
+	if req.CaseInsensitive {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	maxResults := req.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultMaxResults
+	}
+
+	ignore := parseIgnoreFile(filepath.Join(root, ".gitignore"))
+	result := &SearchResult{Matches: []SearchMatch{}}
+
+	err = filepath.WalkDir(root, func(abs string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if d.Name() == ".git" || ignore.ignored(rel, true) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if ignore.ignored(rel, false) || !matchGlob(req.Glob, rel) {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > searchMaxFileBytes {
+			return nil
+		}
+		data, err := os.ReadFile(abs)
+		if err != nil || isBinary(data) {
+			return nil
+		}
+
+		lines := strings.Split(string(data), "\n")
+		for i, line := range lines {
+			if !re.MatchString(line) {
+				continue
+			}
+			if len(result.Matches) >= maxResults {
+				result.Truncated = true
+				return fs.SkipAll
+			}
+			m := SearchMatch{Path: rel, Line: i + 1, Text: line}
+			for j := max(0, i-req.ContextLines); j < i; j++ {
+				m.Before = append(m.Before, lines[j])
+			}
+			for j := i + 1; j <= i+req.ContextLines && j < len(lines); j++ {
+				m.After = append(m.After, lines[j])
+			}
+			result.Matches = append(result.Matches, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// matchGlob applies the request glob, gitignore-style: no slash means match
+// the base name.
+func matchGlob(glob, rel string) bool {
+	if glob == "" {
+		return true
+	}
+	target := rel
+	if !strings.Contains(glob, "/") {
+		target = path.Base(rel)
+	}
+	ok, err := path.Match(glob, target)
+	return err == nil && ok
+}
@@ -0,0 +1,103 @@
+package workspace
+
+import (
+	"testing"
+)
+
+func searchWorkspace(t *testing.T) *Service {
+	t.Helper()
+	s := newWorkspace(t)
+	files := map[string]string{
+		"main.go":        "package main\n\nfunc main() {\n\tprintln(\"TODO: fix\")\n}\n",
+		"lib/util.go":    "package lib\n// TODO: refactor\n",
+		"notes.md":       "plain notes\n",
+		"build/out.go":   "// TODO: generated, should be ignored\n",
+		".gitignore":     "build/\n*.md\n",
+		"vendor/dep.txt": "TODO in vendor\n",
+	}
+	for name, content := range files {
+		if _, err := s.Write(name, content, ""); err != nil {
+			t.Fatalf("Write %s: %v", name, err)
+		}
+	}
+	return s
+}
+
+func TestSearchRegexWithContext(t *testing.T) {
+	s := searchWorkspace(t)
+	res, err := s.Search(SearchRequest{Query: `TODO: \w+`, ContextLines: 1})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	paths := map[string]SearchMatch{}
+	for _, m := range res.Matches {
+		paths[m.Path] = m
+	}
+	if _, ok := paths["main.go"]; !ok {
+		t.Fatalf("matches = %+v, want main.go", res.Matches)
+	}
+	if _, ok := paths["lib/util.go"]; !ok {
+		t.Fatalf("matches = %+v, want lib/util.go", res.Matches)
+	}
+	m := paths["main.go"]
+	if m.Line != 4 || len(m.Before) != 1 || len(m.After) != 1 {
+		t.Fatalf("match = %+v, want line 4 with one context line each side", m)
+	}
+}
+
+func TestSearchHonorsGitignore(t *testing.T) {
+	s := searchWorkspace(t)
+	res, err := s.Search(SearchRequest{Query: "TODO"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	for _, m := range res.Matches {
+		if m.Path == "build/out.go" {
+			t.Fatalf("ignored directory searched: %+v", m)
+		}
+	}
+
+	res, err = s.Search(SearchRequest{Query: "notes"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(res.Matches) != 0 {
+		t.Fatalf("matches = %+v, want *.md ignored", res.Matches)
+	}
+}
+
+func TestSearchGlobAndLimits(t *testing.T) {
+	s := searchWorkspace(t)
+	res, err := s.Search(SearchRequest{Query: "TODO", Glob: "*.go"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	for _, m := range res.Matches {
+		if m.Path == "vendor/dep.txt" {
+			t.Fatalf("glob did not filter: %+v", m)
+		}
+	}
+
+	res, err = s.Search(SearchRequest{Query: "TODO", MaxResults: 1})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(res.Matches) != 1 || !res.Truncated {
+		t.Fatalf("result = %+v, want 1 truncated match", res)
+	}
+
+	res, err = s.Search(SearchRequest{Query: "todo", CaseInsensitive: true, Glob: "main.go"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(res.Matches) != 1 {
+		t.Fatalf("matches = %+v, want case-insensitive hit in main.go", res.Matches)
+	}
+
+	if _, err := s.Search(SearchRequest{Query: "("}); err == nil {
+		t.Fatal("invalid regex accepted")
+	}
+	if _, err := s.Search(SearchRequest{}); err == nil {
+		t.Fatal("empty query accepted")
+	}
+}
@@ -0,0 +1,307 @@
+// Package workspace gives the frontend structured file access under a
+// configured root, so routine file manipulation doesn't need generic shell
+// execution. Every path is resolved and confined to the root before use.
+package workspace
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultMaxReadBytes caps Read when the caller sets no limit.
+const defaultMaxReadBytes = 2 << 20 // 2 MiB
+
+// Service performs file operations confined to the workspace root.
+type Service struct {
+	mu   sync.RWMutex
+	root string
+}
+
+// NewService returns a workspace service with no root configured; every
+// operation fails until SetRoot is called.
+func NewService() *Service {
+	return &Service{}
+}
+
+// SetRoot points the workspace at an existing directory. An empty root
+// disables the workspace again.
+func (s *Service) SetRoot(root string) error {
+	if root == "" {
+		s.mu.Lock()
+		s.root = ""
+		s.mu.Unlock()
+		return nil
+	}
+	resolved, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return fmt.Errorf("workspace root: %w", err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return fmt.Errorf("workspace root: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("workspace root is not a directory: %s", root)
+	}
+	abs, err := filepath.Abs(resolved)
+	if err != nil {
+		return fmt.Errorf("workspace root: %w", err)
+	}
+	s.mu.Lock()
+	s.root = abs
+	s.mu.Unlock()
+	return nil
+}
+
+// Root reports the configured workspace root.
+func (s *Service) Root() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.root
+}
+
+// resolve turns a workspace-relative path into an absolute one, rejecting
+// anything that escapes the root. Lexical containment alone is not enough:
+// a symlink inside the workspace can point anywhere, so the path is also
+// resolved through its deepest existing ancestor and checked again.
+func (s *Service) resolve(rel string) (string, error) {
+	root := s.Root()
+	if root == "" {
+		return "", fmt.Errorf("no workspace root configured")
+	}
+	abs := filepath.Join(root, filepath.FromSlash(rel))
+	if !within(root, abs) {
+		return "", fmt.Errorf("path escapes workspace root: %s", rel)
+	}
+	resolved, err := resolveExisting(abs)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", rel, err)
+	}
+	if !within(root, resolved) {
+		return "", fmt.Errorf("path escapes workspace root: %s", rel)
+	}
+	return resolved, nil
+}
+
+// within reports whether abs stays under root, lexically.
+func within(root, abs string) bool {
+	between, err := filepath.Rel(root, abs)
+	return err == nil && between != ".." && !strings.HasPrefix(between, ".."+string(filepath.Separator))
+}
+
+// resolveExisting follows symlinks in the deepest existing ancestor of abs
+// and reattaches the not-yet-existing remainder, so paths about to be
+// created resolve too. A dangling symlink anywhere in the chain is an
+// error rather than a write through the link.
+func resolveExisting(abs string) (string, error) {
+	cur, suffix := abs, ""
+	for {
+		if _, err := os.Lstat(cur); err == nil {
+			resolved, err := filepath.EvalSymlinks(cur)
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(resolved, suffix), nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+		suffix = filepath.Join(filepath.Base(cur), suffix)
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return filepath.Join(cur, suffix), nil
+		}
+		cur = parent
+	}
+}
+
+// FileInfo describes one file or directory, with paths relative to the root.
+type FileInfo struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"modTime"`
+	IsDir   bool      `json:"isDir"`
+}
+
+func fileInfo(relPath string, info os.FileInfo) FileInfo {
+	return FileInfo{
+		Name:    info.Name(),
+		Path:    filepath.ToSlash(relPath),
+		Size:    info.Size(),
+		Mode:    info.Mode().String(),
+		ModTime: info.ModTime().UTC(),
+		IsDir:   info.IsDir(),
+	}
+}
+
+// ReadResult carries file content; binary files come back base64-encoded.
+type ReadResult struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// Encoding is "utf8" for text and "base64" for binary content.
+	Encoding  string `json:"encoding"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// isBinary mirrors the classic heuristic: a NUL byte or invalid UTF-8 in the
+// sample means binary.
+func isBinary(data []byte) bool {
+	sample := data
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	for _, b := range sample {
+		if b == 0 {
+			return true
+		}
+	}
+	return !utf8.Valid(sample)
+}
+
+// Read returns at most maxBytes of the file (0 means the 2 MiB default).
+func (s *Service) Read(rel string, maxBytes int64) (*ReadResult, error) {
+	abs, err := s.resolve(rel)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxReadBytes
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("is a directory: %s", rel)
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data := make([]byte, maxBytes)
+	n, err := io.ReadFull(f, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	data = data[:n]
+
+	res := &ReadResult{Path: filepath.ToSlash(rel), Size: info.Size(), Truncated: info.Size() > int64(n)}
+	if isBinary(data) {
+		res.Encoding = "base64"
+		res.Content = base64.StdEncoding.EncodeToString(data)
+	} else {
+		res.Encoding = "utf8"
+		res.Content = string(data)
+	}
+	return res, nil
+}
+
+// Write stores content at the path, creating parent directories. Encoding
+// "base64" lets binary payloads survive JSON transport.
+func (s *Service) Write(rel, content, encoding string) (*FileInfo, error) {
+	abs, err := s.resolve(rel)
+	if err != nil {
+		return nil, err
+	}
+	data := []byte(content)
+	if encoding == "base64" {
+		if data, err = base64.StdEncoding.DecodeString(content); err != nil {
+			return nil, fmt.Errorf("decoding base64 content: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(abs, data, 0o644); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, err
+	}
+	fi := fileInfo(rel, info)
+	return &fi, nil
+}
+
+// List returns the entries of a directory, directories first, then by name.
+func (s *Service) List(rel string) ([]FileInfo, error) {
+	abs, err := s.resolve(rel)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(abs)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FileInfo, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, fileInfo(filepath.Join(rel, e.Name()), info))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].IsDir != out[j].IsDir {
+			return out[i].IsDir
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+// Stat returns metadata for one path.
+func (s *Service) Stat(rel string) (*FileInfo, error) {
+	abs, err := s.resolve(rel)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, err
+	}
+	fi := fileInfo(rel, info)
+	return &fi, nil
+}
+
+// Move renames a file or directory; both ends stay inside the root.
+func (s *Service) Move(rel, to string) error {
+	from, err := s.resolve(rel)
+	if err != nil {
+		return err
+	}
+	dest, err := s.resolve(to)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(from, dest)
+}
+
+// Delete removes a file, or a whole directory when recursive is set.
+func (s *Service) Delete(rel string, recursive bool) error {
+	abs, err := s.resolve(rel)
+	if err != nil {
+		return err
+	}
+	if clean, _ := filepath.Rel(s.Root(), abs); clean == "." {
+		return fmt.Errorf("refusing to delete the workspace root")
+	}
+	if recursive {
+		return os.RemoveAll(abs)
+	}
+	return os.Remove(abs)
+}
@@ -0,0 +1,159 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newWorkspace(t *testing.T) *Service {
+	t.Helper()
+	s := NewService()
+	if err := s.SetRoot(t.TempDir()); err != nil {
+		t.Fatalf("SetRoot: %v", err)
+	}
+	return s
+}
+
+func TestReadWriteRoundTrip(t *testing.T) {
+	s := newWorkspace(t)
+	if _, err := s.Write("docs/notes.txt", "hello\n", ""); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	res, err := s.Read("docs/notes.txt", 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if res.Encoding != "utf8" || res.Content != "hello\n" || res.Truncated {
+		t.Fatalf("read = %+v", res)
+	}
+}
+
+func TestBinaryDetectionAndTruncation(t *testing.T) {
+	s := newWorkspace(t)
+	if _, err := s.Write("blob.bin", "AAEC/w==", "base64"); err != nil { // 00 01 02 ff
+		t.Fatalf("Write: %v", err)
+	}
+	res, err := s.Read("blob.bin", 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if res.Encoding != "base64" {
+		t.Fatalf("encoding = %q, want base64 for binary", res.Encoding)
+	}
+
+	if _, err := s.Write("big.txt", "0123456789", ""); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	res, err = s.Read("big.txt", 4)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if res.Content != "0123" || !res.Truncated || res.Size != 10 {
+		t.Fatalf("read = %+v, want 4 bytes truncated of 10", res)
+	}
+}
+
+func TestPathConfinement(t *testing.T) {
+	s := newWorkspace(t)
+	if _, err := s.Read("../outside.txt", 0); err == nil {
+		t.Fatal("escape via .. accepted")
+	}
+	if _, err := s.Write("../../etc/oops", "x", ""); err == nil {
+		t.Fatal("escape via .. accepted for write")
+	}
+	if err := s.Delete(".", true); err == nil {
+		t.Fatal("deleting the root accepted")
+	}
+
+	unset := NewService()
+	if _, err := unset.Read("anything", 0); err == nil {
+		t.Fatal("operation without a root accepted")
+	}
+}
+
+func TestSymlinkConfinement(t *testing.T) {
+	s := newWorkspace(t)
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("hidden"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(s.Root(), "escape")); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	if _, err := s.Read("escape/secret.txt", 0); err == nil {
+		t.Fatal("read through a symlink out of the root accepted")
+	}
+	if _, err := s.Write("escape/planted.txt", "x", ""); err == nil {
+		t.Fatal("write through a symlink out of the root accepted")
+	}
+	if err := s.Move("escape/secret.txt", "stolen.txt"); err == nil {
+		t.Fatal("move through a symlink out of the root accepted")
+	}
+	if err := s.Delete("escape/secret.txt", false); err == nil {
+		t.Fatal("delete through a symlink out of the root accepted")
+	}
+	if _, err := os.Stat(secret); err != nil {
+		t.Fatalf("outside file harmed: %v", err)
+	}
+
+	// A symlink staying inside the root keeps working.
+	if _, err := s.Write("docs/real.txt", "ok", ""); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(s.Root(), "docs"), filepath.Join(s.Root(), "alias")); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+	res, err := s.Read("alias/real.txt", 0)
+	if err != nil {
+		t.Fatalf("Read through internal symlink: %v", err)
+	}
+	if res.Content != "ok" {
+		t.Fatalf("content = %q", res.Content)
+	}
+}
+
+func TestListStatMoveDelete(t *testing.T) {
+	s := newWorkspace(t)
+	if _, err := s.Write("a/x.txt", "x", ""); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := s.Write("b.txt", "b", ""); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	entries, err := s.List(".")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 || !entries[0].IsDir || entries[0].Name != "a" || entries[1].Name != "b.txt" {
+		t.Fatalf("entries = %+v, want dir a then b.txt", entries)
+	}
+
+	info, err := s.Stat("a/x.txt")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size != 1 || info.IsDir {
+		t.Fatalf("stat = %+v", info)
+	}
+
+	if err := s.Move("a/x.txt", "moved.txt"); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if _, err := s.Stat("moved.txt"); err != nil {
+		t.Fatalf("Stat after move: %v", err)
+	}
+
+	if err := s.Delete("a", false); err != nil {
+		t.Fatalf("Delete empty dir: %v", err)
+	}
+	if err := s.Delete("moved.txt", false); err != nil {
+		t.Fatalf("Delete file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Root(), "moved.txt")); !os.IsNotExist(err) {
+		t.Fatal("file still present after delete")
+	}
+}